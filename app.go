@@ -3,6 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"ghostdraft/internal/data"
 	"ghostdraft/internal/lcu"
@@ -12,22 +15,46 @@ import (
 
 // App struct
 type App struct {
-	ctx              context.Context
-	lcuClient        *lcu.Client
-	wsClient         *lcu.WebSocketClient
-	liveClient       *lcu.LiveClient
-	champions        *lcu.ChampionRegistry
-	items            *lcu.ItemRegistry
-	championDB       *data.ChampionDB
-	tursoClient      *data.TursoClient     // Turso database connection
-	statsProvider    *data.StatsProvider   // Stats queries (uses Turso with caching)
-	stopPoll         chan struct{}
+	ctx                 context.Context
+	lcuClient           *lcu.Client
+	wsClient            *lcu.WebSocketClient
+	liveClient          *lcu.LiveClient
+	champions           *lcu.ChampionRegistry
+	items               *lcu.ItemRegistry
+	championDB          *data.ChampionDB
+	tursoClient         *data.TursoClient                  // Turso database connection
+	statsProvider       atomic.Pointer[data.StatsProvider] // Stats queries (uses Turso with caching)
+	stopPoll            chan struct{}
 	lastFetchedChamp    int
 	lastFetchedEnemy    int
 	lastBanFetchKey     string
 	lastItemFetchKey    string
 	lastCounterFetchKey string
+	lastEnemyFetchKey   string
+	refetchRequested    atomic.Bool // ClearAllCaches asked the champ select handler to reset the fetch keys
 	windowVisible       bool
+	emitAllMatchups     atomic.Bool                 // Include win rates vs every enemy in build:update
+	emitRawCounts       atomic.Bool                 // Include raw wins/matches/win rate alongside formatted stats
+	situationalOptions  atomic.Int32                // Options per 4th/5th/6th item slot (0 means default)
+	itemsByWinRate      atomic.Bool                 // Order build items by win rate instead of pick frequency
+	evenBand            atomic.Pointer[MatchupBand] // Win-rate band classified as even (nil means default)
+	minEnemies          atomic.Int32                // Enemy picks needed before guessing the lane opponent (0 means default)
+
+	// Stale-stats auto refresh
+	statsUpdatedAt        atomic.Int64                        // UnixNano of the last successful provider swap
+	statsMaxAge           atomic.Int64                        // Max stats age in nanoseconds (0 means default, <0 disables)
+	statsRefreshAttemptAt atomic.Int64                        // UnixNano of the last background refresh attempt
+	statsRefreshing       atomic.Bool                         // A background refresh is in flight
+	statsUpdating         atomic.Bool                         // UpdateStats is loading a replacement provider
+	statsUpdater          func() string                       // Refresh hook (nil means ForceStatsUpdate)
+	statsLoader           func() (*data.StatsProvider, error) // Builds a ready provider (nil means loadStatsProvider)
+	clock                 func() time.Time                    // Time source (nil means time.Now)
+
+	// Cancels in-flight per-enemy fetches when the selection changes
+	selectionMu     sync.Mutex
+	selectionCancel context.CancelFunc
+	selection       champSelection // Pick shown in champ select, for snapshots
+	historyPath     string         // Champ select snapshot file (empty means the user config dir)
 
 	// Champ select state - passed to in-game
 	lockedChampionID   int
@@ -78,16 +105,24 @@ func (a *App) startup(ctx context.Context) {
 	}
 
 	// Load data from Data Dragon in parallel
+	var ddragon sync.WaitGroup
+	ddragon.Add(2)
 	go func() {
+		defer ddragon.Done()
 		if err := a.champions.Load(); err != nil {
 			fmt.Printf("Failed to load champions: %v\n", err)
 		}
 	}()
 	go func() {
+		defer ddragon.Done()
 		if err := a.items.Load(); err != nil {
 			fmt.Printf("Failed to load items: %v\n", err)
 		}
 	}()
+	go func() {
+		ddragon.Wait()
+		a.checkDataDragonVersions()
+	}()
 
 	// Initialize stats database and check for updates
 	go a.initStats()
@@ -98,6 +133,9 @@ func (a *App) startup(ctx context.Context) {
 	// Set up gameflow handler
 	a.wsClient.SetGameflowHandler(a.onGameflowUpdate)
 
+	// Mirror LCU connection state to the UI
+	a.lcuClient.SetConnectionHandler(a.onLCUStateChange)
+
 	// Start polling for League Client
 	go a.pollForLeagueClient()
 
@@ -105,6 +143,15 @@ func (a *App) startup(ctx context.Context) {
 	a.RegisterToggleHotkey()
 }
 
+// checkDataDragonVersions warns when champion and item data came from different
+// Data Dragon versions, since icons would then be built against mismatched assets
+func (a *App) checkDataDragonVersions() {
+	champVersion, itemVersion := a.champions.GetVersion(), a.items.GetVersion()
+	if champVersion != "" && itemVersion != "" && champVersion != itemVersion {
+		fmt.Printf("Warning: Data Dragon version mismatch (champions v%s, items v%s)\n", champVersion, itemVersion)
+	}
+}
+
 // initStats initializes the Turso connection and stats provider
 func (a *App) initStats() {
 	// Connect to Turso
@@ -128,8 +175,9 @@ func (a *App) initStats() {
 		return
 	}
 
-	a.statsProvider = provider
+	a.setStatsProvider(provider)
 	fmt.Printf("Stats provider ready (patch %s)\n", provider.GetPatch())
+	a.emitMetaReady()
 }
 
 // shutdown is called when the app is closing
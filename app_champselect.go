@@ -3,20 +3,22 @@ package main
 import (
 	"fmt"
 
-	"ghostdraft/internal/data"
 	"ghostdraft/internal/lcu"
+	"ghostdraft/internal/roles"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // onChampSelectUpdate handles champ select state changes
 func (a *App) onChampSelectUpdate(session *lcu.ChampSelectSession, inChampSelect bool) {
+	// ClearAllCaches can't touch the fetch keys from its own goroutine, so they're dropped here
+	if a.refetchRequested.CompareAndSwap(true, false) {
+		a.resetFetchKeys()
+	}
 	if !inChampSelect {
-		a.lastFetchedChamp = 0
-		a.lastFetchedEnemy = 0
-		a.lastBanFetchKey = ""
-		a.lastItemFetchKey = ""
-		a.lastCounterFetchKey = ""
+		a.resetFetchKeys()
+		a.cancelSelectionFetches()
+		a.setCurrentSelection(champSelection{})
 		runtime.EventsEmit(a.ctx, "champselect:update", map[string]interface{}{
 			"inChampSelect": false,
 		})
@@ -32,6 +34,9 @@ func (a *App) onChampSelectUpdate(session *lcu.ChampSelectSession, inChampSelect
 		runtime.EventsEmit(a.ctx, "counterpicks:update", map[string]interface{}{
 			"hasData": false,
 		})
+		runtime.EventsEmit(a.ctx, "enemycounters:update", map[string]interface{}{
+			"hasData": false,
+		})
 		fmt.Println("Exited Champion Select")
 		return
 	}
@@ -163,15 +168,20 @@ func (a *App) onChampSelectUpdate(session *lcu.ChampSelectSession, inChampSelect
 	}
 
 	runtime.EventsEmit(a.ctx, "champselect:update", data)
+	a.setCurrentSelection(champSelection{championID: championID, role: localPosition, enemyIDs: enemyChampionIDs})
+
+	// Bans and picks so far, so suggestions skip champions that are off the table
+	unavailable := session.UnavailableChampions()
+	unavailableIDs := unavailableKey(unavailable)
 
 	// Show recommended bans whenever we have a champion + role
 	fmt.Printf("Ban check: championID=%d, localPosition='%s', lastBanFetchKey='%s'\n", championID, localPosition, a.lastBanFetchKey)
 	if championID > 0 && localPosition != "" {
-		banKey := fmt.Sprintf("%d-%s", championID, localPosition)
+		banKey := fmt.Sprintf("%d-%s-%s", championID, localPosition, unavailableIDs)
 		if banKey != a.lastBanFetchKey {
 			fmt.Printf("Triggering ban fetch for key: %s\n", banKey)
 			a.lastBanFetchKey = banKey
-			go a.fetchAndEmitRecommendedBans(championID, localPosition)
+			go a.fetchAndEmitRecommendedBans(championID, localPosition, unavailable)
 		} else {
 			fmt.Printf("Skipping ban fetch - same key: %s\n", banKey)
 		}
@@ -206,10 +216,10 @@ func (a *App) onChampSelectUpdate(session *lcu.ChampSelectSession, inChampSelect
 
 	// Fetch counter picks for enemy laner (after ban phase)
 	if enemyLanerID > 0 && localPosition != "" {
-		counterKey := fmt.Sprintf("counter-%d-%s", enemyLanerID, localPosition)
+		counterKey := fmt.Sprintf("counter-%d-%s-%s", enemyLanerID, localPosition, unavailableIDs)
 		if counterKey != a.lastCounterFetchKey {
 			a.lastCounterFetchKey = counterKey
-			go a.fetchAndEmitCounterPicks(enemyLanerID, localPosition)
+			go a.fetchAndEmitCounterPicks(enemyLanerID, localPosition, unavailable)
 		}
 	} else {
 		// No enemy laner visible yet
@@ -218,16 +228,36 @@ func (a *App) onChampSelectUpdate(session *lcu.ChampSelectSession, inChampSelect
 		})
 	}
 
+	// Fetch counters for every visible enemy when the enemy team changes
+	if len(enemyChampionIDs) > 0 && localPosition != "" {
+		enemyKey := fmt.Sprintf("%v-%s-%s", enemyChampionIDs, localPosition, unavailableIDs)
+		if enemyKey != a.lastEnemyFetchKey {
+			a.lastEnemyFetchKey = enemyKey
+			go a.fetchAndEmitEnemyCounters(a.newSelectionContext(), enemyChampionIDs, localPosition, unavailable)
+		}
+	}
+
 	// Fetch build data when champion changes or new enemies appear
 	if championID > 0 && championID != a.lastFetchedChamp {
 		a.lastFetchedChamp = championID
-		go a.fetchAndEmitBuild(championID, championName, localPosition, enemyChampionIDs)
+		go a.fetchAndEmitBuild(championID, championName, localPosition, enemyChampionIDs, enemyLanerID)
 	} else if len(enemyChampionIDs) > 0 && len(enemyChampionIDs) != a.lastFetchedEnemy {
 		a.lastFetchedEnemy = len(enemyChampionIDs)
-		go a.fetchAndEmitBuild(championID, championName, localPosition, enemyChampionIDs)
+		go a.fetchAndEmitBuild(championID, championName, localPosition, enemyChampionIDs, enemyLanerID)
 	}
 }
 
+// resetFetchKeys forgets which champ select lookups were already emitted, so the
+// next update fetches everything again. Only call it from the champ select handler.
+func (a *App) resetFetchKeys() {
+	a.lastFetchedChamp = 0
+	a.lastFetchedEnemy = 0
+	a.lastBanFetchKey = ""
+	a.lastItemFetchKey = ""
+	a.lastCounterFetchKey = ""
+	a.lastEnemyFetchKey = ""
+}
+
 // onGameflowUpdate handles gameflow phase changes
 func (a *App) onGameflowUpdate(phase string) {
 	fmt.Printf("Gameflow update: %s\n", phase)
@@ -251,6 +281,7 @@ func (a *App) onGameflowUpdate(phase string) {
 
 // fetchAndEmitInGameBuild fetches the build for the current in-game champion
 func (a *App) fetchAndEmitInGameBuild() {
+	stats := a.stats()
 	var championID int
 	var championName string
 	var role string
@@ -304,8 +335,8 @@ func (a *App) fetchAndEmitInGameBuild() {
 		championName = a.champions.GetName(championID)
 
 		// Get most played role for this champion from stats
-		if a.statsProvider != nil {
-			role = a.statsProvider.GetMostPlayedRole(championID)
+		if stats != nil {
+			role = stats.GetMostPlayedRole(championID)
 		}
 		if role == "" {
 			role = "middle" // Default fallback
@@ -321,7 +352,7 @@ func (a *App) fetchAndEmitInGameBuild() {
 	}
 
 	// Fetch build data from stats provider
-	if a.statsProvider == nil {
+	if stats == nil {
 		runtime.EventsEmit(a.ctx, "ingame:build", map[string]interface{}{
 			"hasBuild":     false,
 			"championName": championName,
@@ -331,12 +362,8 @@ func (a *App) fetchAndEmitInGameBuild() {
 		return
 	}
 
-	// Fetch item build using existing method
-	buildData, err := a.statsProvider.FetchChampionData(championID, championName, role)
-	if err != nil {
-		// Try without role filter
-		buildData, err = a.statsProvider.FetchChampionData(championID, championName, "")
-	}
+	// Fetch item build; falls back to the champion's most-played role if needed
+	buildData, err := stats.FetchChampionData(championID, championName, role)
 
 	if err != nil || len(buildData.Builds) == 0 {
 		runtime.EventsEmit(a.ctx, "ingame:build", map[string]interface{}{
@@ -348,42 +375,14 @@ func (a *App) fetchAndEmitInGameBuild() {
 		return
 	}
 
-	// Helper to convert item IDs to frontend format
-	convertItems := func(itemIDs []int) []map[string]interface{} {
-		var result []map[string]interface{}
-		for _, itemID := range itemIDs {
-			result = append(result, map[string]interface{}{
-				"id":      itemID,
-				"name":    a.items.GetName(itemID),
-				"iconURL": a.items.GetIconURL(itemID),
-			})
-		}
-		return result
-	}
-
-	// Helper to convert item options with win rates
-	convertItemOptions := func(options []data.ItemOption) []map[string]interface{} {
-		var result []map[string]interface{}
-		for _, opt := range options {
-			result = append(result, map[string]interface{}{
-				"id":      opt.ItemID,
-				"name":    a.items.GetName(opt.ItemID),
-				"iconURL": a.items.GetIconURL(opt.ItemID),
-				"winRate": opt.WinRate,
-				"games":   opt.Games,
-			})
-		}
-		return result
-	}
-
 	// Convert builds to frontend format
 	var builds []map[string]interface{}
 	for _, build := range buildData.Builds {
 		builds = append(builds, map[string]interface{}{
-			"coreItems":   convertItems(build.CoreItems),
-			"fourthItems": convertItemOptions(build.FourthItemOptions),
-			"fifthItems":  convertItemOptions(build.FifthItemOptions),
-			"sixthItems":  convertItemOptions(build.SixthItemOptions),
+			"coreItems":   a.itemEntries(build.CoreItems),
+			"fourthItems": a.itemOptionEntries(build.FourthItemOptions),
+			"fifthItems":  a.itemOptionEntries(build.FifthItemOptions),
+			"sixthItems":  a.itemOptionEntries(build.SixthItemOptions),
 		})
 	}
 
@@ -395,26 +394,14 @@ func (a *App) fetchAndEmitInGameBuild() {
 		"championID":   championID,
 		"championIcon": a.champions.GetIconURL(championID),
 		"role":         role,
+		"resolvedRole": buildData.ResolvedRole,
 		"builds":       builds,
 	})
 }
 
 // normalizePosition converts LCU position strings to our format
 func normalizePosition(position string) string {
-	switch position {
-	case "TOP":
-		return "top"
-	case "JUNGLE":
-		return "jungle"
-	case "MIDDLE", "MID":
-		return "middle"
-	case "BOTTOM", "ADC":
-		return "bottom"
-	case "UTILITY", "SUPPORT":
-		return "utility"
-	default:
-		return ""
-	}
+	return roles.Parse(position).String()
 }
 
 // PlayerStats represents calculated stats for a player
@@ -4,65 +4,70 @@ import (
 	"fmt"
 	"time"
 
+	"ghostdraft/internal/lcu"
+
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// pollForLeagueClient continuously checks for League Client
+// pollForLeagueClient continuously checks for League Client, reconnecting with backoff
 func (a *App) pollForLeagueClient() {
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
 
-	wasConnected := false
+	check := func() {
+		if a.lcuClient.MaintainConnection(time.Now()) && !a.wsClient.IsConnected() {
+			// Just connected, or HTTP connected but WebSocket dropped
+			a.connectWebSocket()
+		}
+	}
 
 	// Try immediately on startup
-	a.tryConnect()
-	if a.lcuClient.IsConnected() {
-		wasConnected = true
-		a.connectWebSocket()
-	}
+	check()
 
 	for {
 		select {
 		case <-a.stopPoll:
 			return
 		case <-ticker.C:
-			isConnected := a.lcuClient.IsConnected()
-
-			if isConnected && !wasConnected {
-				// Just connected
-				wasConnected = true
-				a.connectWebSocket()
-			} else if !isConnected {
-				// If we were connected before, emit disconnect event
-				if wasConnected {
-					a.wsClient.Disconnect()
-					runtime.EventsEmit(a.ctx, "lcu:status", map[string]interface{}{
-						"connected": false,
-						"message":   "League Disconnected. Waiting...",
-					})
-					runtime.EventsEmit(a.ctx, "champselect:update", map[string]interface{}{
-						"inChampSelect": false,
-					})
-					runtime.EventsEmit(a.ctx, "build:update", map[string]interface{}{
-						"hasBuild": false,
-					})
-					fmt.Println("League Disconnected. Waiting for reconnection...")
-					wasConnected = false
-				}
-				// Try to reconnect
-				a.tryConnect()
-				if a.lcuClient.IsConnected() {
-					wasConnected = true
-					a.connectWebSocket()
-				}
-			} else if isConnected && !a.wsClient.IsConnected() {
-				// HTTP connected but WebSocket disconnected, try to reconnect WS
-				a.connectWebSocket()
-			}
+			check()
 		}
 	}
 }
 
+// onLCUStateChange mirrors LCU connection transitions to the frontend status indicator
+func (a *App) onLCUStateChange(from, to lcu.ConnectionState) {
+	switch to {
+	case lcu.StateConnecting:
+		runtime.EventsEmit(a.ctx, "lcu:status", map[string]interface{}{
+			"connected": false,
+			"state":     string(to),
+			"message":   "Connecting to League...",
+		})
+
+	case lcu.StateConnected:
+		a.onLCUConnected()
+
+	case lcu.StateDisconnected:
+		message := "Waiting for League..."
+		if from == lcu.StateConnected {
+			message = "League Disconnected. Waiting..."
+			a.wsClient.Disconnect()
+			runtime.EventsEmit(a.ctx, "champselect:update", map[string]interface{}{
+				"inChampSelect": false,
+			})
+			runtime.EventsEmit(a.ctx, "build:update", map[string]interface{}{
+				"hasBuild": false,
+			})
+			fmt.Println("League Disconnected. Waiting for reconnection...")
+		}
+		runtime.EventsEmit(a.ctx, "lcu:status", map[string]interface{}{
+			"connected": false,
+			"state":     string(to),
+			"message":   message,
+		})
+	}
+}
+
 // connectWebSocket establishes WebSocket connection
 func (a *App) connectWebSocket() {
 	creds := a.lcuClient.GetCredentials()
@@ -82,20 +87,11 @@ func (a *App) connectWebSocket() {
 	go a.fetchInitialGameflow()
 }
 
-// tryConnect attempts to connect to the League Client
-func (a *App) tryConnect() {
-	err := a.lcuClient.Connect()
-	if err != nil {
-		runtime.EventsEmit(a.ctx, "lcu:status", map[string]interface{}{
-			"connected": false,
-			"message":   "Waiting for League...",
-		})
-		return
-	}
-
-	// Successfully connected
+// onLCUConnected announces a new LCU connection and records who is logged in
+func (a *App) onLCUConnected() {
 	runtime.EventsEmit(a.ctx, "lcu:status", map[string]interface{}{
 		"connected": true,
+		"state":     string(lcu.StateConnected),
 		"message":   "League Connected!",
 		"port":      a.lcuClient.GetPort(),
 	})
@@ -118,12 +114,14 @@ func (a *App) GetConnectionStatus() map[string]interface{} {
 	if a.lcuClient.IsConnected() {
 		return map[string]interface{}{
 			"connected": true,
+			"state":     string(lcu.StateConnected),
 			"message":   "League Connected!",
 			"port":      a.lcuClient.GetPort(),
 		}
 	}
 	return map[string]interface{}{
 		"connected": false,
+		"state":     string(a.lcuClient.State()),
 		"message":   "Waiting for League...",
 	}
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"ghostdraft/internal/data"
+)
+
+// LCUDiagnostic explains the current state of the League Client connection
+type LCUDiagnostic struct {
+	Connected     bool   `json:"connected"`
+	LockfileFound bool   `json:"lockfileFound"`
+	LoggedIn      bool   `json:"loggedIn"`
+	Reason        string `json:"reason"`
+}
+
+// lcuProbe is the subset of the LCU client used for diagnostics
+type lcuProbe interface {
+	HasLockfile() bool
+	IsConnected() bool
+	GetCurrentSummonerPUUID() (string, error)
+}
+
+// DiagnoseLCU checks the League Client connection and returns a human-readable reason
+func (a *App) DiagnoseLCU() LCUDiagnostic {
+	return diagnoseLCU(a.lcuClient)
+}
+
+// diagnoseLCU walks the connection steps in order and reports the first one that fails
+func diagnoseLCU(client lcuProbe) LCUDiagnostic {
+	var d LCUDiagnostic
+
+	d.LockfileFound = client.HasLockfile()
+	if !d.LockfileFound {
+		d.Reason = "League client is not running (lockfile not found)"
+		return d
+	}
+
+	d.Connected = client.IsConnected()
+	if !d.Connected {
+		d.Reason = "League client is running but not responding. It may still be starting up"
+		return d
+	}
+
+	puuid, err := client.GetCurrentSummonerPUUID()
+	if err != nil || puuid == "" {
+		d.Reason = "League client is connected but no summoner is logged in"
+		return d
+	}
+
+	d.LoggedIn = true
+	d.Reason = "Connected"
+	return d
+}
+
+// winRateDiscrepancyThreshold is the gap, in percentage points, between our win rate
+// and the reference site's that suggests a collection or aggregation bug
+const winRateDiscrepancyThreshold = 3.0
+
+// WinRateCheck compares a champion's win rate in our stats against an external reference
+type WinRateCheck struct {
+	ChampionID       int     `json:"championId"`
+	Role             string  `json:"role"`
+	WinRate          float64 `json:"winRate"` // ours, from champion_stats
+	Games            int     `json:"games"`
+	ReferenceWinRate float64 `json:"referenceWinRate"`
+	ReferenceGames   int     `json:"referenceGames"`
+	Delta            float64 `json:"delta"` // winRate - referenceWinRate
+	Discrepancy      bool    `json:"discrepancy"`
+	Error            string  `json:"error,omitempty"`
+}
+
+// winRateReference is an external stats site (e.g. U.GG) whose published win rates
+// our own aggregation is checked against
+type winRateReference interface {
+	FetchWinRate(championID int, role string) (winRate float64, games int, err error)
+}
+
+// roleWinRateSource is the part of the stats provider the win rate check reads
+type roleWinRateSource interface {
+	FetchRoleWinRate(championID int, role string) (*data.RoleWinRate, error)
+}
+
+// checkWinRateConsistency is a pipeline sanity check: it computes the delta between our
+// win rate and the reference's and flags a large gap. It isn't bound on App until the app
+// has a reference client to pass in.
+func checkWinRateConsistency(ours roleWinRateSource, reference winRateReference, championID int, role string) WinRateCheck {
+	result := WinRateCheck{ChampionID: championID, Role: role}
+	if ours == nil {
+		result.Error = "Stats provider not available"
+		return result
+	}
+	if reference == nil {
+		result.Error = "No reference win rate source configured"
+		return result
+	}
+
+	local, err := ours.FetchRoleWinRate(championID, role)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	refWinRate, refGames, err := reference.FetchWinRate(championID, role)
+	if err != nil {
+		result.Error = fmt.Sprintf("Reference win rate unavailable: %v", err)
+		return result
+	}
+	if local.Games == 0 || refGames == 0 {
+		result.Error = fmt.Sprintf("Not enough data (%d games, %d reference games)", local.Games, refGames)
+		return result
+	}
+
+	result.WinRate = local.WinRate
+	result.Games = local.Games
+	result.ReferenceWinRate = refWinRate
+	result.ReferenceGames = refGames
+	result.Delta = local.WinRate - refWinRate
+	result.Discrepancy = math.Abs(result.Delta) > winRateDiscrepancyThreshold
+	return result
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"ghostdraft/internal/data"
+)
+
+type stubLCU struct {
+	lockfile  bool
+	connected bool
+	puuid     string
+	err       error
+}
+
+func (s stubLCU) HasLockfile() bool                        { return s.lockfile }
+func (s stubLCU) IsConnected() bool                        { return s.connected }
+func (s stubLCU) GetCurrentSummonerPUUID() (string, error) { return s.puuid, s.err }
+
+func TestDiagnoseLCU_FailureModes(t *testing.T) {
+	tests := []struct {
+		name   string
+		client stubLCU
+		want   LCUDiagnostic
+	}{
+		{
+			name:   "client closed",
+			client: stubLCU{},
+			want:   LCUDiagnostic{Reason: "League client is not running (lockfile not found)"},
+		},
+		{
+			name:   "not responding",
+			client: stubLCU{lockfile: true},
+			want: LCUDiagnostic{
+				LockfileFound: true,
+				Reason:        "League client is running but not responding. It may still be starting up",
+			},
+		},
+		{
+			name:   "not logged in",
+			client: stubLCU{lockfile: true, connected: true, err: errors.New("unexpected status: 404")},
+			want: LCUDiagnostic{
+				LockfileFound: true,
+				Connected:     true,
+				Reason:        "League client is connected but no summoner is logged in",
+			},
+		},
+		{
+			name:   "connected",
+			client: stubLCU{lockfile: true, connected: true, puuid: "abc"},
+			want: LCUDiagnostic{
+				LockfileFound: true,
+				Connected:     true,
+				LoggedIn:      true,
+				Reason:        "Connected",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diagnoseLCU(tt.client); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+type stubRoleWinRate struct {
+	winRate float64
+	games   int
+}
+
+func (s stubRoleWinRate) FetchRoleWinRate(championID int, role string) (*data.RoleWinRate, error) {
+	return &data.RoleWinRate{ChampionID: championID, Games: s.games, WinRate: s.winRate}, nil
+}
+
+type stubWinRateReference struct {
+	winRate float64
+	games   int
+	err     error
+}
+
+func (s stubWinRateReference) FetchWinRate(championID int, role string) (float64, int, error) {
+	return s.winRate, s.games, s.err
+}
+
+func TestCheckWinRateConsistency_FlagsDivergence(t *testing.T) {
+	ours := stubRoleWinRate{winRate: 51.0, games: 1000}
+
+	got := checkWinRateConsistency(ours, stubWinRateReference{winRate: 50.0, games: 50000}, 103, "middle")
+	if got.Delta != 1.0 || got.Discrepancy || got.Error != "" {
+		t.Errorf("close sources: got %+v, want delta 1 and no discrepancy", got)
+	}
+
+	got = checkWinRateConsistency(ours, stubWinRateReference{winRate: 45.0, games: 50000}, 103, "middle")
+	if got.Delta != 6.0 || !got.Discrepancy {
+		t.Errorf("divergent sources: got %+v, want delta 6 flagged", got)
+	}
+	if got.ReferenceWinRate != 45.0 || got.ReferenceGames != 50000 || got.Games != 1000 {
+		t.Errorf("sample sizes not carried through: %+v", got)
+	}
+
+	if got := checkWinRateConsistency(ours, stubWinRateReference{err: errors.New("timeout")}, 103, "middle"); got.Error == "" || got.Discrepancy {
+		t.Errorf("reference failure: got %+v, want an error and no discrepancy", got)
+	}
+	if got := checkWinRateConsistency(ours, nil, 103, "middle"); got.Error == "" {
+		t.Error("missing reference should report an error")
+	}
+	if got := checkWinRateConsistency(nil, stubWinRateReference{winRate: 50.0, games: 1}, 103, "middle"); got.Error == "" {
+		t.Error("missing provider should report an error")
+	}
+}
@@ -1,99 +1,115 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"sort"
 
 	"ghostdraft/internal/data"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// fetchAndEmitBuild fetches matchup data from our database and emits it to frontend
-func (a *App) fetchAndEmitBuild(championID int, championName string, role string, enemyChampionIDs []int) {
+// defaultMinLaneEnemies is how many enemy picks must be visible before the lane
+// opponent is guessed from matchup games; with fewer the guess is mostly noise
+const defaultMinLaneEnemies = 3
+
+// fetchAndEmitBuild fetches matchup data from our database and emits it to frontend.
+// laneEnemyID is the enemy known to share our role, or 0 if positions are hidden.
+func (a *App) fetchAndEmitBuild(championID int, championName string, role string, enemyChampionIDs []int, laneEnemyID int) {
 	fmt.Printf("Fetching matchup for %s (%s) vs %d enemies...\n", championName, role, len(enemyChampionIDs))
 
+	var payload map[string]interface{}
+	if stats := a.stats(); stats != nil {
+		payload = a.buildUpdate(stats, championID, championName, role, enemyChampionIDs, laneEnemyID)
+	} else {
+		payload = a.buildUpdate(nil, championID, championName, role, enemyChampionIDs, laneEnemyID)
+	}
+	runtime.EventsEmit(a.ctx, "build:update", payload)
+}
+
+// buildUpdate computes the build:update payload for the lane matchup
+func (a *App) buildUpdate(stats champSelectStats, championID int, championName string, role string, enemyChampionIDs []int, laneEnemyID int) map[string]interface{} {
 	patch := ""
-	if a.statsProvider != nil {
-		patch = a.statsProvider.GetPatch()
+	if stats != nil {
+		patch = stats.GetPatch()
 	}
 
 	if len(enemyChampionIDs) == 0 {
-		runtime.EventsEmit(a.ctx, "build:update", map[string]interface{}{
+		fmt.Printf("No enemies detected yet for %s\n", championName)
+		return map[string]interface{}{
 			"hasBuild":     true,
 			"championName": championName,
 			"role":         role,
 			"winRate":      "-",
 			"winRateLabel": "Waiting for enemy...",
 			"patch":        patch,
-		})
-		fmt.Printf("No enemies detected yet for %s\n", championName)
-		return
+		}
 	}
 
-	if a.statsProvider == nil {
-		runtime.EventsEmit(a.ctx, "build:update", map[string]interface{}{
+	// Without a known same-role enemy, wait for enough picks to make the guess meaningful
+	minEnemies := a.minLaneEnemies()
+	if laneEnemyID == 0 && len(enemyChampionIDs) < minEnemies {
+		fmt.Printf("Awaiting more enemy picks for %s (%d/%d)\n", championName, len(enemyChampionIDs), minEnemies)
+		return map[string]interface{}{
+			"hasBuild":      true,
+			"championName":  championName,
+			"role":          role,
+			"winRate":       "-",
+			"winRateLabel":  fmt.Sprintf("Awaiting more picks (%d/%d)", len(enemyChampionIDs), minEnemies),
+			"awaitingPicks": true,
+			"patch":         patch,
+		}
+	}
+
+	if stats == nil {
+		fmt.Println("Stats provider not available for matchups")
+		return map[string]interface{}{
 			"hasBuild": false,
 			"error":    "Stats provider not available",
-		})
-		fmt.Println("Stats provider not available for matchups")
-		return
+		}
 	}
 
 	// Fetch our matchups - this gives us all enemies we face in our role
-	matchups, err := a.statsProvider.FetchAllMatchups(championID, role)
+	matchups, err := stats.FetchAllMatchups(championID, role)
 	if err != nil {
-		runtime.EventsEmit(a.ctx, "build:update", map[string]interface{}{
+		fmt.Printf("Failed to fetch matchups: %v\n", err)
+		return map[string]interface{}{
 			"hasBuild": false,
 			"error":    err.Error(),
-		})
-		fmt.Printf("Failed to fetch matchups: %v\n", err)
-		return
+		}
 	}
 
-	// Find enemy with highest game count in matchup data (likely lane opponent)
-	var laneOpponentID int
-	var matchupWR float64
-	var matchupGames int
-	for _, enemyID := range enemyChampionIDs {
-		for _, m := range matchups {
-			if m.EnemyChampionID == enemyID && m.Matches > matchupGames {
-				laneOpponentID = enemyID
-				matchupWR = m.WinRate
-				matchupGames = m.Matches
-			}
-		}
+	// Use the same-role enemy if known, otherwise the enemy with the most games in our matchup data
+	candidates := enemyChampionIDs
+	if laneEnemyID > 0 {
+		candidates = []int{laneEnemyID}
 	}
+	laneOpponent := findLaneOpponent(candidates, matchups)
+	laneOpponentID, matchupWR, matchupGames := laneOpponent.EnemyChampionID, laneOpponent.WinRate, laneOpponent.Matches
 	if laneOpponentID > 0 {
 		fmt.Printf("Lane opponent (highest games): %d (%.1f%% WR, %d games)\n", laneOpponentID, matchupWR, matchupGames)
 	}
 
 	if laneOpponentID == 0 {
-		runtime.EventsEmit(a.ctx, "build:update", map[string]interface{}{
+		fmt.Printf("No lane opponent found in matchup data for %s\n", championName)
+		return map[string]interface{}{
 			"hasBuild":     true,
 			"championName": championName,
 			"role":         role,
 			"winRate":      "-",
 			"winRateLabel": "No lane opponent found",
 			"patch":        patch,
-		})
-		fmt.Printf("No lane opponent found in matchup data for %s\n", championName)
-		return
+		}
 	}
 
 	enemyName := a.champions.GetName(laneOpponentID)
 
-	// Determine matchup status: winning (>51%), losing (<49%), even (49-51%)
-	var matchupStatus string
-	if matchupWR >= 51.0 {
-		matchupStatus = "winning"
-	} else if matchupWR <= 49.0 {
-		matchupStatus = "losing"
-	} else {
-		matchupStatus = "even"
-	}
+	// Determine matchup status: winning (>=upper), losing (<=lower), even in between
+	matchupStatus := a.matchupBand().classify(matchupWR)
 
 	fmt.Printf("Matchup: %s vs %s = %.1f%% (%s, %d games)\n", championName, enemyName, matchupWR, matchupStatus, matchupGames)
-	runtime.EventsEmit(a.ctx, "build:update", map[string]interface{}{
+	payload := map[string]interface{}{
 		"hasBuild":      true,
 		"championName":  championName,
 		"role":          role,
@@ -102,15 +118,159 @@ func (a *App) fetchAndEmitBuild(championID int, championName string, role string
 		"enemyName":     enemyName,
 		"matchupStatus": matchupStatus,
 		"patch":         patch,
-	})
+	}
+	a.withRawCounts(payload, laneOpponent.Wins, laneOpponent.Matches, laneOpponent.WinRate)
+	if a.emitAllMatchups.Load() {
+		payload["allMatchups"] = buildMatchupMap(enemyChampionIDs, matchups)
+	}
+	return payload
+}
+
+// SetMinLaneEnemies sets how many enemy picks are needed before the lane opponent is
+// guessed. Values <= 0 restore the default.
+func (a *App) SetMinLaneEnemies(count int) {
+	if count < 0 {
+		count = 0
+	}
+	a.minEnemies.Store(int32(count))
+}
+
+// minLaneEnemies returns the configured minimum enemy picks, or the default if unset
+func (a *App) minLaneEnemies() int {
+	if n := a.minEnemies.Load(); n > 0 {
+		return int(n)
+	}
+	return defaultMinLaneEnemies
+}
+
+// SetEmitRawCounts toggles including raw wins, matches and the unformatted win rate
+// in stat events, so the frontend can do its own formatting and confidence math.
+// Typed results such as MetaChampion always carry their counts.
+func (a *App) SetEmitRawCounts(enabled bool) {
+	a.emitRawCounts.Store(enabled)
+}
+
+// withRawCounts adds wins, matches and the numeric win rate to a stat event entry
+// when raw counts are enabled
+func (a *App) withRawCounts(entry map[string]interface{}, wins, matches int, winRate float64) map[string]interface{} {
+	if a.emitRawCounts.Load() {
+		entry["wins"] = wins
+		entry["matches"] = matches
+		entry["winRateValue"] = winRate
+	}
+	return entry
+}
+
+// SetEmitAllMatchups toggles including win rates vs every enemy pick in build:update
+func (a *App) SetEmitAllMatchups(enabled bool) {
+	a.emitAllMatchups.Store(enabled)
+}
+
+// MatchupBand bounds the win rates treated as an even matchup
+type MatchupBand struct {
+	Lower float64
+	Upper float64
+}
+
+// DefaultMatchupBand treats 49-51% as even
+var DefaultMatchupBand = MatchupBand{Lower: 49.0, Upper: 51.0}
+
+// classify labels a win rate as winning, losing, or even relative to the band
+func (b MatchupBand) classify(winRate float64) string {
+	switch {
+	case winRate >= b.Upper:
+		return "winning"
+	case winRate <= b.Lower:
+		return "losing"
+	default:
+		return "even"
+	}
+}
+
+// SetMatchupBand sets the win-rate band treated as an even matchup
+func (a *App) SetMatchupBand(lower, upper float64) {
+	if lower > upper {
+		lower, upper = upper, lower
+	}
+	a.evenBand.Store(&MatchupBand{Lower: lower, Upper: upper})
+}
+
+// matchupBand returns the configured even band, or the default if unset
+func (a *App) matchupBand() MatchupBand {
+	if band := a.evenBand.Load(); band != nil {
+		return *band
+	}
+	return DefaultMatchupBand
+}
+
+// findLaneOpponent returns the enemy with the most games in the matchup data, indexing matchups once
+func findLaneOpponent(enemyChampionIDs []int, matchups []data.MatchupStat) data.MatchupStat {
+	byEnemy := make(map[int]data.MatchupStat, len(matchups))
+	for _, m := range matchups {
+		if existing, ok := byEnemy[m.EnemyChampionID]; !ok || m.Matches > existing.Matches {
+			byEnemy[m.EnemyChampionID] = m
+		}
+	}
+
+	var best data.MatchupStat
+	for _, id := range enemyChampionIDs {
+		if m, ok := byEnemy[id]; ok && m.Matches > best.Matches {
+			best = m
+		}
+	}
+	return best
+}
+
+// buildMatchupMap returns enemyChampionID -> win rate for each enemy present in the matchup data
+func buildMatchupMap(enemyChampionIDs []int, matchups []data.MatchupStat) map[int]float64 {
+	byEnemy := make(map[int]float64, len(matchups))
+	for _, m := range matchups {
+		byEnemy[m.EnemyChampionID] = m.WinRate
+	}
+
+	result := make(map[int]float64, len(enemyChampionIDs))
+	for _, enemyID := range enemyChampionIDs {
+		if wr, ok := byEnemy[enemyID]; ok {
+			result[enemyID] = wr
+		}
+	}
+	return result
+}
+
+// withoutUnavailable drops matchups against champions that are banned or already picked
+func withoutUnavailable(matchups []data.MatchupStat, unavailable map[int]bool, limit int) []data.MatchupStat {
+	var result []data.MatchupStat
+	for _, m := range matchups {
+		if unavailable[m.EnemyChampionID] {
+			continue
+		}
+		if len(result) == limit {
+			break
+		}
+		result = append(result, m)
+	}
+	return result
 }
 
-// fetchAndEmitCounterPicks fetches champions that counter the enemy laner
-func (a *App) fetchAndEmitCounterPicks(enemyChampionID int, role string) {
+// unavailableKey identifies a set of unavailable champions by its sorted IDs, so a
+// swapped ban or pick changes the key even when the count stays the same
+func unavailableKey(unavailable map[int]bool) string {
+	ids := make([]int, 0, len(unavailable))
+	for id := range unavailable {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return fmt.Sprint(ids)
+}
+
+// fetchAndEmitCounterPicks fetches champions that counter the enemy laner, skipping
+// champions in unavailable
+func (a *App) fetchAndEmitCounterPicks(enemyChampionID int, role string, unavailable map[int]bool) {
+	stats := a.stats()
 	enemyName := a.champions.GetName(enemyChampionID)
 	fmt.Printf("Fetching counter picks vs %s (%s)...\n", enemyName, role)
 
-	if a.statsProvider == nil {
+	if stats == nil {
 		fmt.Println("Stats provider not available for counter picks")
 		runtime.EventsEmit(a.ctx, "counterpicks:update", map[string]interface{}{
 			"hasData": false,
@@ -118,7 +278,9 @@ func (a *App) fetchAndEmitCounterPicks(enemyChampionID int, role string) {
 		return
 	}
 
-	counterPicks, err := a.statsProvider.FetchCounterPicks(enemyChampionID, role, 6)
+	// Over-fetch so filtering out unavailable champions still leaves a full list
+	counterPicks, err := stats.FetchCounterPicks(enemyChampionID, role, data.DefaultCounterPickMinGames, 6+len(unavailable))
+	counterPicks = withoutUnavailable(counterPicks, unavailable, 6)
 	if err != nil || len(counterPicks) == 0 {
 		fmt.Printf("No counter pick data vs %s: %v\n", enemyName, err)
 		runtime.EventsEmit(a.ctx, "counterpicks:update", map[string]interface{}{
@@ -134,13 +296,13 @@ func (a *App) fetchAndEmitCounterPicks(enemyChampionID int, role string) {
 	var pickList []map[string]interface{}
 	for _, m := range counterPicks {
 		champName := a.champions.GetName(m.EnemyChampionID)
-		pickList = append(pickList, map[string]interface{}{
+		pickList = append(pickList, a.withRawCounts(map[string]interface{}{
 			"championID":   m.EnemyChampionID,
 			"championName": champName,
 			"iconURL":      a.champions.GetIconURL(m.EnemyChampionID),
 			"winRate":      m.WinRate,
 			"games":        m.Matches,
-		})
+		}, m.Wins, m.Matches, m.WinRate))
 	}
 
 	fmt.Printf("Counter picks vs %s: ", enemyName)
@@ -157,13 +319,61 @@ func (a *App) fetchAndEmitCounterPicks(enemyChampionID int, role string) {
 	})
 }
 
-// fetchAndEmitRecommendedBans fetches hardest counters and emits as recommended bans
-func (a *App) fetchAndEmitRecommendedBans(championID int, role string) {
+// fetchAndEmitEnemyCounters fetches counter picks for each enemy in parallel and emits them
+// together, skipping champions in unavailable
+func (a *App) fetchAndEmitEnemyCounters(ctx context.Context, enemyChampionIDs []int, role string, unavailable map[int]bool) {
+	stats := a.stats()
+	if stats == nil {
+		return
+	}
+
+	results, err := fetchConcurrently(ctx, maxConcurrentFetches, enemyChampionIDs, func(ctx context.Context, enemyID int) ([]data.MatchupStat, error) {
+		picks, err := stats.FetchCounterPicks(enemyID, role, data.DefaultCounterPickMinGames, 3+len(unavailable))
+		return withoutUnavailable(picks, unavailable, 3), err
+	})
+	if err != nil {
+		// Selection changed while fetching - a newer fetch will emit
+		return
+	}
+
+	enemies := make([]map[string]interface{}, 0, len(enemyChampionIDs))
+	for _, enemyID := range enemyChampionIDs {
+		var counters []map[string]interface{}
+		for _, cp := range results[enemyID] {
+			counters = append(counters, a.withRawCounts(map[string]interface{}{
+				"championID":   cp.EnemyChampionID,
+				"championName": a.champions.GetName(cp.EnemyChampionID),
+				"iconURL":      a.champions.GetIconURL(cp.EnemyChampionID),
+				"winRate":      cp.WinRate,
+				"games":        cp.Matches,
+			}, cp.Wins, cp.Matches, cp.WinRate))
+		}
+		enemies = append(enemies, map[string]interface{}{
+			"championID":   enemyID,
+			"championName": a.champions.GetName(enemyID),
+			"counters":     counters,
+		})
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+	runtime.EventsEmit(a.ctx, "enemycounters:update", map[string]interface{}{
+		"hasData": true,
+		"role":    role,
+		"enemies": enemies,
+	})
+}
+
+// fetchAndEmitRecommendedBans fetches hardest counters and emits as recommended bans,
+// skipping champions that are already banned or picked
+func (a *App) fetchAndEmitRecommendedBans(championID int, role string, unavailable map[int]bool) {
+	stats := a.stats()
 	championName := a.champions.GetName(championID)
 	fmt.Printf("Fetching recommended bans for %s (%s)...\n", championName, role)
 
 	// Use our stats provider for counter matchups
-	if a.statsProvider == nil {
+	if stats == nil {
 		fmt.Println("Stats provider not available for bans")
 		runtime.EventsEmit(a.ctx, "bans:update", map[string]interface{}{
 			"hasBans":      true,
@@ -175,7 +385,8 @@ func (a *App) fetchAndEmitRecommendedBans(championID int, role string) {
 		return
 	}
 
-	matchups, err := a.statsProvider.FetchCounterMatchups(championID, role, 5)
+	matchups, err := stats.FetchCounterMatchups(championID, role, 5+len(unavailable))
+	matchups = withoutUnavailable(matchups, unavailable, 5)
 	if err != nil || len(matchups) == 0 {
 		fmt.Printf("No matchup data for %s %s: %v\n", championName, role, err)
 		runtime.EventsEmit(a.ctx, "bans:update", map[string]interface{}{
@@ -188,23 +399,7 @@ func (a *App) fetchAndEmitRecommendedBans(championID int, role string) {
 		return
 	}
 
-	// Convert to frontend format
-	var banList []map[string]interface{}
-	for _, m := range matchups {
-		enemyName := a.champions.GetName(m.EnemyChampionID)
-		damageType := "Unknown"
-		if a.championDB != nil {
-			damageType = a.championDB.GetDamageType(enemyName)
-		}
-		banList = append(banList, map[string]interface{}{
-			"championID":   m.EnemyChampionID,
-			"championName": enemyName,
-			"iconURL":      a.champions.GetIconURL(m.EnemyChampionID),
-			"damageType":   damageType,
-			"winRate":      m.WinRate,
-			"games":        m.Matches,
-		})
-	}
+	banList := a.buildBanList(matchups)
 
 	fmt.Printf("Counter matchups for %s: ", championName)
 	for _, b := range banList {
@@ -220,11 +415,50 @@ func (a *App) fetchAndEmitRecommendedBans(championID int, role string) {
 	})
 }
 
+// buildBanList converts counter matchups to the bans:update frontend format
+func (a *App) buildBanList(matchups []data.MatchupStat) []map[string]interface{} {
+	var banList []map[string]interface{}
+	for _, m := range matchups {
+		enemyName := a.champions.GetName(m.EnemyChampionID)
+		banList = append(banList, a.withRawCounts(map[string]interface{}{
+			"championID":   m.EnemyChampionID,
+			"championName": enemyName,
+			"iconURL":      a.champions.GetIconURL(m.EnemyChampionID),
+			"damageType":   a.getDamageType(enemyName),
+			"winRate":      m.WinRate,
+			"games":        m.Matches,
+		}, m.Wins, m.Matches, m.WinRate))
+	}
+	return banList
+}
+
+// unknownDamageType is reported for champions the champion DB doesn't know, or when
+// there is no champion DB
+const unknownDamageType = "Unknown"
+
+// getDamageType returns a champion's damage type, or "Unknown" if the champion DB is unavailable
+func (a *App) getDamageType(championName string) string {
+	if a.championDB == nil {
+		return unknownDamageType
+	}
+	return a.championDB.GetDamageType(championName)
+}
+
+// getRoleTags returns a champion's comma-separated role tags, or "" if the champion DB
+// is unavailable
+func (a *App) getRoleTags(championName string) string {
+	if a.championDB == nil {
+		return ""
+	}
+	return a.championDB.GetRoleTags(championName)
+}
+
 // fetchAndEmitItems fetches item build from our stats database and emits to frontend
 func (a *App) fetchAndEmitItems(championID int, championName string, role string) {
+	stats := a.stats()
 	fmt.Printf("Fetching items for %s (%s)...\n", championName, role)
 
-	if a.statsProvider == nil {
+	if stats == nil {
 		fmt.Println("Stats provider not available")
 		runtime.EventsEmit(a.ctx, "items:update", map[string]interface{}{
 			"hasItems": false,
@@ -232,7 +466,7 @@ func (a *App) fetchAndEmitItems(championID int, championName string, role string
 		return
 	}
 
-	buildData, err := a.statsProvider.FetchChampionData(championID, championName, role)
+	buildData, err := stats.FetchChampionData(championID, championName, role)
 	if err != nil {
 		fmt.Printf("No data for %s: %v\n", championName, err)
 		runtime.EventsEmit(a.ctx, "items:update", map[string]interface{}{
@@ -241,61 +475,73 @@ func (a *App) fetchAndEmitItems(championID int, championName string, role string
 		return
 	}
 
-	// Helper to convert item IDs to frontend format
-	convertItems := func(itemIDs []int) []map[string]interface{} {
-		var result []map[string]interface{}
-		for _, itemID := range itemIDs {
-			result = append(result, map[string]interface{}{
-				"id":      itemID,
-				"name":    a.items.GetName(itemID),
-				"iconURL": a.items.GetIconURL(itemID),
-			})
-		}
-		return result
-	}
-
-	// Helper to convert item options with win rates
-	convertItemOptions := func(options []data.ItemOption) []map[string]interface{} {
-		var result []map[string]interface{}
-		for _, opt := range options {
-			result = append(result, map[string]interface{}{
-				"id":      opt.ItemID,
-				"name":    a.items.GetName(opt.ItemID),
-				"iconURL": a.items.GetIconURL(opt.ItemID),
-				"winRate": opt.WinRate,
-				"games":   opt.Games,
-			})
-		}
-		return result
-	}
+	builds := a.buildPathEntries(buildData.Builds)
+
+	fmt.Printf("Found %d build paths for %s\n", len(builds), championName)
+
+	runtime.EventsEmit(a.ctx, "items:update", map[string]interface{}{
+		"hasItems":     true,
+		"championName": championName,
+		"role":         role,
+		"resolvedRole": buildData.ResolvedRole,
+		"builds":       builds,
+	})
+}
 
-	// Convert all build paths
+// buildPathEntries converts build paths to the items:update format
+func (a *App) buildPathEntries(paths []data.BuildPath) []map[string]interface{} {
 	var builds []map[string]interface{}
-	for _, build := range buildData.Builds {
+	for _, build := range paths {
 		// Name the build after the first core item
 		buildName := "Build"
 		if len(build.CoreItems) > 0 {
 			buildName = a.items.GetName(build.CoreItems[0])
 		}
 
-		builds = append(builds, map[string]interface{}{
+		entry := map[string]interface{}{
 			"name":          buildName,
 			"winRate":       build.WinRate,
 			"games":         build.Games,
-			"startingItems": convertItems(build.StartingItems),
-			"coreItems":     convertItems(build.CoreItems),
-			"fourthItems":   convertItemOptions(build.FourthItemOptions),
-			"fifthItems":    convertItemOptions(build.FifthItemOptions),
-			"sixthItems":    convertItemOptions(build.SixthItemOptions),
-		})
+			"startingItems": a.itemEntries(build.StartingItems),
+			"coreItems":     a.itemEntries(build.CoreItems),
+			"fourthItems":   a.itemOptionEntries(build.FourthItemOptions),
+			"fifthItems":    a.itemOptionEntries(build.FifthItemOptions),
+			"sixthItems":    a.itemOptionEntries(build.SixthItemOptions),
+		}
+		// The build's win rate is its first core item's, so the raw counts are too
+		if len(build.CoreItemStats) > 0 {
+			first := build.CoreItemStats[0]
+			a.withRawCounts(entry, first.Wins, first.Games, first.WinRate)
+		}
+		builds = append(builds, entry)
 	}
+	return builds
+}
 
-	fmt.Printf("Found %d build paths for %s\n", len(builds), championName)
+// itemEntries converts item IDs to frontend format
+func (a *App) itemEntries(itemIDs []int) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, itemID := range itemIDs {
+		result = append(result, map[string]interface{}{
+			"id":      itemID,
+			"name":    a.items.GetName(itemID),
+			"iconURL": a.items.GetIconURL(itemID),
+		})
+	}
+	return result
+}
 
-	runtime.EventsEmit(a.ctx, "items:update", map[string]interface{}{
-		"hasItems":     true,
-		"championName": championName,
-		"role":         role,
-		"builds":       builds,
-	})
+// itemOptionEntries converts item options with win rates to frontend format
+func (a *App) itemOptionEntries(options []data.ItemOption) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, opt := range options {
+		result = append(result, a.withRawCounts(map[string]interface{}{
+			"id":      opt.ItemID,
+			"name":    a.items.GetName(opt.ItemID),
+			"iconURL": a.items.GetIconURL(opt.ItemID),
+			"winRate": opt.WinRate,
+			"games":   opt.Games,
+		}, opt.Wins, opt.Games, opt.WinRate))
+	}
+	return result
 }
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"ghostdraft/internal/data"
+	"ghostdraft/internal/lcu"
+)
+
+func TestBuildMatchupMap_IncludesAllEnemiesWithData(t *testing.T) {
+	matchups := []data.MatchupStat{
+		{EnemyChampionID: 1, Wins: 55, Matches: 100, WinRate: 55.0},
+		{EnemyChampionID: 2, Wins: 45, Matches: 100, WinRate: 45.0},
+		{EnemyChampionID: 3, Wins: 10, Matches: 20, WinRate: 50.0},
+		{EnemyChampionID: 99, Wins: 60, Matches: 100, WinRate: 60.0}, // not in this lobby
+	}
+	enemies := []int{1, 2, 3, 4} // 4 has no matchup data
+
+	got := buildMatchupMap(enemies, matchups)
+
+	want := map[int]float64{1: 55.0, 2: 45.0, 3: 50.0}
+	if len(got) != len(want) {
+		t.Fatalf("got %d matchups, want %d: %v", len(got), len(want), got)
+	}
+	for id, wr := range want {
+		if got[id] != wr {
+			t.Errorf("enemy %d: got %.1f, want %.1f", id, got[id], wr)
+		}
+	}
+	if _, ok := got[4]; ok {
+		t.Error("enemy without matchup data should be omitted")
+	}
+	if _, ok := got[99]; ok {
+		t.Error("champion not in enemy team should be omitted")
+	}
+}
+
+func TestBuildBanList_NilChampionDB(t *testing.T) {
+	app := &App{champions: lcu.NewChampionRegistry()} // championDB left nil
+
+	bans := app.buildBanList([]data.MatchupStat{
+		{EnemyChampionID: 238, Wins: 40, Matches: 100, WinRate: 40.0},
+		{EnemyChampionID: 7, Wins: 45, Matches: 100, WinRate: 45.0},
+	})
+
+	if len(bans) != 2 {
+		t.Fatalf("got %d bans, want 2", len(bans))
+	}
+	for _, b := range bans {
+		if b["damageType"] != "Unknown" {
+			t.Errorf("ban %v: damageType = %v, want Unknown", b["championID"], b["damageType"])
+		}
+	}
+	if bans[0]["championID"] != 238 || bans[0]["winRate"] != 40.0 {
+		t.Errorf("unexpected first ban: %v", bans[0])
+	}
+}
+
+func TestAnalyzeTeamTags_NilChampionDB(t *testing.T) {
+	app := &App{champions: lcu.NewChampionRegistry()} // championDB left nil
+
+	comp := app.analyzeTeamTags([]lcu.ChampSelectPlayer{{ChampionID: 103}, {ChampionID: 238}, {ChampionID: 0}})
+	if comp.Known != 0 || comp.AP != 0 || comp.AD != 0 || len(comp.Tags) != 0 {
+		t.Errorf("got %+v, want an empty comp without a champion DB", comp)
+	}
+	if tags := app.getRoleTags("Zed"); tags != "" {
+		t.Errorf("getRoleTags = %q, want empty without a champion DB", tags)
+	}
+}
+
+func TestFindLaneOpponent_MatchesNestedScan(t *testing.T) {
+	matchups := []data.MatchupStat{
+		{EnemyChampionID: 238, Wins: 120, Matches: 250, WinRate: 48.0},
+		{EnemyChampionID: 7, Wins: 300, Matches: 600, WinRate: 50.0},
+		{EnemyChampionID: 103, Wins: 330, Matches: 600, WinRate: 55.0}, // ties 7 on games
+		{EnemyChampionID: 91, Wins: 510, Matches: 1000, WinRate: 51.0}, // not in this lobby
+		{EnemyChampionID: 55, Wins: 20, Matches: 40, WinRate: 50.0},
+	}
+	lobbies := [][]int{
+		{238, 7, 103, 55, 1},
+		{103, 7, 238},
+		{55, 1},
+		{1, 2, 3},
+		{},
+	}
+
+	for _, enemies := range lobbies {
+		// Reference: the original nested scan over every matchup per enemy
+		var wantID, wantGames int
+		var wantWR float64
+		for _, enemyID := range enemies {
+			for _, m := range matchups {
+				if m.EnemyChampionID == enemyID && m.Matches > wantGames {
+					wantID, wantWR, wantGames = enemyID, m.WinRate, m.Matches
+				}
+			}
+		}
+
+		got := findLaneOpponent(enemies, matchups)
+		gotID, gotWR, gotGames := got.EnemyChampionID, got.WinRate, got.Matches
+		if gotID != wantID || gotWR != wantWR || gotGames != wantGames {
+			t.Errorf("enemies %v: got (%d, %.1f, %d), want (%d, %.1f, %d)",
+				enemies, gotID, gotWR, gotGames, wantID, wantWR, wantGames)
+		}
+	}
+}
+
+func TestMatchupBand_Classify(t *testing.T) {
+	app := &App{}
+
+	if got := app.matchupBand().classify(50.5); got != "even" {
+		t.Errorf("default band: 50.5%% = %q, want even", got)
+	}
+
+	app.SetMatchupBand(49.8, 50.2)
+	if got := app.matchupBand().classify(50.5); got != "winning" {
+		t.Errorf("tight band: 50.5%% = %q, want winning", got)
+	}
+	if got := app.matchupBand().classify(49.5); got != "losing" {
+		t.Errorf("tight band: 49.5%% = %q, want losing", got)
+	}
+}
+
+func TestWithoutUnavailable_FiltersCountersAndBans(t *testing.T) {
+	app := &App{champions: lcu.NewChampionRegistry()}
+	counters := []data.MatchupStat{
+		{EnemyChampionID: 238, WinRate: 40.0, Matches: 100},
+		{EnemyChampionID: 157, WinRate: 42.0, Matches: 100},
+		{EnemyChampionID: 7, WinRate: 45.0, Matches: 100},
+		{EnemyChampionID: 61, WinRate: 46.0, Matches: 100},
+	}
+	unavailable := map[int]bool{238: true, 7: true}
+
+	bans := app.buildBanList(withoutUnavailable(counters, unavailable, 5))
+
+	if len(bans) != 2 {
+		t.Fatalf("got %d bans, want 2", len(bans))
+	}
+	for _, b := range bans {
+		if id := b["championID"].(int); unavailable[id] {
+			t.Errorf("unavailable champion %d suggested as a ban", id)
+		}
+	}
+
+	// Over-fetched lists are trimmed back to the limit after filtering
+	if got := withoutUnavailable(counters, unavailable, 1); len(got) != 1 || got[0].EnemyChampionID != 157 {
+		t.Errorf("limit 1: got %+v, want only 157", got)
+	}
+	if got := withoutUnavailable(counters, nil, 3); len(got) != 3 {
+		t.Errorf("nil set: got %d, want 3", len(got))
+	}
+}
+
+func TestUnavailableKey_TracksSetNotSize(t *testing.T) {
+	before := unavailableKey(map[int]bool{238: true, 157: true})
+	// A ban swapped for another keeps the count but must change the key
+	after := unavailableKey(map[int]bool{238: true, 7: true})
+	if before == after {
+		t.Errorf("same-size sets share key %q", before)
+	}
+	if again := unavailableKey(map[int]bool{157: true, 238: true}); again != before {
+		t.Errorf("same set gave keys %q and %q", before, again)
+	}
+	if unavailableKey(nil) != unavailableKey(map[int]bool{}) {
+		t.Error("nil and empty sets should share a key")
+	}
+}
+
+func TestBuildUpdate_AwaitsMinimumEnemies(t *testing.T) {
+	app := &App{champions: lcu.NewChampionRegistry()}
+
+	// Zed is in the matchup data, but one pick is too few to call him the lane opponent
+	payload := app.buildUpdate(fakeChampSelectStats{}, 103, "Ahri", "middle", []int{238}, 0)
+	if payload["awaitingPicks"] != true || payload["enemyName"] != nil {
+		t.Errorf("expected awaiting state, got %v", payload)
+	}
+	if payload["winRateLabel"] != "Awaiting more picks (1/3)" {
+		t.Errorf("winRateLabel = %v", payload["winRateLabel"])
+	}
+
+	// A known same-role enemy is enough on its own
+	payload = app.buildUpdate(fakeChampSelectStats{}, 103, "Ahri", "middle", []int{238}, 238)
+	if payload["awaitingPicks"] != nil || payload["matchupStatus"] != "losing" {
+		t.Errorf("same-role enemy should resolve the matchup, got %v", payload)
+	}
+
+	// Lowering the minimum commits to the guess
+	app.SetMinLaneEnemies(1)
+	payload = app.buildUpdate(fakeChampSelectStats{}, 103, "Ahri", "middle", []int{238}, 0)
+	if payload["awaitingPicks"] != nil || payload["winRateLabel"] != "vs Champion 238" {
+		t.Errorf("expected a matchup with min 1, got %v", payload)
+	}
+}
+
+func TestWithRawCounts_BuildAndBans(t *testing.T) {
+	app := &App{champions: lcu.NewChampionRegistry()}
+
+	payload := app.buildUpdate(fakeChampSelectStats{}, 103, "Ahri", "middle", []int{238}, 238)
+	if _, ok := payload["wins"]; ok {
+		t.Errorf("raw counts should be off by default, got %v", payload)
+	}
+
+	app.SetEmitRawCounts(true)
+	payload = app.buildUpdate(fakeChampSelectStats{}, 103, "Ahri", "middle", []int{238}, 238)
+	if payload["winRate"] != "47.5%" {
+		t.Errorf("formatted winRate = %v, want 47.5%%", payload["winRate"])
+	}
+	if payload["wins"] != 190 || payload["matches"] != 400 || payload["winRateValue"] != 47.5 {
+		t.Errorf("raw counts missing from build payload: %v", payload)
+	}
+
+	bans := app.buildBanList([]data.MatchupStat{{EnemyChampionID: 7, Wins: 45, Matches: 100, WinRate: 45.0}})
+	if bans[0]["wins"] != 45 || bans[0]["matches"] != 100 || bans[0]["winRateValue"] != 45.0 {
+		t.Errorf("raw counts missing from ban entry: %v", bans[0])
+	}
+
+	// No lane opponent: no matchup, so no counts
+	payload = app.buildUpdate(fakeChampSelectStats{}, 103, "Ahri", "middle", []int{99}, 0)
+	if _, ok := payload["wins"]; ok {
+		t.Errorf("raw counts without a lane opponent: %v", payload)
+	}
+}
+
+func TestWithRawCounts_BuildPathsAndItemOptions(t *testing.T) {
+	app := &App{champions: lcu.NewChampionRegistry(), items: lcu.NewItemRegistry()}
+	app.SetEmitRawCounts(true)
+
+	builds := app.buildPathEntries([]data.BuildPath{{
+		CoreItems:         []int{3089, 3020},
+		CoreItemStats:     []data.ItemOption{{ItemID: 3089, Wins: 52, WinRate: 52, Games: 100}, {ItemID: 3020, Wins: 30, WinRate: 50, Games: 60}},
+		WinRate:           52,
+		Games:             400,
+		FourthItemOptions: []data.ItemOption{{ItemID: 3157, Wins: 0, WinRate: 0, Games: 3}},
+	}})
+	if builds[0]["wins"] != 52 || builds[0]["matches"] != 100 || builds[0]["winRateValue"] != 52.0 {
+		t.Errorf("build path raw counts should come from its first core item: %v", builds[0])
+	}
+
+	// A real zero-win count is still reported
+	fourth := builds[0]["fourthItems"].([]map[string]interface{})
+	if fourth[0]["wins"] != 0 || fourth[0]["matches"] != 3 || fourth[0]["winRateValue"] != 0.0 {
+		t.Errorf("raw counts missing from item option: %v", fourth[0])
+	}
+
+	raw, err := json.Marshal(MetaChampion{Games: 3})
+	if err != nil || !strings.Contains(string(raw), `"wins":0`) {
+		t.Errorf("meta champion JSON %s should keep a zero win count", raw)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentFetches caps parallel stats queries so a full enemy team doesn't flood Turso
+const maxConcurrentFetches = 3
+
+// fetchConcurrently runs fetch for each champion ID with at most limit in flight.
+// Failed fetches are logged and left out of the result. Once ctx is cancelled no new
+// fetches start and ctx.Err() is returned.
+func fetchConcurrently[T any](ctx context.Context, limit int, championIDs []int, fetch func(ctx context.Context, championID int) (T, error)) (map[int]T, error) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(limit)
+
+	var mu sync.Mutex
+	results := make(map[int]T, len(championIDs))
+
+	for _, id := range championIDs {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			result, err := fetch(ctx, id)
+			if err != nil {
+				fmt.Printf("Fetch for champion %d failed: %v\n", id, err)
+				return nil
+			}
+
+			mu.Lock()
+			results[id] = result
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// newSelectionContext cancels any in-flight champ select fetches and returns a fresh context for the next batch
+func (a *App) newSelectionContext() context.Context {
+	a.selectionMu.Lock()
+	defer a.selectionMu.Unlock()
+
+	if a.selectionCancel != nil {
+		a.selectionCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.selectionCancel = cancel
+	return ctx
+}
+
+// cancelSelectionFetches stops any in-flight champ select fetches
+func (a *App) cancelSelectionFetches() {
+	a.selectionMu.Lock()
+	defer a.selectionMu.Unlock()
+
+	if a.selectionCancel != nil {
+		a.selectionCancel()
+		a.selectionCancel = nil
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchConcurrently_BoundedAndComplete(t *testing.T) {
+	const limit = 2
+	ids := []int{1, 2, 3, 4, 5, 6, 7}
+
+	var inFlight, maxInFlight atomic.Int32
+	results, err := fetchConcurrently(context.Background(), limit, ids, func(ctx context.Context, id int) (int, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if id == 4 {
+			return 0, errors.New("no data")
+		}
+		return id * 10, nil
+	})
+	if err != nil {
+		t.Fatalf("fetchConcurrently: %v", err)
+	}
+
+	if got := maxInFlight.Load(); got > limit {
+		t.Errorf("max concurrent fetches = %d, want <= %d", got, limit)
+	}
+	if len(results) != len(ids)-1 {
+		t.Errorf("got %d results, want %d", len(results), len(ids)-1)
+	}
+	for _, id := range ids {
+		if id == 4 {
+			if _, ok := results[id]; ok {
+				t.Error("failed fetch should be omitted")
+			}
+			continue
+		}
+		if results[id] != id*10 {
+			t.Errorf("result[%d] = %d, want %d", id, results[id], id*10)
+		}
+	}
+}
+
+func TestFetchConcurrently_CancelledSelection(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int32
+	_, err := fetchConcurrently(ctx, 2, []int{1, 2, 3}, func(ctx context.Context, id int) (int, error) {
+		calls.Add(1)
+		return id, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("fetch called %d times after cancellation, want 0", calls.Load())
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// maxChampSelectSnapshots caps the history file; the oldest snapshots are dropped
+const maxChampSelectSnapshots = 50
+
+// champSelection is the pick the champ select panels are currently showing
+type champSelection struct {
+	championID int
+	role       string
+	enemyIDs   []int
+}
+
+// ChampSelectSnapshot is a champ select summary saved for post-game review
+type ChampSelectSnapshot struct {
+	RecordedAt int64              `json:"recordedAt"` // Unix milliseconds
+	Summary    ChampSelectSummary `json:"summary"`
+}
+
+// SnapshotResult reports the outcome of recording a snapshot
+type SnapshotResult struct {
+	Success  bool                `json:"success"`
+	Snapshot ChampSelectSnapshot `json:"snapshot"`
+	Error    string              `json:"error,omitempty"`
+}
+
+// setCurrentSelection remembers the pick shown in champ select so it can be snapshotted
+func (a *App) setCurrentSelection(sel champSelection) {
+	a.selectionMu.Lock()
+	defer a.selectionMu.Unlock()
+	a.selection = sel
+}
+
+// currentSelection returns the pick shown in champ select
+func (a *App) currentSelection() champSelection {
+	a.selectionMu.Lock()
+	defer a.selectionMu.Unlock()
+	return a.selection
+}
+
+// RecordChampSelectSnapshot saves the current champ select recommendations to history
+func (a *App) RecordChampSelectSnapshot() SnapshotResult {
+	a.refreshStatsIfStale()
+	if stats := a.stats(); stats != nil {
+		return a.recordChampSelectSnapshot(stats)
+	}
+	return a.recordChampSelectSnapshot(nil)
+}
+
+// recordChampSelectSnapshot captures the summary for the current selection and appends it
+func (a *App) recordChampSelectSnapshot(stats champSelectStats) SnapshotResult {
+	sel := a.currentSelection()
+	if sel.championID == 0 || sel.role == "" {
+		return SnapshotResult{Error: "No champion selected"}
+	}
+
+	snapshot := ChampSelectSnapshot{
+		RecordedAt: a.now().UnixMilli(),
+		Summary:    a.champSelectSummary(stats, sel.championID, sel.role, sel.enemyIDs),
+	}
+
+	history, err := a.loadChampSelectHistory()
+	if err != nil {
+		return SnapshotResult{Error: fmt.Sprintf("Failed to read history: %v", err)}
+	}
+	history = append([]ChampSelectSnapshot{snapshot}, history...)
+	if len(history) > maxChampSelectSnapshots {
+		history = history[:maxChampSelectSnapshots]
+	}
+
+	encoded, err := json.Marshal(history)
+	if err != nil {
+		return SnapshotResult{Error: fmt.Sprintf("Failed to encode history: %v", err)}
+	}
+	if err := writeFileAtomic(a.championSelectHistoryPath(), encoded); err != nil {
+		return SnapshotResult{Error: fmt.Sprintf("Failed to save history: %v", err)}
+	}
+	return SnapshotResult{Success: true, Snapshot: snapshot}
+}
+
+// GetChampSelectHistory returns saved snapshots, newest first
+func (a *App) GetChampSelectHistory() []ChampSelectSnapshot {
+	history, err := a.loadChampSelectHistory()
+	if err != nil {
+		fmt.Printf("Failed to read champ select history: %v\n", err)
+		return []ChampSelectSnapshot{}
+	}
+	return history
+}
+
+// loadChampSelectHistory reads the history file; a missing file is an empty history
+func (a *App) loadChampSelectHistory() ([]ChampSelectSnapshot, error) {
+	raw, err := os.ReadFile(a.championSelectHistoryPath())
+	if os.IsNotExist(err) {
+		return []ChampSelectSnapshot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history := []ChampSelectSnapshot{}
+	if err := json.Unmarshal(raw, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// championSelectHistoryPath is the history file, next to the champion database by default
+func (a *App) championSelectHistoryPath() string {
+	if a.historyPath != "" {
+		return a.historyPath
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = "."
+	}
+	return filepath.Join(configDir, "GhostDraft", "champselect_history.json")
+}
+
+// writeFileAtomic writes to a temp file in the same directory and renames it over
+// path, so a crash mid-write never leaves a truncated file behind
+func writeFileAtomic(path string, contents []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ghostdraft/internal/lcu"
+)
+
+func TestRecordChampSelectSnapshot_RoundTrips(t *testing.T) {
+	recordedAt := time.Date(2025, 12, 1, 18, 30, 0, 0, time.UTC)
+	app := &App{
+		champions:   lcu.NewChampionRegistry(),
+		items:       lcu.NewItemRegistry(),
+		historyPath: filepath.Join(t.TempDir(), "GhostDraft", "champselect_history.json"),
+		clock:       func() time.Time { return recordedAt },
+	}
+
+	if result := app.recordChampSelectSnapshot(fakeChampSelectStats{}); result.Success {
+		t.Fatal("snapshot without a selection should fail")
+	}
+
+	app.setCurrentSelection(champSelection{championID: 103, role: "middle", enemyIDs: []int{7, 238}})
+	result := app.recordChampSelectSnapshot(fakeChampSelectStats{})
+	if !result.Success {
+		t.Fatalf("RecordChampSelectSnapshot failed: %s", result.Error)
+	}
+
+	history := app.GetChampSelectHistory()
+	if len(history) != 1 {
+		t.Fatalf("got %d snapshots, want 1", len(history))
+	}
+	got := history[0]
+	if got.RecordedAt != recordedAt.UnixMilli() {
+		t.Errorf("recordedAt = %d, want %d", got.RecordedAt, recordedAt.UnixMilli())
+	}
+	s := got.Summary
+	if s.ChampionID != 103 || s.Role != "middle" || s.Patch != "15.24" {
+		t.Errorf("summary header: %+v", s)
+	}
+	if s.Matchup.EnemyID != 238 || s.Matchup.Games != 400 || s.Matchup.Status != "losing" {
+		t.Errorf("matchup not preserved: %+v", s.Matchup)
+	}
+	if len(s.Build.Builds) != 1 || len(s.Counters) != 1 || len(s.Bans) != 1 {
+		t.Errorf("sections not preserved: builds=%d counters=%d bans=%d", len(s.Build.Builds), len(s.Counters), len(s.Bans))
+	}
+
+	// Newer snapshots come first
+	app.setCurrentSelection(champSelection{championID: 238, role: "middle"})
+	app.recordChampSelectSnapshot(fakeChampSelectStats{})
+	if history := app.GetChampSelectHistory(); len(history) != 2 || history[0].Summary.ChampionID != 238 {
+		t.Errorf("history order: %+v", history)
+	}
+}
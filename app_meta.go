@@ -2,11 +2,19 @@ package main
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"ghostdraft/internal/data"
+	"ghostdraft/internal/lcu"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// MetaChampion represents a champion in the meta list
+// MetaChampion represents a champion in the meta list. Unlike the map-shaped stat
+// events, its fields are a fixed schema (with Games and Confidence already raw), so Wins
+// is always set and SetEmitRawCounts doesn't apply.
 type MetaChampion struct {
 	ChampionID   int     `json:"championId"`
 	ChampionName string  `json:"championName"`
@@ -14,8 +22,25 @@ type MetaChampion struct {
 	WinRate      float64 `json:"winRate"`
 	PickRate     float64 `json:"pickRate"`
 	Games        int     `json:"games"`
+	Wins         int     `json:"wins"`
+	Confidence   float64 `json:"confidence"`  // Wilson lower bound of the win rate
+	Provisional  bool    `json:"provisional"` // too few games to trust the rank
+	Tier         string  `json:"tier"`
 }
 
+// Tier score thresholds, in standard deviations above the role average
+const (
+	tierSThreshold = 1.0
+	tierAThreshold = 0.5
+	tierBThreshold = -1.0
+)
+
+// Win rate dominates the tier score; pick rate rewards proven popularity
+const (
+	tierWinRateWeight  = 0.7
+	tierPickRateWeight = 0.3
+)
+
 // MetaData represents the top champions for all roles
 type MetaData struct {
 	Patch   string                    `json:"patch"`
@@ -82,25 +107,63 @@ type ChampionBuildData struct {
 	ChampionName string      `json:"championName"`
 	ChampionID   int         `json:"championId"`
 	Role         string      `json:"role"`
+	ResolvedRole string      `json:"resolvedRole"` // role the build data came from, if it fell back
 	IconURL      string      `json:"iconURL"`
 	SplashURL    string      `json:"splashURL"`
+	Games        int         `json:"games"`
+	Source       string      `json:"source"`
 	Builds       []BuildPath `json:"builds"`
+
+	// Set by GetChampionBuildForPatch: the patch the build came from, and whether it is
+	// only the closest stored patch because the requested one has no data
+	Patch         string `json:"patch,omitempty"`
+	PatchFallback bool   `json:"patchFallback,omitempty"`
 }
 
-// GetMetaChampions returns the top 5 champions by win rate for each role
+// metaChampionsPerRole is how many champions the meta table lists per role
+const metaChampionsPerRole = 5
+
+// metaStats is the part of the stats provider the meta table reads
+type metaStats interface {
+	GetPatch() string
+	FetchAllRolesConfidentChampions(limit int) (map[string][]data.ChampionWinRate, error)
+}
+
+// GetMetaChampions returns the top 5 champions for each role, ranked by the lower
+// confidence bound of their win rate so small samples don't lead the list
 func (a *App) GetMetaChampions() MetaData {
+	a.refreshStatsIfStale()
+	if stats := a.stats(); stats != nil {
+		return a.metaData(stats)
+	}
+	return a.metaData(nil)
+}
+
+// emitMetaReady sends the whole meta table in one "meta:ready" event so the UI
+// swaps every role at once instead of rendering them one by one
+func (a *App) emitMetaReady() {
+	if a.ctx == nil {
+		return
+	}
+	if stats := a.stats(); stats != nil {
+		runtime.EventsEmit(a.ctx, "meta:ready", a.metaData(stats))
+	}
+}
+
+// metaData computes every role's meta list, tiers included, from a single provider query
+func (a *App) metaData(stats metaStats) MetaData {
 	result := MetaData{
 		HasData: false,
 		Roles:   make(map[string][]MetaChampion),
 	}
 
-	if a.statsProvider == nil {
+	if stats == nil {
 		return result
 	}
 
-	result.Patch = a.statsProvider.GetPatch()
+	result.Patch = stats.GetPatch()
 
-	roleData, err := a.statsProvider.FetchAllRolesTopChampions(5)
+	roleData, err := stats.FetchAllRolesConfidentChampions(metaChampionsPerRole)
 	if err != nil {
 		return result
 	}
@@ -110,15 +173,20 @@ func (a *App) GetMetaChampions() MetaData {
 		for _, c := range champs {
 			name := a.champions.GetName(c.ChampionID)
 			icon := a.champions.GetIconURL(c.ChampionID)
-			metaChamps = append(metaChamps, MetaChampion{
+			mc := MetaChampion{
 				ChampionID:   c.ChampionID,
 				ChampionName: name,
 				IconURL:      icon,
 				WinRate:      c.WinRate,
 				PickRate:     c.PickRate,
 				Games:        c.Matches,
-			})
+				Wins:         c.Wins,
+				Confidence:   c.Confidence,
+				Provisional:  c.Provisional,
+			}
+			metaChamps = append(metaChamps, mc)
 		}
+		assignMetaTiers(metaChamps)
 		result.Roles[role] = metaChamps
 	}
 
@@ -126,30 +194,155 @@ func (a *App) GetMetaChampions() MetaData {
 	return result
 }
 
+// assignMetaTiers labels each champion S/A/B/C by a blend of win rate and
+// pick rate z-scores relative to the other champions in the same role
+func assignMetaTiers(champs []MetaChampion) {
+	winRates := make([]float64, len(champs))
+	pickRates := make([]float64, len(champs))
+	for i, c := range champs {
+		winRates[i] = c.WinRate
+		pickRates[i] = c.PickRate
+	}
+	wrMean, wrStd := meanStdDev(winRates)
+	prMean, prStd := meanStdDev(pickRates)
+
+	for i := range champs {
+		score := tierWinRateWeight*zScore(champs[i].WinRate, wrMean, wrStd) +
+			tierPickRateWeight*zScore(champs[i].PickRate, prMean, prStd)
+		switch {
+		case score >= tierSThreshold:
+			champs[i].Tier = "S"
+		case score >= tierAThreshold:
+			champs[i].Tier = "A"
+		case score >= tierBThreshold:
+			champs[i].Tier = "B"
+		default:
+			champs[i].Tier = "C"
+		}
+	}
+}
+
+// meanStdDev returns the mean and population standard deviation of values
+func meanStdDev(values []float64) (mean, stdDev float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		stdDev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(stdDev / float64(len(values)))
+}
+
+// zScore returns how many standard deviations v is from mean (0 when there is no spread)
+func zScore(v, mean, stdDev float64) float64 {
+	if stdDev == 0 {
+		return 0
+	}
+	return (v - mean) / stdDev
+}
+
+// GetItemTooltip returns an item's name, description and cost for hover tooltips
+func (a *App) GetItemTooltip(itemID int) lcu.ItemTooltip {
+	return a.items.GetTooltip(itemID)
+}
+
+// Good matchups shown on the champion page
+const (
+	goodMatchupMinGames = 20
+	goodMatchupLimit    = 5
+)
+
+// selectGoodMatchups ranks matchups by the Wilson lower bound of their win rate so
+// well-sampled matchups outrank low-sample spikes, then keeps the top limit
+func selectGoodMatchups(matchups []data.MatchupStat, minGames, limit int) []data.MatchupStat {
+	var eligible []data.MatchupStat
+	for _, m := range matchups {
+		if m.Matches >= minGames {
+			eligible = append(eligible, m)
+		}
+	}
+	sort.SliceStable(eligible, func(i, j int) bool {
+		return data.WilsonLowerBound(eligible[i].Wins, eligible[i].Matches) >
+			data.WilsonLowerBound(eligible[j].Wins, eligible[j].Matches)
+	})
+	if len(eligible) > limit {
+		eligible = eligible[:limit]
+	}
+	return eligible
+}
+
 // GetChampionBuild returns build data for a champion in the same format as items:update
 func (a *App) GetChampionBuild(championID int, role string) ChampionBuildData {
+	a.refreshStatsIfStale()
+	stats := a.stats()
+	if stats == nil {
+		return a.championBuild(nil, championID, role)
+	}
+
+	buildData, err := stats.FetchChampionData(championID, a.champions.GetName(championID), role)
+	if err != nil {
+		return a.championBuild(nil, championID, role)
+	}
+	return a.championBuild(buildData, championID, role)
+}
+
+// GetChampionBuildForPatch returns build data computed from a single patch. An empty patch
+// means the live game patch; when the stats database lags behind it, the closest stored
+// patch is served and PatchFallback is set.
+func (a *App) GetChampionBuildForPatch(championID int, role string, patch string) ChampionBuildData {
+	if patch == "" {
+		patch = livePatch(a.champions.GetVersion())
+	}
+
+	a.refreshStatsIfStale()
+	stats := a.stats()
+	if stats == nil || patch == "" {
+		return a.championBuild(nil, championID, role)
+	}
+
+	buildData, err := stats.FetchChampionDataForPatch(championID, a.champions.GetName(championID), role, patch)
+	if err != nil {
+		return a.championBuild(nil, championID, role)
+	}
+	return a.championBuild(buildData, championID, role)
+}
+
+// livePatch trims a Data Dragon version ("15.24.1") to the stats patch format ("15.24")
+func livePatch(ddragonVersion string) string {
+	parts := strings.SplitN(ddragonVersion, ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// championBuild converts fetched build data to the frontend shape; nil means no items
+func (a *App) championBuild(buildData *data.BuildData, championID int, role string) ChampionBuildData {
 	result := ChampionBuildData{
 		HasItems:   false,
 		ChampionID: championID,
 		Builds:     []BuildPath{},
 	}
 
-	champName := a.champions.GetName(championID)
-	result.ChampionName = champName
+	result.ChampionName = a.champions.GetName(championID)
 	result.Role = role
 	result.IconURL = a.champions.GetIconURL(championID)
 	result.SplashURL = a.champions.GetSplashURL(championID)
 
-	if a.statsProvider == nil {
-		return result
-	}
-
-	buildData, err := a.statsProvider.FetchChampionData(championID, champName, role)
-	if err != nil || buildData == nil || len(buildData.Builds) == 0 {
+	if buildData == nil || len(buildData.Builds) == 0 {
 		return result
 	}
 
 	result.HasItems = true
+	result.ResolvedRole = buildData.ResolvedRole
+	result.Games = buildData.Games
+	result.Source = buildData.Source
+	result.Patch = buildData.Patch
+	result.PatchFallback = buildData.Patch != "" && !buildData.ExactPatch
 
 	// Helper to convert item IDs to BuildItem
 	convertItems := func(itemIDs []int) []BuildItem {
@@ -181,6 +374,9 @@ func (a *App) GetChampionBuild(championID int, role string) ChampionBuildData {
 
 	// Convert all build paths
 	for _, build := range buildData.Builds {
+		if a.itemsByWinRate.Load() {
+			build = orderItemsByWinRate(build)
+		}
 		buildName := "Build"
 		if len(build.CoreItems) > 0 {
 			buildName = a.items.GetName(build.CoreItems[0])
@@ -201,8 +397,43 @@ func (a *App) GetChampionBuild(championID int, role string) ChampionBuildData {
 	return result
 }
 
+// orderItemsByWinRate returns a copy of build with core items and each situational slot
+// sorted by win rate, highest first. Ties keep their frequency order. Core items are left
+// as they are if their stats are missing.
+func orderItemsByWinRate(build data.BuildPath) data.BuildPath {
+	byWinRate := func(options []data.ItemOption) []data.ItemOption {
+		sorted := append([]data.ItemOption(nil), options...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].WinRate > sorted[j].WinRate })
+		return sorted
+	}
+
+	if len(build.CoreItemStats) == len(build.CoreItems) {
+		build.CoreItemStats = byWinRate(build.CoreItemStats)
+		build.CoreItems = make([]int, len(build.CoreItemStats))
+		for i, item := range build.CoreItemStats {
+			build.CoreItems[i] = item.ItemID
+		}
+	}
+	build.FourthItemOptions = byWinRate(build.FourthItemOptions)
+	build.FifthItemOptions = byWinRate(build.FifthItemOptions)
+	build.SixthItemOptions = byWinRate(build.SixthItemOptions)
+	return build
+}
+
+// topN returns at most the first n options, tolerating nil slices and non-positive n
+func topN(options []data.ItemOption, n int) []data.ItemOption {
+	if n <= 0 || len(options) == 0 {
+		return nil
+	}
+	if n > len(options) {
+		n = len(options)
+	}
+	return options[:n]
+}
+
 // GetChampionDetails returns detailed build and matchup info for a champion
 func (a *App) GetChampionDetails(championID int, role string) ChampionDetails {
+	stats := a.stats()
 	result := ChampionDetails{
 		HasData:      false,
 		ChampionID:   championID,
@@ -215,7 +446,7 @@ func (a *App) GetChampionDetails(championID int, role string) ChampionDetails {
 		GoodMatchups: []ChampionDetailMatchup{},
 	}
 
-	if a.statsProvider == nil {
+	if stats == nil {
 		return result
 	}
 
@@ -223,7 +454,7 @@ func (a *App) GetChampionDetails(championID int, role string) ChampionDetails {
 	result.ChampionName = champName
 
 	// Fetch build data
-	buildData, err := a.statsProvider.FetchChampionData(championID, champName, role)
+	buildData, err := stats.FetchChampionData(championID, champName, role)
 	if err == nil && buildData != nil && len(buildData.Builds) > 0 {
 		result.HasData = true
 		build := buildData.Builds[0]
@@ -237,8 +468,10 @@ func (a *App) GetChampionDetails(championID int, role string) ChampionDetails {
 			})
 		}
 
+		optionCount := stats.SituationalItemOptions()
+
 		// 4th item options
-		for _, opt := range build.FourthItemOptions[:min(3, len(build.FourthItemOptions))] {
+		for _, opt := range topN(build.FourthItemOptions, optionCount) {
 			result.FourthItems = append(result.FourthItems, ChampionDetailItem{
 				ItemID:  opt.ItemID,
 				Name:    a.items.GetName(opt.ItemID),
@@ -249,7 +482,7 @@ func (a *App) GetChampionDetails(championID int, role string) ChampionDetails {
 		}
 
 		// 5th item options
-		for _, opt := range build.FifthItemOptions[:min(3, len(build.FifthItemOptions))] {
+		for _, opt := range topN(build.FifthItemOptions, optionCount) {
 			result.FifthItems = append(result.FifthItems, ChampionDetailItem{
 				ItemID:  opt.ItemID,
 				Name:    a.items.GetName(opt.ItemID),
@@ -260,7 +493,7 @@ func (a *App) GetChampionDetails(championID int, role string) ChampionDetails {
 		}
 
 		// 6th item options
-		for _, opt := range build.SixthItemOptions[:min(3, len(build.SixthItemOptions))] {
+		for _, opt := range topN(build.SixthItemOptions, optionCount) {
 			result.SixthItems = append(result.SixthItems, ChampionDetailItem{
 				ItemID:  opt.ItemID,
 				Name:    a.items.GetName(opt.ItemID),
@@ -272,7 +505,7 @@ func (a *App) GetChampionDetails(championID int, role string) ChampionDetails {
 	}
 
 	// Fetch counters (champions that beat you) - separate from allMatchups
-	counters, err := a.statsProvider.FetchCounterMatchups(championID, role, 6)
+	counters, err := stats.FetchCounterMatchups(championID, role, 6)
 	if err != nil {
 		fmt.Printf("Failed to fetch counters for %s: %v\n", champName, err)
 	} else {
@@ -295,23 +528,11 @@ func (a *App) GetChampionDetails(championID int, role string) ChampionDetails {
 	}
 
 	// Fetch good matchups (champions you beat)
-	allMatchups, err := a.statsProvider.FetchAllMatchups(championID, role)
+	allMatchups, err := stats.FetchAllMatchups(championID, role)
 	if err == nil && len(allMatchups) > 0 {
 		result.HasData = true
 
-		// Sort allMatchups by win rate descending
-		for i := 0; i < len(allMatchups); i++ {
-			for j := i + 1; j < len(allMatchups); j++ {
-				if allMatchups[j].WinRate > allMatchups[i].WinRate {
-					allMatchups[i], allMatchups[j] = allMatchups[j], allMatchups[i]
-				}
-			}
-		}
-		for i := 0; i < min(5, len(allMatchups)); i++ {
-			m := allMatchups[i]
-			if m.Matches < 20 {
-				continue
-			}
+		for _, m := range selectGoodMatchups(allMatchups, goodMatchupMinGames, goodMatchupLimit) {
 			enemyName := a.champions.GetName(m.EnemyChampionID)
 			iconURL := a.champions.GetIconURL(m.EnemyChampionID)
 			result.GoodMatchups = append(result.GoodMatchups, ChampionDetailMatchup{
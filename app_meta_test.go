@@ -0,0 +1,187 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"ghostdraft/internal/data"
+	"ghostdraft/internal/lcu"
+	"ghostdraft/internal/roles"
+)
+
+func TestAssignMetaTiers_OutlierIsS(t *testing.T) {
+	champs := []MetaChampion{
+		{ChampionID: 1, WinRate: 58.0, PickRate: 15.0}, // dominant pick
+		{ChampionID: 2, WinRate: 50.0, PickRate: 5.0},
+		{ChampionID: 3, WinRate: 50.5, PickRate: 5.0},
+		{ChampionID: 4, WinRate: 49.5, PickRate: 5.0},
+		{ChampionID: 5, WinRate: 50.0, PickRate: 5.0},
+	}
+
+	assignMetaTiers(champs)
+
+	if champs[0].Tier != "S" {
+		t.Errorf("outlier: got tier %q, want S", champs[0].Tier)
+	}
+	for _, c := range champs[1:] {
+		if c.Tier != "B" {
+			t.Errorf("champion %d (%.1f%% WR): got tier %q, want B", c.ChampionID, c.WinRate, c.Tier)
+		}
+	}
+}
+
+func TestAssignMetaTiers_NoSpreadIsB(t *testing.T) {
+	champs := []MetaChampion{
+		{ChampionID: 1, WinRate: 50.0, PickRate: 5.0},
+		{ChampionID: 2, WinRate: 50.0, PickRate: 5.0},
+	}
+
+	assignMetaTiers(champs)
+
+	for _, c := range champs {
+		if c.Tier != "B" {
+			t.Errorf("champion %d: got tier %q, want B", c.ChampionID, c.Tier)
+		}
+	}
+}
+
+func TestTopN_Clamps(t *testing.T) {
+	options := []data.ItemOption{{ItemID: 1}, {ItemID: 2}, {ItemID: 3}}
+
+	tests := []struct {
+		name    string
+		options []data.ItemOption
+		n       int
+		want    int
+	}{
+		{"n below len", options, 2, 2},
+		{"n equals len", options, 3, 3},
+		{"n above len", options, 5, 3},
+		{"n zero", options, 0, 0},
+		{"n negative", options, -1, 0},
+		{"empty slice", []data.ItemOption{}, 3, 0},
+		{"nil slice", nil, 3, 0},
+	}
+	for _, tt := range tests {
+		got := topN(tt.options, tt.n)
+		if len(got) != tt.want {
+			t.Errorf("%s: got %d options, want %d", tt.name, len(got), tt.want)
+		}
+		for i := range got {
+			if got[i].ItemID != tt.options[i].ItemID {
+				t.Errorf("%s: option %d = %d, want %d", tt.name, i, got[i].ItemID, tt.options[i].ItemID)
+			}
+		}
+	}
+}
+
+func TestSelectGoodMatchups_PrefersSolidSample(t *testing.T) {
+	matchups := []data.MatchupStat{
+		{EnemyChampionID: 1, Wins: 15, Matches: 22, WinRate: 68.2},   // low-sample spike
+		{EnemyChampionID: 2, Wins: 106, Matches: 200, WinRate: 53.0}, // solid
+		{EnemyChampionID: 3, Wins: 15, Matches: 19, WinRate: 78.9},   // below the floor
+	}
+
+	got := selectGoodMatchups(matchups, goodMatchupMinGames, goodMatchupLimit)
+
+	if len(got) != 2 {
+		t.Fatalf("got %d matchups, want 2 above the games floor", len(got))
+	}
+	if got[0].EnemyChampionID != 2 || got[1].EnemyChampionID != 1 {
+		t.Errorf("order = [%d %d], want the 200-game matchup first", got[0].EnemyChampionID, got[1].EnemyChampionID)
+	}
+
+	if got := selectGoodMatchups(matchups, goodMatchupMinGames, 1); len(got) != 1 {
+		t.Errorf("limit not applied: got %d", len(got))
+	}
+}
+
+// fakeMetaStats returns three champions for every role and counts provider queries
+type fakeMetaStats struct {
+	calls int
+}
+
+func (f *fakeMetaStats) GetPatch() string { return "15.24" }
+
+func (f *fakeMetaStats) FetchAllRolesConfidentChampions(limit int) (map[string][]data.ChampionWinRate, error) {
+	f.calls++
+	result := make(map[string][]data.ChampionWinRate)
+	for i, r := range roles.All {
+		base := (i + 1) * 100
+		result[r.String()] = []data.ChampionWinRate{
+			{ChampionID: base + 1, WinRate: 54, PickRate: 12, Matches: 1200},
+			{ChampionID: base + 2, WinRate: 51, PickRate: 6, Matches: 600},
+			{ChampionID: base + 3, WinRate: 48, PickRate: 3, Matches: 300},
+		}
+	}
+	return result, nil
+}
+
+func TestMetaData_SinglePayloadCoversAllRoles(t *testing.T) {
+	app := &App{champions: lcu.NewChampionRegistry()}
+	stats := &fakeMetaStats{}
+
+	meta := app.metaData(stats)
+
+	if stats.calls != 1 {
+		t.Errorf("provider queried %d times, want 1", stats.calls)
+	}
+	if !meta.HasData || meta.Patch != "15.24" {
+		t.Fatalf("got hasData=%v patch=%q, want true 15.24", meta.HasData, meta.Patch)
+	}
+	for _, r := range roles.All {
+		champs := meta.Roles[r.String()]
+		if len(champs) != 3 {
+			t.Errorf("%s: got %d champions, want 3", r, len(champs))
+			continue
+		}
+		for _, c := range champs {
+			if c.Tier == "" || c.PickRate == 0 || c.ChampionName == "" {
+				t.Errorf("%s: champion %d missing tier, pick rate or name: %+v", r, c.ChampionID, c)
+			}
+		}
+	}
+}
+
+func TestChampionBuild_ItemOrder(t *testing.T) {
+	build := &data.BuildData{Builds: []data.BuildPath{{
+		CoreItems: []int{6655, 3089, 3020},
+		CoreItemStats: []data.ItemOption{
+			{ItemID: 6655, WinRate: 50, Games: 900},
+			{ItemID: 3089, WinRate: 55, Games: 700},
+			{ItemID: 3020, WinRate: 52, Games: 800},
+		},
+		FourthItemOptions: []data.ItemOption{
+			{ItemID: 3135, WinRate: 51, Games: 300},
+			{ItemID: 3157, WinRate: 56, Games: 200},
+		},
+	}}}
+	app := &App{champions: lcu.NewChampionRegistry(), items: lcu.NewItemRegistry()}
+	ids := func(items []BuildItem) []int {
+		var out []int
+		for _, item := range items {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+
+	// Frequency (default) keeps the most-picked-first order
+	got := app.championBuild(build, 103, "middle").Builds[0]
+	if !reflect.DeepEqual(ids(got.CoreItems), []int{6655, 3089, 3020}) || got.FourthItems[0].ID != 3135 {
+		t.Errorf("frequency order: core %v, 4th %v", ids(got.CoreItems), ids(got.FourthItems))
+	}
+
+	app.SetBuildItemOrder(ItemOrderWinRate)
+	got = app.championBuild(build, 103, "middle").Builds[0]
+	if !reflect.DeepEqual(ids(got.CoreItems), []int{3089, 3020, 6655}) || got.FourthItems[0].ID != 3157 {
+		t.Errorf("win rate order: core %v, 4th %v", ids(got.CoreItems), ids(got.FourthItems))
+	}
+	if build.Builds[0].CoreItems[0] != 6655 {
+		t.Error("reordering must not modify the (cached) build data")
+	}
+
+	app.SetBuildItemOrder(ItemOrderFrequency)
+	if got := app.championBuild(build, 103, "middle").Builds[0]; got.CoreItems[0].ID != 6655 {
+		t.Errorf("back to frequency: core %v", ids(got.CoreItems))
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"ghostdraft/internal/data"
+)
+
+// spellPairMinGames keeps rarely-taken spell pairs from topping the list on a lucky streak
+const spellPairMinGames = 30
+
+// summonerSpellNames maps Riot summoner spell IDs to display names
+var summonerSpellNames = map[int]string{
+	1:  "Cleanse",
+	3:  "Exhaust",
+	4:  "Flash",
+	6:  "Ghost",
+	7:  "Heal",
+	11: "Smite",
+	12: "Teleport",
+	14: "Ignite",
+	21: "Barrier",
+}
+
+// defensiveSpells are the spells taken to survive an all-in
+var defensiveSpells = map[int]bool{1: true, 3: true, 7: true, 21: true}
+
+// SpellRecommendation is the best-performing summoner spell pair for a matchup
+type SpellRecommendation struct {
+	Spell1ID   int     `json:"spell1Id"`
+	Spell1Name string  `json:"spell1Name"`
+	Spell2ID   int     `json:"spell2Id"`
+	Spell2Name string  `json:"spell2Name"`
+	WinRate    float64 `json:"winRate"`
+	Games      int     `json:"games"`
+	Note       string  `json:"note,omitempty"` // set when a defensive spell is favored into burst
+	Error      string  `json:"error,omitempty"`
+}
+
+// spellPairSource is the part of the stats provider spell recommendations read
+type spellPairSource interface {
+	FetchSpellPairs(championID int, role string, minGames int) ([]data.SpellPairStat, error)
+}
+
+// enemyProfile is what the recommendation needs to know about the lane opponent
+type enemyProfile struct {
+	Name       string
+	DamageType string
+	RoleTags   string
+}
+
+// GetRecommendedSpells returns the highest win rate summoner spell pair for a champion
+// in a role, noting when a defensive spell is favored into the enemy's damage
+func (a *App) GetRecommendedSpells(championID int, role string, enemyChampionID int) SpellRecommendation {
+	enemy := enemyProfile{Name: a.champions.GetName(enemyChampionID)}
+	enemy.DamageType = a.getDamageType(enemy.Name)
+	enemy.RoleTags = a.getRoleTags(enemy.Name)
+
+	if stats := a.stats(); stats != nil {
+		return recommendSpells(stats, championID, role, enemy)
+	}
+	return recommendSpells(nil, championID, role, enemy)
+}
+
+// recommendSpells picks the top spell pair and adds the matchup note
+func recommendSpells(source spellPairSource, championID int, role string, enemy enemyProfile) SpellRecommendation {
+	var result SpellRecommendation
+	if source == nil {
+		result.Error = "Stats provider not available"
+		return result
+	}
+
+	pairs, err := source.FetchSpellPairs(championID, role, spellPairMinGames)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if len(pairs) == 0 {
+		result.Error = fmt.Sprintf("No spell data with at least %d games", spellPairMinGames)
+		return result
+	}
+
+	best := pairs[0]
+	result.Spell1ID, result.Spell1Name = best.Spell1ID, spellName(best.Spell1ID)
+	result.Spell2ID, result.Spell2Name = best.Spell2ID, spellName(best.Spell2ID)
+	result.WinRate = best.WinRate
+	result.Games = best.Matches
+
+	if isBurstThreat(enemy) {
+		for _, id := range []int{best.Spell1ID, best.Spell2ID} {
+			if defensiveSpells[id] {
+				result.Note = fmt.Sprintf("%s favored into %s's %s burst", spellName(id), enemy.Name, enemy.DamageType)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// isBurstThreat reports whether the enemy is an assassin-style damage dealer
+func isBurstThreat(enemy enemyProfile) bool {
+	if enemy.DamageType != "AD" && enemy.DamageType != "AP" {
+		return false
+	}
+	return strings.Contains(enemy.RoleTags, "Burst") && !strings.Contains(enemy.RoleTags, "Tank")
+}
+
+// spellName returns a summoner spell's display name, or its ID if unknown
+func spellName(id int) string {
+	if name, ok := summonerSpellNames[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("Spell %d", id)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"ghostdraft/internal/data"
+)
+
+type stubSpellPairs struct {
+	pairs []data.SpellPairStat
+}
+
+func (s stubSpellPairs) FetchSpellPairs(championID int, role string, minGames int) ([]data.SpellPairStat, error) {
+	return s.pairs, nil
+}
+
+func TestRecommendSpells_BestPairAndDefensiveNote(t *testing.T) {
+	// Provider order is best win rate first
+	source := stubSpellPairs{[]data.SpellPairStat{
+		{Spell1ID: 3, Spell2ID: 4, Wins: 54, Matches: 100, WinRate: 54},
+		{Spell1ID: 4, Spell2ID: 14, Wins: 510, Matches: 1000, WinRate: 51},
+	}}
+
+	zed := enemyProfile{Name: "Zed", DamageType: "AD", RoleTags: "Burst, Poke"}
+	got := recommendSpells(source, 103, "middle", zed)
+	if got.Spell1Name != "Exhaust" || got.Spell2Name != "Flash" || got.WinRate != 54 || got.Games != 100 {
+		t.Errorf("got %+v, want Exhaust + Flash at 54%% over 100 games", got)
+	}
+	if got.Note != "Exhaust favored into Zed's AD burst" {
+		t.Errorf("Note = %q, want the defensive note against an assassin", got.Note)
+	}
+
+	// No note into a tank, even with Exhaust on top
+	malphite := enemyProfile{Name: "Malphite", DamageType: "AP", RoleTags: "Tank, Engage, Burst"}
+	if got := recommendSpells(source, 103, "middle", malphite); got.Note != "" {
+		t.Errorf("Note = %q into a tank, want none", got.Note)
+	}
+
+	if got := recommendSpells(stubSpellPairs{}, 103, "middle", zed); got.Error == "" {
+		t.Error("no spell data should report an error")
+	}
+	if got := recommendSpells(nil, 103, "middle", zed); got.Error == "" {
+		t.Error("missing provider should report an error")
+	}
+}
@@ -1,26 +1,234 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
 
+	"ghostdraft/internal/data"
 	"ghostdraft/internal/lcu"
+	"ghostdraft/internal/roles"
 )
 
-// ForceStatsUpdate clears the cache and refreshes stats data from Turso
+// stats returns the current stats provider, or nil if stats are unavailable.
+// Callers should read it once and use the local value for the whole request.
+func (a *App) stats() *data.StatsProvider {
+	return a.statsProvider.Load()
+}
+
+// setStatsProvider atomically swaps in a new stats provider, carrying over the
+// configured situational item count
+func (a *App) setStatsProvider(provider *data.StatsProvider) {
+	if provider != nil {
+		provider.SetSituationalItemOptions(int(a.situationalOptions.Load()))
+	}
+	a.statsProvider.Store(provider)
+	if provider != nil {
+		a.statsUpdatedAt.Store(a.now().UnixNano())
+	}
+}
+
+// DefaultStatsMaxAge is how old stats may get before a lookup triggers a background refresh
+const DefaultStatsMaxAge = 6 * time.Hour
+
+// statsRefreshRetryInterval is the minimum wait between background refresh attempts, so
+// stale stats don't hammer an unreachable database on every lookup
+const statsRefreshRetryInterval = 5 * time.Minute
+
+// SetStatsMaxAge sets how many minutes stats are served before a background refresh.
+// Zero restores DefaultStatsMaxAge; negative values disable auto refresh.
+func (a *App) SetStatsMaxAge(minutes int) {
+	if minutes < 0 {
+		a.statsMaxAge.Store(-1)
+		return
+	}
+	a.statsMaxAge.Store(int64(time.Duration(minutes) * time.Minute))
+}
+
+// now returns the current time from the injected clock, if any
+func (a *App) now() time.Time {
+	if a.clock != nil {
+		return a.clock()
+	}
+	return time.Now()
+}
+
+// refreshStatsIfStale starts a background ForceStatsUpdate when the loaded stats are
+// older than the configured max age and no attempt was made in the last
+// statsRefreshRetryInterval. Returns true if a refresh was scheduled.
+func (a *App) refreshStatsIfStale() bool {
+	maxAge := time.Duration(a.statsMaxAge.Load())
+	if maxAge < 0 || a.stats() == nil {
+		return false
+	}
+	if maxAge == 0 {
+		maxAge = DefaultStatsMaxAge
+	}
+
+	now := a.now()
+	updatedAt := a.statsUpdatedAt.Load()
+	if updatedAt == 0 || now.Sub(time.Unix(0, updatedAt)) < maxAge {
+		return false
+	}
+	// A failed refresh leaves statsUpdatedAt behind, so back off from the last attempt instead
+	if attemptAt := a.statsRefreshAttemptAt.Load(); attemptAt != 0 && now.Sub(time.Unix(0, attemptAt)) < statsRefreshRetryInterval {
+		return false
+	}
+	if !a.statsRefreshing.CompareAndSwap(false, true) {
+		return false
+	}
+	a.statsRefreshAttemptAt.Store(now.UnixNano())
+
+	update := a.statsUpdater
+	if update == nil {
+		update = a.ForceStatsUpdate
+	}
+	go func() {
+		defer a.statsRefreshing.Store(false)
+		fmt.Printf("Stats older than %v, refreshing: %s\n", maxAge, update())
+	}()
+	return true
+}
+
+// SetSituationalItemOptions sets how many options are shown for the 4th/5th/6th item slots.
+// Values <= 0 restore the default of data.DefaultSituationalItemOptions.
+func (a *App) SetSituationalItemOptions(count int) {
+	if count < 0 {
+		count = 0
+	}
+	a.situationalOptions.Store(int32(count))
+	if stats := a.stats(); stats != nil {
+		stats.SetSituationalItemOptions(count)
+	}
+}
+
+// Build item orderings accepted by SetBuildItemOrder
+const (
+	ItemOrderFrequency = "frequency" // most-picked first (default)
+	ItemOrderWinRate   = "winrate"   // highest win rate first
+)
+
+// SetBuildItemOrder sets how core and situational items are ordered in GetChampionBuild.
+// Anything other than ItemOrderWinRate restores the frequency default.
+func (a *App) SetBuildItemOrder(order string) {
+	a.itemsByWinRate.Store(order == ItemOrderWinRate)
+}
+
+// StatsUpdateResult reports the outcome of a stats update
+type StatsUpdateResult struct {
+	Success       bool   `json:"success"`
+	Changed       bool   `json:"changed"` // the new provider serves a different patch
+	Patch         string `json:"patch"`
+	PreviousPatch string `json:"previousPatch"`
+	InProgress    bool   `json:"inProgress,omitempty"` // another update was already running; nothing was done
+	Error         string `json:"error,omitempty"`
+}
+
+// statsUpdateInProgress is the error reported to a caller that overlaps a running update
+const statsUpdateInProgress = "Stats update already in progress"
+
+// ForceStatsUpdate builds a fresh stats provider and swaps it in once the patch is known.
+// In-flight readers keep using the old provider until they finish.
 func (a *App) ForceStatsUpdate() string {
-	if a.statsProvider == nil {
-		return "Stats provider not initialized"
+	result := a.UpdateStats()
+	switch {
+	case !result.Success:
+		return result.Error
+	case result.Changed:
+		return fmt.Sprintf("Cache cleared, using patch %s (was %s)", result.Patch, result.PreviousPatch)
+	default:
+		return fmt.Sprintf("Cache cleared, using patch %s", result.Patch)
+	}
+}
+
+// UpdateStats builds a complete replacement provider and swaps it in only if every step
+// succeeds, so a failed update leaves the current provider serving as before. Only one
+// update runs at a time; an overlapping call returns InProgress without loading anything.
+func (a *App) UpdateStats() StatsUpdateResult {
+	current := a.stats()
+	if current == nil {
+		return StatsUpdateResult{Error: "Stats provider not initialized"}
+	}
+	if !a.statsUpdating.CompareAndSwap(false, true) {
+		return StatsUpdateResult{PreviousPatch: current.GetPatch(), InProgress: true, Error: statsUpdateInProgress}
+	}
+	defer a.statsUpdating.Store(false)
+	result := StatsUpdateResult{PreviousPatch: current.GetPatch()}
+
+	load := a.statsLoader
+	if load == nil {
+		load = a.loadStatsProvider
+	}
+	provider, err := load()
+	if err != nil {
+		result.Error = fmt.Sprintf("Failed to refresh: %v", err)
+		return result
+	}
+
+	a.setStatsProvider(provider)
+	result.Success = true
+	result.Patch = provider.GetPatch()
+	result.Changed = result.Patch != result.PreviousPatch
+	if result.Changed {
+		a.emitMetaReady()
+	}
+	return result
+}
+
+// loadStatsProvider creates a provider against Turso with a cleared cache and a known patch
+func (a *App) loadStatsProvider() (*data.StatsProvider, error) {
+	if a.tursoClient == nil {
+		return nil, fmt.Errorf("no database connection")
+	}
+
+	provider, err := data.NewStatsProvider(a.tursoClient)
+	if err != nil {
+		return nil, err
 	}
 
 	// Clear the query cache
-	a.statsProvider.ClearCache()
+	provider.ClearCache()
 
 	// Refetch patch info
-	if err := a.statsProvider.FetchPatch(); err != nil {
-		return fmt.Sprintf("Failed to refresh: %v", err)
+	if err := provider.FetchPatch(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// cacheClearer is anything holding cached data that can be dropped on demand
+type cacheClearer interface {
+	ClearCache()
+}
+
+// clearFunc adapts a plain function to cacheClearer
+type clearFunc func()
+
+func (f clearFunc) ClearCache() { f() }
+
+// ClearAllCaches drops every local cache so the next lookups hit the database again.
+// The stats provider holds the only query cache; the App itself just remembers which
+// champ select lookups it already emitted, and forgets them so current picks refetch.
+func (a *App) ClearAllCaches() string {
+	clearers := []cacheClearer{clearFunc(func() { a.refetchRequested.Store(true) })}
+	if stats := a.stats(); stats != nil {
+		clearers = append(clearers, stats)
 	}
+	return clearCaches(clearers)
+}
 
-	return fmt.Sprintf("Cache cleared, using patch %s", a.statsProvider.GetPatch())
+// clearCaches clears each cache once and returns a short status
+func clearCaches(clearers []cacheClearer) string {
+	if len(clearers) == 0 {
+		return "No caches to clear"
+	}
+	for _, c := range clearers {
+		c.ClearCache()
+	}
+	return fmt.Sprintf("Cleared %d cache(s)", len(clearers))
 }
 
 // GetPersonalStats returns aggregated personal stats from recent match history
@@ -37,5 +245,177 @@ func (a *App) GetPersonalStats() *lcu.PersonalStats {
 		return emptyStats
 	}
 
-	return lcu.CalculatePersonalStats(history, a.champions)
+	return lcu.CalculatePersonalStats(history, a.champions, a.currentPUUID)
+}
+
+// personalStatsCardChampions is how many champions appear on the shareable card
+const personalStatsCardChampions = 3
+
+// PersonalStatsCardChampion is one champion on the shareable stats card
+type PersonalStatsCardChampion struct {
+	ChampionID   int     `json:"championId"`
+	ChampionName string  `json:"championName"`
+	Games        int     `json:"games"`
+	WinRate      float64 `json:"winRate"`
+}
+
+// PersonalStatsCard is a compact recent-form summary for rendering a shareable image
+type PersonalStatsCard struct {
+	Games        int                         `json:"games"`
+	Wins         int                         `json:"wins"`
+	Losses       int                         `json:"losses"`
+	WinRate      float64                     `json:"winRate"`
+	KDA          float64                     `json:"kda"`
+	Streak       int                         `json:"streak"` // + for wins, - for losses
+	TopChampions []PersonalStatsCardChampion `json:"topChampions"`
+}
+
+// ExportPersonalStatsCard returns the recent-form summary as JSON, or "" without data
+func (a *App) ExportPersonalStatsCard() string {
+	if !a.lcuClient.IsConnected() {
+		return ""
+	}
+
+	history, err := a.lcuClient.FetchMatchHistory(20)
+	if err != nil {
+		fmt.Printf("Failed to fetch match history: %v\n", err)
+		return ""
+	}
+
+	stats := lcu.CalculatePersonalStats(history, a.champions, a.currentPUUID)
+	if !stats.HasData {
+		return ""
+	}
+
+	card, err := json.Marshal(buildPersonalStatsCard(stats, lcu.CurrentStreak(history, a.currentPUUID)))
+	if err != nil {
+		return ""
+	}
+	return string(card)
+}
+
+// buildPersonalStatsCard condenses personal stats into the card, rounding for display.
+// Champions are ordered by games then ID so the output is stable for the same input.
+func buildPersonalStatsCard(stats *lcu.PersonalStats, streak int) PersonalStatsCard {
+	card := PersonalStatsCard{
+		Games:        stats.TotalGames,
+		Wins:         stats.Wins,
+		Losses:       stats.Losses,
+		WinRate:      roundTo1(stats.WinRate),
+		KDA:          roundTo1(stats.AvgKDA),
+		Streak:       streak,
+		TopChampions: []PersonalStatsCardChampion{},
+	}
+
+	champs := append([]lcu.ChampionPersonalStats(nil), stats.ChampionStats...)
+	sort.SliceStable(champs, func(i, j int) bool {
+		if champs[i].Games != champs[j].Games {
+			return champs[i].Games > champs[j].Games
+		}
+		return champs[i].ChampionId < champs[j].ChampionId
+	})
+	for _, c := range champs[:min(personalStatsCardChampions, len(champs))] {
+		card.TopChampions = append(card.TopChampions, PersonalStatsCardChampion{
+			ChampionID:   c.ChampionId,
+			ChampionName: c.ChampionName,
+			Games:        c.Games,
+			WinRate:      roundTo1(c.WinRate),
+		})
+	}
+	return card
+}
+
+// roundTo1 rounds to one decimal place
+func roundTo1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// championPoolMetaSize is how many top champions per role count as "in the meta"
+const championPoolMetaSize = 10
+
+// ChampionPoolEntry compares one of the player's most-played champions against the meta
+type ChampionPoolEntry struct {
+	ChampionID    int     `json:"championId"`
+	ChampionName  string  `json:"championName"`
+	IconURL       string  `json:"iconURL"`
+	Role          string  `json:"role"`
+	Games         int     `json:"games"`
+	WinRate       float64 `json:"winRate"`
+	MetaRank      int     `json:"metaRank"` // 1-based rank in the role's top list, 0 if absent
+	MetaWinRate   float64 `json:"metaWinRate"`
+	WeakThisPatch bool    `json:"weakThisPatch"`
+}
+
+// ChampionPoolReport is the player's champion pool checked against the current patch
+type ChampionPoolReport struct {
+	HasData   bool                `json:"hasData"`
+	Patch     string              `json:"patch"`
+	Champions []ChampionPoolEntry `json:"champions"`
+}
+
+// GetChampionPoolReport cross-references the player's most-played champions with the
+// current meta, flagging mains that fall outside their role's top champions.
+func (a *App) GetChampionPoolReport() ChampionPoolReport {
+	report := ChampionPoolReport{Champions: []ChampionPoolEntry{}}
+
+	stats := a.stats()
+	if stats == nil || !a.lcuClient.IsConnected() {
+		return report
+	}
+
+	history, err := a.lcuClient.FetchMatchHistory(20)
+	if err != nil {
+		fmt.Printf("Failed to fetch match history: %v\n", err)
+		return report
+	}
+
+	meta, err := stats.FetchAllRolesTopChampions(championPoolMetaSize)
+	if err != nil {
+		fmt.Printf("Failed to fetch meta champions: %v\n", err)
+		return report
+	}
+
+	report = buildChampionPoolReport(lcu.CalculatePersonalStats(history, a.champions, a.currentPUUID), meta)
+	report.Patch = stats.GetPatch()
+	return report
+}
+
+// buildChampionPoolReport matches each personal champion against its role's meta list
+func buildChampionPoolReport(personal *lcu.PersonalStats, meta map[string][]data.ChampionWinRate) ChampionPoolReport {
+	report := ChampionPoolReport{Champions: []ChampionPoolEntry{}}
+	if personal == nil || !personal.HasData {
+		return report
+	}
+
+	for _, champ := range personal.ChampionStats {
+		entry := ChampionPoolEntry{
+			ChampionID:    champ.ChampionId,
+			ChampionName:  champ.ChampionName,
+			IconURL:       champ.IconURL,
+			Role:          champ.Role,
+			Games:         champ.Games,
+			WinRate:       champ.WinRate,
+			WeakThisPatch: true,
+		}
+		for i, m := range meta[personalRoleToMetaRole(champ.Role)] {
+			if m.ChampionID == champ.ChampionId {
+				entry.MetaRank = i + 1
+				entry.MetaWinRate = m.WinRate
+				entry.WeakThisPatch = false
+				break
+			}
+		}
+		report.Champions = append(report.Champions, entry)
+	}
+
+	report.HasData = len(report.Champions) > 0
+	return report
+}
+
+// personalRoleToMetaRole maps match-history roles (TOP, MID, ADC...) to meta role keys
+func personalRoleToMetaRole(role string) string {
+	if r := roles.Parse(role); r != roles.Unknown {
+		return r.String()
+	}
+	return strings.ToLower(role)
 }
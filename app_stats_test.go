@@ -0,0 +1,356 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ghostdraft/internal/data"
+	"ghostdraft/internal/lcu"
+)
+
+func TestStatsProviderSwap_ConcurrentReaders(t *testing.T) {
+	oldProvider, _ := data.NewStatsProvider(nil)
+	newProvider, _ := data.NewStatsProvider(nil)
+
+	app := &App{}
+	app.setStatsProvider(oldProvider)
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				// A single snapshot must be used consistently for the whole read
+				stats := app.stats()
+				if stats != oldProvider && stats != newProvider {
+					errs <- "reader saw an unexpected provider"
+					return
+				}
+				_ = stats.GetPatch()
+			}
+		}()
+	}
+
+	app.setStatsProvider(newProvider)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if app.stats() != newProvider {
+		t.Error("expected new provider after swap")
+	}
+}
+
+func TestBuildChampionPoolReport_FlagsOffMetaMain(t *testing.T) {
+	personal := &lcu.PersonalStats{
+		HasData: true,
+		ChampionStats: []lcu.ChampionPersonalStats{
+			{ChampionId: 103, ChampionName: "Ahri", Role: "MID", Games: 12, WinRate: 58},
+			{ChampionId: 222, ChampionName: "Jinx", Role: "ADC", Games: 5, WinRate: 40},
+		},
+	}
+	meta := map[string][]data.ChampionWinRate{
+		"middle": {{ChampionID: 7, WinRate: 54}, {ChampionID: 103, WinRate: 52.5}},
+		"bottom": {{ChampionID: 51, WinRate: 53}},
+	}
+
+	report := buildChampionPoolReport(personal, meta)
+	if !report.HasData || len(report.Champions) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", report)
+	}
+
+	ahri := report.Champions[0]
+	if ahri.WeakThisPatch || ahri.MetaRank != 2 || ahri.MetaWinRate != 52.5 {
+		t.Errorf("Ahri is in the mid meta: got %+v", ahri)
+	}
+
+	jinx := report.Champions[1]
+	if !jinx.WeakThisPatch || jinx.MetaRank != 0 {
+		t.Errorf("Jinx is out of the bot meta and should be flagged: got %+v", jinx)
+	}
+}
+
+type countingCache struct{ clears int }
+
+func (c *countingCache) ClearCache() { c.clears++ }
+
+func TestClearCaches_ClearsEachOnce(t *testing.T) {
+	first, second := &countingCache{}, &countingCache{}
+
+	status := clearCaches([]cacheClearer{first, second})
+
+	if first.clears != 1 || second.clears != 1 {
+		t.Errorf("clear counts = %d, %d; want 1, 1", first.clears, second.clears)
+	}
+	if status != "Cleared 2 cache(s)" {
+		t.Errorf("status = %q", status)
+	}
+}
+
+func TestClearAllCaches_NoProvider(t *testing.T) {
+	app := &App{}
+	if got := app.ClearAllCaches(); got != "Cleared 1 cache(s)" {
+		t.Errorf("status = %q, want %q", got, "Cleared 1 cache(s)")
+	}
+	if !app.refetchRequested.Load() {
+		t.Error("champ select fetch keys should still be reset without a provider")
+	}
+}
+
+func TestClearAllCaches_ClearsProviderAndRequestsRefetch(t *testing.T) {
+	app := &App{}
+	provider, _ := data.NewStatsProvider(nil)
+	app.setStatsProvider(provider)
+
+	if got := app.ClearAllCaches(); got != "Cleared 2 cache(s)" {
+		t.Errorf("status = %q, want %q", got, "Cleared 2 cache(s)")
+	}
+	if !app.refetchRequested.Load() {
+		t.Error("ClearAllCaches should request a fetch key reset")
+	}
+}
+
+func TestRefreshStatsIfStale(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	refreshed := make(chan struct{}, 1)
+	app := &App{
+		clock: func() time.Time { return now },
+		statsUpdater: func() string {
+			refreshed <- struct{}{}
+			return "ok"
+		},
+	}
+	provider, _ := data.NewStatsProvider(nil)
+	app.setStatsProvider(provider)
+	app.SetStatsMaxAge(60)
+
+	now = now.Add(30 * time.Minute)
+	if app.refreshStatsIfStale() {
+		t.Error("fresh stats should not schedule a refresh")
+	}
+
+	now = now.Add(time.Hour)
+	if !app.refreshStatsIfStale() {
+		t.Fatal("stale stats should schedule a refresh")
+	}
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("updater was not called")
+	}
+
+	app.SetStatsMaxAge(-1)
+	if app.refreshStatsIfStale() {
+		t.Error("disabled auto refresh should not schedule a refresh")
+	}
+}
+
+func TestRefreshStatsIfStale_BacksOffAfterFailedLoad(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		now = now.Add(d)
+		mu.Unlock()
+	}
+
+	var loads atomic.Int32
+	app := &App{
+		clock: clock,
+		statsLoader: func() (*data.StatsProvider, error) {
+			loads.Add(1)
+			return nil, errors.New("database unreachable")
+		},
+	}
+	provider, _ := data.NewStatsProvider(nil)
+	app.setStatsProvider(provider)
+	app.SetStatsMaxAge(60)
+
+	// waitIdle blocks until the background refresh has finished
+	waitIdle := func() {
+		deadline := time.Now().Add(time.Second)
+		for app.statsRefreshing.Load() {
+			if time.Now().After(deadline) {
+				t.Fatal("background refresh did not finish")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	advance(2 * time.Hour)
+	if !app.refreshStatsIfStale() {
+		t.Fatal("stale stats should schedule a refresh")
+	}
+	waitIdle()
+	if app.stats() != provider {
+		t.Fatal("failed refresh replaced the provider")
+	}
+
+	// Still stale, but every lookup inside the retry interval must not reload
+	for i := 0; i < 5; i++ {
+		advance(time.Second)
+		if app.refreshStatsIfStale() {
+			t.Fatal("refresh retried inside the retry interval after a failure")
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+
+	advance(statsRefreshRetryInterval)
+	if !app.refreshStatsIfStale() {
+		t.Fatal("refresh should be retried once the retry interval has passed")
+	}
+	waitIdle()
+	if n := loads.Load(); n != 2 {
+		t.Errorf("loader called %d times, want 2", n)
+	}
+}
+
+func TestBuildPersonalStatsCard_StableSummary(t *testing.T) {
+	stats := &lcu.PersonalStats{
+		HasData:    true,
+		TotalGames: 10,
+		Wins:       6,
+		Losses:     4,
+		WinRate:    60,
+		AvgKDA:     3.456,
+		ChampionStats: []lcu.ChampionPersonalStats{
+			{ChampionId: 238, ChampionName: "Zed", Games: 2, WinRate: 50},
+			{ChampionId: 103, ChampionName: "Ahri", Games: 5, WinRate: 66.666},
+			{ChampionId: 7, ChampionName: "LeBlanc", Games: 2, WinRate: 0},
+			{ChampionId: 1, ChampionName: "Annie", Games: 1, WinRate: 100},
+		},
+	}
+
+	first, err := json.Marshal(buildPersonalStatsCard(stats, 3))
+	if err != nil {
+		t.Fatalf("marshal card: %v", err)
+	}
+	second, _ := json.Marshal(buildPersonalStatsCard(stats, 3))
+	if string(first) != string(second) {
+		t.Errorf("card not stable:\n%s\n%s", first, second)
+	}
+
+	var card map[string]interface{}
+	if err := json.Unmarshal(first, &card); err != nil {
+		t.Fatalf("unmarshal card: %v", err)
+	}
+	for _, field := range []string{"games", "wins", "losses", "winRate", "kda", "streak", "topChampions"} {
+		if _, ok := card[field]; !ok {
+			t.Errorf("card missing %q: %s", field, first)
+		}
+	}
+	if card["kda"] != 3.5 || card["streak"] != 3.0 {
+		t.Errorf("kda/streak: got %v/%v, want 3.5/3", card["kda"], card["streak"])
+	}
+
+	top := card["topChampions"].([]interface{})
+	if len(top) != 3 {
+		t.Fatalf("got %d top champions, want 3", len(top))
+	}
+	// Ties on games are broken by champion ID
+	wantIDs := []float64{103, 7, 238}
+	for i, c := range top {
+		if id := c.(map[string]interface{})["championId"]; id != wantIDs[i] {
+			t.Errorf("top[%d] = %v, want %v", i, id, wantIDs[i])
+		}
+	}
+}
+
+func TestUpdateStats_FailureKeepsPreviousProvider(t *testing.T) {
+	previous, _ := data.NewStatsProvider(nil)
+	app := &App{
+		statsLoader: func() (*data.StatsProvider, error) {
+			return nil, errors.New("connection reset mid-update")
+		},
+	}
+	app.setStatsProvider(previous)
+
+	result := app.UpdateStats()
+	if result.Success || result.Changed || result.Error == "" {
+		t.Errorf("failed update reported as %+v", result)
+	}
+	if app.stats() != previous {
+		t.Fatal("failed update replaced the previous provider")
+	}
+	if got := app.ForceStatsUpdate(); got != "Failed to refresh: connection reset mid-update" {
+		t.Errorf("ForceStatsUpdate = %q", got)
+	}
+
+	next, _ := data.NewStatsProvider(nil)
+	app.statsLoader = func() (*data.StatsProvider, error) { return next, nil }
+	result = app.UpdateStats()
+	if !result.Success || result.Changed {
+		t.Errorf("same-patch update: got %+v, want success without change", result)
+	}
+	if app.stats() != next {
+		t.Error("successful update should swap in the new provider")
+	}
+}
+
+func TestUpdateStats_NotInitialized(t *testing.T) {
+	app := &App{}
+	if result := app.UpdateStats(); result.Success || result.Error != "Stats provider not initialized" {
+		t.Errorf("got %+v", result)
+	}
+}
+
+func TestForceStatsUpdate_ConcurrentCallReportsInProgress(t *testing.T) {
+	previous, _ := data.NewStatsProvider(nil)
+	next, _ := data.NewStatsProvider(nil)
+
+	var loads atomic.Int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	app := &App{
+		statsLoader: func() (*data.StatsProvider, error) {
+			loads.Add(1)
+			close(entered)
+			<-release
+			return next, nil
+		},
+	}
+	app.setStatsProvider(previous)
+
+	first := make(chan string)
+	go func() { first <- app.ForceStatsUpdate() }()
+	<-entered
+
+	if got := app.ForceStatsUpdate(); got != statsUpdateInProgress {
+		t.Errorf("overlapping ForceStatsUpdate = %q, want %q", got, statsUpdateInProgress)
+	}
+	if result := app.UpdateStats(); !result.InProgress || result.Success {
+		t.Errorf("overlapping UpdateStats = %+v, want InProgress", result)
+	}
+
+	close(release)
+	if got := <-first; got == statsUpdateInProgress {
+		t.Errorf("first ForceStatsUpdate = %q, want it to run", got)
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("provider loaded %d times, want 1", n)
+	}
+	if app.stats() != next {
+		t.Error("first update should have swapped in the new provider")
+	}
+
+	// The guard is released once the update finishes
+	app.statsLoader = func() (*data.StatsProvider, error) { return next, nil }
+	if result := app.UpdateStats(); !result.Success {
+		t.Errorf("update after the first finished = %+v, want success", result)
+	}
+}
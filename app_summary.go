@@ -0,0 +1,128 @@
+package main
+
+import (
+	"ghostdraft/internal/data"
+)
+
+// summaryCounterPicks and summaryBans match the list sizes of the per-panel events
+const (
+	summaryCounterPicks = 6
+	summaryBans         = 5
+)
+
+// champSelectStats is the part of the stats provider the champ select summary reads
+type champSelectStats interface {
+	GetPatch() string
+	FetchChampionData(championID int, championName string, role string) (*data.BuildData, error)
+	FetchAllMatchups(championID int, role string) ([]data.MatchupStat, error)
+	FetchCounterPicks(enemyChampionID int, role string, minGames, limit int) ([]data.MatchupStat, error)
+	FetchCounterMatchups(championID int, role string, limit int) ([]data.MatchupStat, error)
+}
+
+// ChampSelectMatchup is the lane matchup section of the summary
+type ChampSelectMatchup struct {
+	HasOpponent bool    `json:"hasOpponent"`
+	EnemyID     int     `json:"enemyId"`
+	EnemyName   string  `json:"enemyName"`
+	EnemyIcon   string  `json:"enemyIcon"`
+	WinRate     float64 `json:"winRate"`
+	Games       int     `json:"games"`
+	Status      string  `json:"status"` // winning, losing or even
+}
+
+// ChampSelectBan is a recommended ban: one of the champion's hardest counters
+type ChampSelectBan struct {
+	ChampionID   int     `json:"championId"`
+	ChampionName string  `json:"championName"`
+	IconURL      string  `json:"iconURL"`
+	DamageType   string  `json:"damageType"`
+	WinRate      float64 `json:"winRate"`
+	Games        int     `json:"games"`
+}
+
+// ChampSelectSummary combines the build, lane matchup, counter picks and bans panels
+// so the UI can render champ select from one consistent snapshot
+type ChampSelectSummary struct {
+	HasData      bool                    `json:"hasData"`
+	ChampionID   int                     `json:"championId"`
+	ChampionName string                  `json:"championName"`
+	Role         string                  `json:"role"`
+	Patch        string                  `json:"patch"`
+	Build        ChampionBuildData       `json:"build"`
+	Matchup      ChampSelectMatchup      `json:"matchup"`
+	Counters     []ChampionDetailMatchup `json:"counters"` // picks that beat the lane opponent
+	Bans         []ChampSelectBan        `json:"bans"`
+}
+
+// GetChampSelectSummary returns every champ select panel for the pick in one call
+func (a *App) GetChampSelectSummary(myChampionID int, role string, enemyChampionIDs []int) ChampSelectSummary {
+	a.refreshStatsIfStale()
+	if stats := a.stats(); stats != nil {
+		return a.champSelectSummary(stats, myChampionID, role, enemyChampionIDs)
+	}
+	return a.champSelectSummary(nil, myChampionID, role, enemyChampionIDs)
+}
+
+// champSelectSummary builds the summary from a single provider so all sections agree
+func (a *App) champSelectSummary(stats champSelectStats, championID int, role string, enemyChampionIDs []int) ChampSelectSummary {
+	championName := a.champions.GetName(championID)
+	summary := ChampSelectSummary{
+		ChampionID:   championID,
+		ChampionName: championName,
+		Role:         role,
+		Build:        a.championBuild(nil, championID, role),
+		Counters:     []ChampionDetailMatchup{},
+		Bans:         []ChampSelectBan{},
+	}
+	if stats == nil {
+		return summary
+	}
+	summary.HasData = true
+	summary.Patch = stats.GetPatch()
+
+	if buildData, err := stats.FetchChampionData(championID, championName, role); err == nil {
+		summary.Build = a.championBuild(buildData, championID, role)
+	}
+
+	if matchups, err := stats.FetchAllMatchups(championID, role); err == nil {
+		if m := findLaneOpponent(enemyChampionIDs, matchups); m.EnemyChampionID > 0 {
+			summary.Matchup = ChampSelectMatchup{
+				HasOpponent: true,
+				EnemyID:     m.EnemyChampionID,
+				EnemyName:   a.champions.GetName(m.EnemyChampionID),
+				EnemyIcon:   a.champions.GetIconURL(m.EnemyChampionID),
+				WinRate:     m.WinRate,
+				Games:       m.Matches,
+				Status:      a.matchupBand().classify(m.WinRate),
+			}
+		}
+	}
+
+	if summary.Matchup.HasOpponent {
+		picks, _ := stats.FetchCounterPicks(summary.Matchup.EnemyID, role, data.DefaultCounterPickMinGames, summaryCounterPicks)
+		for _, m := range picks {
+			summary.Counters = append(summary.Counters, ChampionDetailMatchup{
+				ChampionID:   m.EnemyChampionID,
+				ChampionName: a.champions.GetName(m.EnemyChampionID),
+				IconURL:      a.champions.GetIconURL(m.EnemyChampionID),
+				WinRate:      m.WinRate,
+				Games:        m.Matches,
+			})
+		}
+	}
+
+	counters, _ := stats.FetchCounterMatchups(championID, role, summaryBans)
+	for _, m := range counters {
+		name := a.champions.GetName(m.EnemyChampionID)
+		summary.Bans = append(summary.Bans, ChampSelectBan{
+			ChampionID:   m.EnemyChampionID,
+			ChampionName: name,
+			IconURL:      a.champions.GetIconURL(m.EnemyChampionID),
+			DamageType:   a.getDamageType(name),
+			WinRate:      m.WinRate,
+			Games:        m.Matches,
+		})
+	}
+
+	return summary
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"ghostdraft/internal/data"
+	"ghostdraft/internal/lcu"
+)
+
+// fakeChampSelectStats serves one fixed dataset: Ahri mid vs Zed
+type fakeChampSelectStats struct{}
+
+func (fakeChampSelectStats) GetPatch() string { return "15.24" }
+
+func (fakeChampSelectStats) FetchChampionData(championID int, championName string, role string) (*data.BuildData, error) {
+	return &data.BuildData{
+		ChampionID:   championID,
+		ChampionName: championName,
+		Role:         role,
+		ResolvedRole: role,
+		Games:        100,
+		Source:       data.BuildSourceLocal,
+		Builds:       []data.BuildPath{{CoreItems: []int{3089, 3020}, WinRate: 52, Games: 100}},
+	}, nil
+}
+
+func (fakeChampSelectStats) FetchAllMatchups(championID int, role string) ([]data.MatchupStat, error) {
+	return []data.MatchupStat{
+		{EnemyChampionID: 238, Wins: 190, WinRate: 47.5, Matches: 400},
+		{EnemyChampionID: 7, Wins: 27, WinRate: 55, Matches: 50},
+	}, nil
+}
+
+func (fakeChampSelectStats) FetchCounterPicks(enemyChampionID int, role string, minGames, limit int) ([]data.MatchupStat, error) {
+	if enemyChampionID != 238 {
+		return nil, nil
+	}
+	return []data.MatchupStat{{EnemyChampionID: 1, WinRate: 56, Matches: 200}}, nil
+}
+
+func (fakeChampSelectStats) FetchCounterMatchups(championID int, role string, limit int) ([]data.MatchupStat, error) {
+	return []data.MatchupStat{{EnemyChampionID: 238, WinRate: 47.5, Matches: 400}}, nil
+}
+
+func TestChampSelectSummary_PopulatesAllSections(t *testing.T) {
+	app := &App{champions: lcu.NewChampionRegistry(), items: lcu.NewItemRegistry()}
+
+	summary := app.champSelectSummary(fakeChampSelectStats{}, 103, "middle", []int{7, 238})
+
+	if !summary.HasData || summary.Patch != "15.24" {
+		t.Errorf("got hasData=%v patch=%q, want true 15.24", summary.HasData, summary.Patch)
+	}
+	if !summary.Build.HasItems || summary.Build.Games != 100 || len(summary.Build.Builds) != 1 {
+		t.Errorf("build section not populated: %+v", summary.Build)
+	}
+
+	m := summary.Matchup
+	if !m.HasOpponent || m.EnemyID != 238 || m.Games != 400 || m.Status != "losing" {
+		t.Errorf("matchup: got %+v, want Zed with 400 games, losing", m)
+	}
+	if len(summary.Counters) != 1 || summary.Counters[0].ChampionID != 1 {
+		t.Errorf("counters should be picks vs the lane opponent: %+v", summary.Counters)
+	}
+	if len(summary.Bans) != 1 || summary.Bans[0].ChampionID != 238 || summary.Bans[0].DamageType != "Unknown" {
+		t.Errorf("bans: got %+v", summary.Bans)
+	}
+}
+
+func TestChampSelectSummary_NoStats(t *testing.T) {
+	app := &App{champions: lcu.NewChampionRegistry(), items: lcu.NewItemRegistry()}
+
+	summary := app.champSelectSummary(nil, 103, "middle", []int{238})
+
+	if summary.HasData || summary.Matchup.HasOpponent || summary.Build.HasItems {
+		t.Errorf("expected an empty summary, got %+v", summary)
+	}
+	if summary.Counters == nil || summary.Bans == nil {
+		t.Error("empty sections should be non-nil for the frontend")
+	}
+}
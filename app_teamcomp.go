@@ -17,14 +17,11 @@ type TeamCompData struct {
 	Archetype string
 	HasTank   bool
 	HasPick   bool // Single-target CC (hooks, roots)
+	Known     int  // Champions the champion DB had data for
 }
 
 // analyzeTeamComp checks team damage balance and emits recommendation
 func (a *App) analyzeTeamComp(session *lcu.ChampSelectSession, localChampID int) {
-	if a.championDB == nil {
-		fmt.Println("Team comp: championDB is nil!")
-		return
-	}
 	fmt.Println("Analyzing team comp...")
 
 	var apCount, adCount, mixedCount int
@@ -55,7 +52,7 @@ func (a *App) analyzeTeamComp(session *lcu.ChampSelectSession, localChampID int)
 
 		// Count teammate's champion damage type
 		champName := a.champions.GetName(player.ChampionID)
-		dmgType := a.championDB.GetDamageType(champName)
+		dmgType := a.getDamageType(champName)
 
 		fmt.Printf("  Teammate %s: %s\n", champName, dmgType)
 
@@ -137,10 +134,6 @@ func (a *App) analyzeTeamComp(session *lcu.ChampSelectSession, localChampID int)
 
 // analyzeFullComp analyzes both teams when all players have locked in
 func (a *App) analyzeFullComp(session *lcu.ChampSelectSession) {
-	if a.championDB == nil {
-		return
-	}
-
 	// Check if all players have locked in
 	allLocked := true
 	for _, player := range session.MyTeam {
@@ -167,6 +160,14 @@ func (a *App) analyzeFullComp(session *lcu.ChampSelectSession) {
 	allyComp := a.analyzeTeamTags(session.MyTeam)
 	enemyComp := a.analyzeTeamTags(session.TheirTeam)
 
+	// Nothing known about any pick (e.g. no champion DB): no analysis to show
+	if allyComp.Known == 0 && enemyComp.Known == 0 {
+		runtime.EventsEmit(a.ctx, "fullcomp:update", map[string]interface{}{
+			"ready": false,
+		})
+		return
+	}
+
 	// Calculate damage percentages
 	allyTotal := allyComp.AP + allyComp.AD
 	enemyTotal := enemyComp.AP + enemyComp.AD
@@ -210,24 +211,25 @@ func (a *App) analyzeTeamTags(team []lcu.ChampSelectPlayer) TeamCompData {
 		}
 
 		champName := a.champions.GetName(player.ChampionID)
-		info, _ := a.championDB.GetChampion(champName)
-		if info == nil {
+		dmgType := a.getDamageType(champName)
+		if dmgType == unknownDamageType {
 			continue
 		}
+		comp.Known++
 
 		// Count damage type
-		if strings.Contains(info.DamageType, "AP") {
+		if strings.Contains(dmgType, "AP") {
 			comp.AP++
 		}
-		if strings.Contains(info.DamageType, "AD") {
+		if strings.Contains(dmgType, "AD") {
 			comp.AD++
 		}
-		if info.DamageType == "Tank" {
+		if dmgType == "Tank" {
 			comp.HasTank = true
 		}
 
 		// Count role tags
-		tags := info.RoleTags
+		tags := a.getRoleTags(champName)
 		for _, tag := range strings.Split(tags, ", ") {
 			tag = strings.TrimSpace(tag)
 			// Normalize tags - extract base tag
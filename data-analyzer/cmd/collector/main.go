@@ -42,8 +42,16 @@ func main() {
 	puuid := flag.String("puuid", "", "Starting PUUID")
 	matchCount := flag.Int("count", 20, "Number of matches to fetch per player")
 	maxPlayers := flag.Int("max-players", 100, "Maximum unique players to collect")
+	seed := flag.Int64("seed", 0, "Seed for timeline sampling, for reproducible runs (0 seeds from the clock)")
 	flag.Parse()
 
+	// Every random draw comes from rng, so a fixed -seed repeats the same sample
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+	fmt.Printf("Sampling seed: %d\n", *seed)
+
 	// Get blob storage path from env (required)
 	dataDir := os.Getenv("BLOB_STORAGE_PATH")
 	if dataDir == "" {
@@ -210,7 +218,7 @@ func main() {
 
 			// Fetch timeline for 20% of matches (statistical sampling for build order data)
 			var buildOrders map[int][]int
-			if rand.Float64() < timelineSamplingRate {
+			if rng.Float64() < timelineSamplingRate {
 				timeline, err := client.GetTimeline(ctx, matchID)
 				if err != nil {
 					log.Printf("    [Timeline] Failed to fetch: %v", err)
@@ -232,13 +240,17 @@ func main() {
 					GameVersion:  match.Info.GameVersion,
 					GameDuration: match.Info.GameDuration,
 					GameCreation: match.Info.GameCreation,
+					QueueID:      match.Info.QueueID,
 					PUUID:        participant.PUUID,
 					GameName:     participant.RiotIdGameName,
 					TagLine:      participant.RiotIdTagline,
 					ChampionID:   participant.ChampionID,
 					ChampionName: participant.ChampionName,
 					TeamPosition: participant.TeamPosition,
+					TeamID:       participant.TeamID,
 					Win:          participant.Win,
+					Summoner1ID:  participant.Summoner1ID,
+					Summoner2ID:  participant.Summoner2ID,
 					Item0:        participant.Item0,
 					Item1:        participant.Item1,
 					Item2:        participant.Item2,
@@ -120,6 +120,12 @@ func runContinuousMode() {
 		log.Fatal("RIOT_API_KEY environment variable is required")
 	}
 
+	// Load tunables from COLLECTOR_CONFIG (optional JSON file) and the environment
+	collectorConfig, err := collector.LoadCollectorConfig(os.Getenv("COLLECTOR_CONFIG"), os.Getenv)
+	if err != nil {
+		log.Fatalf("Collector config: %v", err)
+	}
+
 	// Get storage path
 	storagePath := os.Getenv("BLOB_STORAGE_PATH")
 	if storagePath == "" {
@@ -140,6 +146,7 @@ func runContinuousMode() {
 	tursoToken := os.Getenv("TURSO_AUTH_TOKEN")
 	var tursoClient *db.TursoClient
 	var tursoPusher *collector.TursoPusher
+	var dataPusher collector.DataPusher // Underlying store, for the synchronous shutdown push
 	if tursoURL != "" {
 		var err error
 		tursoClient, err = db.NewTursoClient(tursoURL, tursoToken)
@@ -150,11 +157,23 @@ func runContinuousMode() {
 			defer tursoClient.Close()
 
 			// Create TursoPusher with adapter
-			dataPusher := collector.NewTursoDataPusher(tursoClient)
-			tursoPusher = collector.NewTursoPusher(dataPusher)
+			dataPusher = collector.NewTursoDataPusher(tursoClient)
+			tursoPusher = collector.NewTursoPusherWithBuffer(dataPusher, collectorConfig.PushBufferSize)
+			tursoPusher.SetDLQ(filepath.Join(storagePath, "dlq"))
+		}
+	} else if sqlitePath := os.Getenv("SQLITE_PATH"); sqlitePath != "" {
+		filePusher, err := collector.NewFilePusher(sqlitePath)
+		if err != nil {
+			log.Printf("Warning: Failed to open SQLite %s: %v (pushes will be skipped)", sqlitePath, err)
+		} else {
+			log.Printf("Turso: disabled, pushing to local SQLite %s", sqlitePath)
+			defer filePusher.Close()
+			dataPusher = filePusher
+			tursoPusher = collector.NewTursoPusherWithBuffer(filePusher, collectorConfig.PushBufferSize)
+			tursoPusher.SetDLQ(filepath.Join(storagePath, "dlq"))
 		}
 	} else {
-		log.Println("Turso: disabled (set TURSO_DATABASE_URL to enable)")
+		log.Println("Turso: disabled (set TURSO_DATABASE_URL or SQLITE_PATH to enable)")
 	}
 
 	// Create Riot API client
@@ -177,23 +196,12 @@ func runContinuousMode() {
 		log.Fatalf("Failed to create file rotator: %v", err)
 	}
 	defer rotator.Close()
+	rotator.SetRotationLimits(collectorConfig.RotateMaxMatches, collectorConfig.RotateMaxAge())
 
 	// Create the real Spider with continuous mode config
-	// Read config from environment variables (with defaults)
-	matchesPerPlayer := getEnvInt("MATCHES_PER_PLAYER", 20)
-	maxPlayers := getEnvInt("MAX_PLAYERS", 10000)
-	workerCount := getEnvInt("WORKER_COUNT", 1)
-	timelineSamplingRate := getEnvFloat("TIMELINE_SAMPLING_RATE", 0.20)
-
+	spiderConfig := collectorConfig.SpiderConfig()
 	log.Printf("Config: matches_per_player=%d, max_players=%d, workers=%d, timeline_rate=%.2f",
-		matchesPerPlayer, maxPlayers, workerCount, timelineSamplingRate)
-
-	spiderConfig := collector.SpiderConfig{
-		MatchesPerPlayer:     matchesPerPlayer,
-		MaxPlayers:           maxPlayers,
-		WorkerCount:          workerCount,
-		TimelineSamplingRate: timelineSamplingRate,
-	}
+		spiderConfig.MatchesPerPlayer, spiderConfig.MaxPlayers, spiderConfig.WorkerCount, spiderConfig.TimelineSamplingRate)
 	spider := collector.NewSpider(riotClient, rotator, currentPatch, spiderConfig)
 
 	// Create API key validator
@@ -332,6 +340,19 @@ func runContinuousMode() {
 	// Create reduce function using real components
 	warmDir := filepath.Join(storagePath, "warm")
 	coldDir := filepath.Join(storagePath, "cold")
+	coldCompressor, err := storage.CompressorByName(os.Getenv("COLD_COMPRESSION"))
+	if err != nil {
+		log.Fatalf("Invalid COLD_COMPRESSION: %v", err)
+	}
+	aggOpts := collector.DefaultAggregateOptions()
+	aggOpts.MaxBuildSlots = collectorConfig.MaxBuildSlots
+	aggOpts.MinCompletedItems = collectorConfig.MinCompletedItems
+	aggOpts.MinGameDurationSeconds = collectorConfig.MinGameDuration
+	aggOpts.Workers = collectorConfig.ReduceWorkers
+	aggOpts.SlotSampleInterval = collectorConfig.SlotSampleInterval
+	aggOpts.CountUnknownPosition = os.Getenv("COUNT_UNKNOWN_POSITION") == "true"
+	shardColdByPatch := os.Getenv("COLD_SHARD_BY_PATCH") == "true"
+
 	reduceFunc := func(reduceCtx context.Context) error {
 		log.Println("[Reduce] ========================================")
 		log.Println("[Reduce] Starting reduce cycle...")
@@ -339,7 +360,7 @@ func runContinuousMode() {
 		log.Printf("[Reduce] Cold directory: %s", coldDir)
 
 		// List warm directory contents before processing
-		warmFiles, _ := filepath.Glob(filepath.Join(warmDir, "*.jsonl"))
+		warmFiles, _ := filepath.Glob(filepath.Join(warmDir, collector.WarmFilePattern))
 		log.Printf("[Reduce] Found %d warm files to process", len(warmFiles))
 		for i, f := range warmFiles {
 			if info, err := os.Stat(f); err == nil {
@@ -354,15 +375,20 @@ func runContinuousMode() {
 		} else if rotated {
 			log.Println("[Reduce] Flushed hot file to warm")
 			// Re-check warm files after flush
-			warmFiles, _ = filepath.Glob(filepath.Join(warmDir, "*.jsonl"))
+			warmFiles, _ = filepath.Glob(filepath.Join(warmDir, collector.WarmFilePattern))
 			log.Printf("[Reduce] After flush: %d warm files", len(warmFiles))
 		} else {
 			log.Println("[Reduce] No hot file to flush (or empty)")
 		}
 
+		// Marker lets a restart detect and reconcile a reduce killed before archiving finished
+		if err := collector.MarkReduceStarted(warmDir); err != nil {
+			return fmt.Errorf("write reduce marker: %w", err)
+		}
+
 		// Aggregate warm files
 		log.Println("[Reduce] Aggregating warm files...")
-		agg, err := collector.AggregateWarmFiles(warmDir, riot.IsCompletedItem)
+		agg, err := collector.AggregateWarmFilesWithOptions(warmDir, riot.IsCompletedItem, aggOpts)
 		if err != nil {
 			log.Printf("[Reduce] ERROR: Aggregation failed: %v", err)
 			return fmt.Errorf("aggregation failed: %w", err)
@@ -370,15 +396,44 @@ func runContinuousMode() {
 
 		log.Printf("[Reduce] Aggregated %d files, %d records, patch %s",
 			agg.FilesProcessed, agg.TotalRecords, agg.DetectedPatch)
+		log.Printf("[Reduce] Aggregation took %v (%.0f records/sec)",
+			agg.TotalDuration.Round(time.Millisecond), agg.RecordsPerSecond())
+		for _, ft := range agg.FileTimings {
+			log.Printf("[Reduce]   %s: %d records in %v", filepath.Base(ft.Path), ft.Records, ft.Duration.Round(time.Millisecond))
+		}
 		log.Printf("[Reduce] Stats: %d champion stats, %d item stats, %d item slot stats, %d matchup stats",
 			len(agg.ChampionStats), len(agg.ItemStats), len(agg.ItemSlotStats), len(agg.MatchupStats))
+		log.Printf("[Reduce] Completed items per participant: %v", agg.ItemCompleteness)
+		log.Printf("[Reduce] Anomaly report: %s", agg.AnomalyReport())
+		for patch, versions := range agg.MergedPatchVersions() {
+			log.Printf("[Reduce] Patch %s merges %d game versions: %s", patch, len(versions), strings.Join(versions, ", "))
+		}
+		for patch, r := range agg.PatchTimeRanges {
+			log.Printf("[Reduce] Patch %s games played %s to %s", patch,
+				time.UnixMilli(r.First).UTC().Format(time.DateOnly), time.UnixMilli(r.Last).UTC().Format(time.DateOnly))
+		}
+		if cc != nil {
+			for patch, games := range cc.GetStats().GamesByPatch {
+				log.Printf("[Reduce] Games collected for patch %s: %d", patch, games)
+			}
+		}
 
 		// Archive warm files to cold
-		archived, err := collector.ArchiveWarmToCold(warmDir, coldDir)
+		archive := collector.ArchiveWarmToColdCounted
+		if shardColdByPatch {
+			archive = collector.ArchiveWarmToColdByPatchCounted
+		}
+		archived, err := archive(warmDir, coldDir, coldCompressor)
 		if err != nil {
 			return fmt.Errorf("archiving failed: %w", err)
 		}
-		log.Printf("[Reduce] Archived %d files to cold storage", archived)
+		log.Printf("[Reduce] Archived %d files (%d lines) to cold storage", archived.Files, archived.Lines)
+		if err := collector.ClearReduceMarker(warmDir); err != nil {
+			log.Printf("[Reduce] Warning: failed to clear reduce marker: %v", err)
+		}
+		if archived.Lines != agg.TotalRecords {
+			log.Printf("[Reduce] Warning: archived %d lines but aggregated %d records", archived.Lines, agg.TotalRecords)
+		}
 
 		// Push to Turso asynchronously if available
 		if tursoPusher != nil && agg.TotalRecords > 0 {
@@ -400,10 +455,9 @@ func runContinuousMode() {
 	}
 
 	// Create configuration
-	config := collector.DefaultConfig()
-	warmFileThreshold := getEnvInt("WARM_FILE_THRESHOLD", 10)
-	config.WarmFileThreshold = int64(warmFileThreshold)
-	log.Printf("Reduce trigger: every %d warm files", warmFileThreshold)
+	config := collectorConfig.ContinuousConfig()
+	config.StateFile = filepath.Join(storagePath, "collector_state.json")
+	log.Printf("Reduce trigger: every %d warm files", config.WarmFileThreshold)
 
 	// Create continuous collector
 	cc = collector.NewContinuousCollector(
@@ -415,6 +469,62 @@ func runContinuousMode() {
 		config,
 	)
 
+	// Remember the active key so the stall watchdog can re-validate it
+	cc.SetAPIKey(os.Getenv("RIOT_API_KEY"))
+	cc.SetStartupRecovery(func(ctx context.Context) error {
+		_, err := collector.RecoverInterruptedReduce(warmDir, coldDir)
+		return err
+	})
+
+	// Pushes that failed or were cut off by a crash are dead-lettered; replay them
+	// before collecting when the last run stopped mid-push
+	if tursoPusher != nil {
+		cc.SetPushRecovery(func(ctx context.Context) error {
+			replayed, err := tursoPusher.ReplayDLQ(ctx)
+			log.Printf("[Reduce] Replayed %d dead-lettered pushes", replayed)
+			return err
+		})
+	}
+
+	if maxBytes := collectorConfig.ColdMaxBytes(); maxBytes > 0 {
+		log.Printf("Cold storage cap: %d MB", collectorConfig.ColdMaxMB)
+		cc.SetPostPush(func(ctx context.Context) error {
+			pruned, err := collector.PruneColdBySize(coldDir, maxBytes)
+			if pruned.Files > 0 {
+				log.Printf("[Reduce] Pruned %d cold archives (%d MB), %d MB left",
+					pruned.Files, pruned.Bytes/(1024*1024), pruned.Remaining/(1024*1024))
+			}
+			return err
+		})
+	}
+
+	if collectorConfig.FinalReduceOnShutdown {
+		log.Println("Final reduce on shutdown: enabled")
+		flush := collector.WarmFlush{
+			WarmDir:      warmDir,
+			ColdDir:      coldDir,
+			ItemFilter:   riot.IsCompletedItem,
+			Options:      aggOpts,
+			Compressor:   coldCompressor,
+			ShardByPatch: shardColdByPatch,
+			Pusher:       dataPusher,
+		}
+		cc.SetShutdownFlush(func(flushCtx context.Context) error {
+			if _, err := rotator.FlushAndRotate(); err != nil {
+				log.Printf("[Reduce] Warning: FlushAndRotate failed: %v", err)
+			}
+			// Let queued background pushes land first so pushes stay in order
+			if tursoPusher != nil {
+				tursoPusher.Drain()
+			}
+			_, err := flush.Run(flushCtx)
+			return err
+		})
+	}
+
+	// Count completed matches per patch
+	spider.SetOnMatchComplete(cc.RecordMatch)
+
 	// Wire up rotator callback to increment warm file count
 	rotator.SetOnRotateCallback(func() {
 		log.Println("[Rotator] File rotated to warm, incrementing counter...")
@@ -437,6 +547,11 @@ func runContinuousMode() {
 		cc.Shutdown(shutdownCtx)
 	})
 
+	// Optional status endpoint for monitoring headless runs
+	if statusAddr := os.Getenv("STATUS_ADDR"); statusAddr != "" {
+		collector.StartStatusServer(signalCtx, statusAddr, cc)
+	}
+
 	// Run the continuous collector
 	log.Println("Starting continuous collector...")
 	if err := cc.Run(signalCtx); err != nil && err != context.Canceled {
@@ -499,31 +614,3 @@ func findAnalyzerDir() string {
 
 	return ""
 }
-
-// getEnvInt reads an integer from an environment variable, returning defaultVal if not set or invalid
-func getEnvInt(key string, defaultVal int) int {
-	val := os.Getenv(key)
-	if val == "" {
-		return defaultVal
-	}
-	var result int
-	if _, err := fmt.Sscanf(val, "%d", &result); err != nil {
-		log.Printf("Warning: Invalid value for %s=%q, using default %d", key, val, defaultVal)
-		return defaultVal
-	}
-	return result
-}
-
-// getEnvFloat reads a float from an environment variable, returning defaultVal if not set or invalid
-func getEnvFloat(key string, defaultVal float64) float64 {
-	val := os.Getenv(key)
-	if val == "" {
-		return defaultVal
-	}
-	var result float64
-	if _, err := fmt.Sscanf(val, "%f", &result); err != nil {
-		log.Printf("Warning: Invalid value for %s=%q, using default %.2f", key, val, defaultVal)
-		return defaultVal
-	}
-	return result
-}
@@ -254,8 +254,8 @@ func main() {
 		log.Fatalf("Failed to load item data: %v", err)
 	}
 
-	// Scan warm directory for .jsonl files
-	files, err := filepath.Glob(filepath.Join(warmDir, "*.jsonl"))
+	// Scan warm directory for rotated match files (raw_matches_*.jsonl)
+	files, err := filepath.Glob(filepath.Join(warmDir, "raw_matches_*.jsonl"))
 	if err != nil {
 		log.Fatalf("Failed to scan warm directory: %v", err)
 	}
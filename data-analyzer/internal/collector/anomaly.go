@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnomalyReport bundles one reduce cycle's data-quality counters so operators get a
+// single summary instead of a warning per category
+type AnomalyReport struct {
+	Records          int // Records aggregated, for scale
+	SkippedLines     int // Unparseable lines
+	CorruptMatches   int // Full matches with no winner
+	AsymmetricLanes  int // Positions without one winner and one loser
+	DuplicateRecords int // Repeated matchId+puuid rows
+	DuplicateMatches int // Matches already counted from an earlier file
+	LowItemRecords   int // Participants left out of item stats by MinCompletedItems
+	ShortGameRecords int // Participants of remakes and other too-short games
+	PartialFiles     int // Files whose read failed partway
+}
+
+// AnomalyReport collects the data-quality counters from an aggregation
+func (a *AggData) AnomalyReport() AnomalyReport {
+	return AnomalyReport{
+		Records:          a.TotalRecords,
+		SkippedLines:     a.SkippedLines,
+		CorruptMatches:   a.CorruptMatches,
+		AsymmetricLanes:  a.AsymmetricLanes,
+		DuplicateRecords: a.DuplicateRecords,
+		DuplicateMatches: a.DuplicateMatches,
+		LowItemRecords:   a.LowItemRecords,
+		ShortGameRecords: a.ShortGameRecords,
+		PartialFiles:     a.PartialFiles,
+	}
+}
+
+// Total returns the number of anomalies across every category
+func (r AnomalyReport) Total() int {
+	return r.SkippedLines + r.CorruptMatches + r.AsymmetricLanes + r.DuplicateRecords + r.DuplicateMatches + r.LowItemRecords + r.ShortGameRecords + r.PartialFiles
+}
+
+// String summarises the non-zero categories on one line
+func (r AnomalyReport) String() string {
+	if r.Total() == 0 {
+		return fmt.Sprintf("no anomalies in %d records", r.Records)
+	}
+
+	var parts []string
+	add := func(n int, label string) {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, label))
+		}
+	}
+	add(r.SkippedLines, "unparseable lines")
+	add(r.CorruptMatches, "corrupt matches")
+	add(r.AsymmetricLanes, "asymmetric lanes")
+	add(r.DuplicateRecords, "duplicate records")
+	add(r.DuplicateMatches, "duplicate matches")
+	add(r.LowItemRecords, "low-item participants")
+	add(r.ShortGameRecords, "short-game participants")
+	add(r.PartialFiles, "partially read files")
+	return fmt.Sprintf("%d anomalies in %d records: %s", r.Total(), r.Records, strings.Join(parts, ", "))
+}
@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnomalyReport_ReflectsEachCategory(t *testing.T) {
+	agg := emptyAggData()
+	agg.TotalRecords = 1000
+	agg.SkippedLines = 1
+	agg.CorruptMatches = 2
+	agg.AsymmetricLanes = 3
+	agg.DuplicateRecords = 4
+	agg.LowItemRecords = 5
+
+	report := agg.AnomalyReport()
+	want := AnomalyReport{Records: 1000, SkippedLines: 1, CorruptMatches: 2, AsymmetricLanes: 3, DuplicateRecords: 4, LowItemRecords: 5}
+	if report != want {
+		t.Errorf("AnomalyReport = %+v, want %+v", report, want)
+	}
+	if report.Total() != 15 {
+		t.Errorf("Total = %d, want 15", report.Total())
+	}
+
+	summary := report.String()
+	for _, part := range []string{"15 anomalies in 1000 records", "1 unparseable lines", "2 corrupt matches", "3 asymmetric lanes", "4 duplicate records", "5 low-item participants"} {
+		if !strings.Contains(summary, part) {
+			t.Errorf("summary %q missing %q", summary, part)
+		}
+	}
+
+	if got := emptyAggData().AnomalyReport().String(); got != "no anomalies in 0 records" {
+		t.Errorf("clean report = %q", got)
+	}
+}
+
+func TestAggregateReader_CountsSkippedLinesAndAsymmetricLanes(t *testing.T) {
+	// One malformed line, and a match where TOP has only one player
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":238,"teamPosition":"MIDDLE","win":false}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":86,"teamPosition":"TOP","win":true}
+not json
+`
+	agg, err := aggregateReader(strings.NewReader(sampleData), func(int) bool { return true }, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+	if agg.SkippedLines != 1 || agg.AsymmetricLanes != 1 {
+		t.Errorf("SkippedLines = %d, AsymmetricLanes = %d; want 1 and 1", agg.SkippedLines, agg.AsymmetricLanes)
+	}
+}
@@ -0,0 +1,240 @@
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+)
+
+// CollectorConfig holds every tunable for continuous mode in one place. It is loaded
+// from an optional JSON file, then overridden by environment variables, then validated.
+type CollectorConfig struct {
+	// Reduce trigger and watchdog
+	WarmFileThreshold   int    `json:"warmFileThreshold"`   // WARM_FILE_THRESHOLD
+	StallTimeoutMinutes int    `json:"stallTimeoutMinutes"` // STALL_TIMEOUT_MINUTES, 0 disables
+	HeartbeatFile       string `json:"heartbeatFile"`       // HEARTBEAT_FILE
+
+	// Spider
+	MatchesPerPlayer     int     `json:"matchesPerPlayer"`     // MATCHES_PER_PLAYER
+	ARAMMatchesPerPlayer int     `json:"aramMatchesPerPlayer"` // ARAM_MATCHES_PER_PLAYER, 0 disables
+	MaxPlayers           int     `json:"maxPlayers"`           // MAX_PLAYERS
+	WorkerCount          int     `json:"workerCount"`          // WORKER_COUNT
+	TimelineSamplingRate float64 `json:"timelineSamplingRate"` // TIMELINE_SAMPLING_RATE
+	SpiderSeed           int64   `json:"spiderSeed"`           // SPIDER_SEED, fixes random selection; 0 seeds from the clock
+
+	// Rotator
+	RotateMaxMatches    int `json:"rotateMaxMatches"`    // ROTATE_MAX_MATCHES
+	RotateMaxAgeMinutes int `json:"rotateMaxAgeMinutes"` // ROTATE_MAX_AGE_MINUTES
+
+	// Cold storage cap, enforced after each push by pruning the oldest archives
+	ColdMaxMB int `json:"coldMaxMB"` // COLD_MAX_MB, 0 disables
+
+	// Reducer and pusher
+	MaxBuildSlots      int `json:"maxBuildSlots"`      // MAX_BUILD_SLOTS
+	MinCompletedItems  int `json:"minCompletedItems"`  // MIN_COMPLETED_ITEMS, 0 counts everyone
+	MinGameDuration    int `json:"minGameDuration"`    // MIN_GAME_DURATION seconds, 0 keeps remakes
+	PushBufferSize     int `json:"pushBufferSize"`     // PUSH_BUFFER_SIZE
+	ReduceWorkers      int `json:"reduceWorkers"`      // REDUCE_WORKERS, 0 uses every CPU
+	SlotSampleInterval int `json:"slotSampleInterval"` // SLOT_SAMPLE_INTERVAL, item-slot stats from 1 in N matches; 0 or 1 uses all
+
+	// FinalReduceOnShutdown aggregates, archives and pushes leftover warm files
+	// before exiting (FINAL_REDUCE_ON_SHUTDOWN=true)
+	FinalReduceOnShutdown bool `json:"finalReduceOnShutdown"`
+}
+
+// maxMatchesPerPlayer is the most match IDs the Riot API returns per request
+const maxMatchesPerPlayer = 100
+
+// DefaultCollectorConfig returns the values continuous mode used before config files existed
+func DefaultCollectorConfig() CollectorConfig {
+	return CollectorConfig{
+		WarmFileThreshold:    10,
+		StallTimeoutMinutes:  15,
+		MatchesPerPlayer:     20,
+		MaxPlayers:           10000,
+		WorkerCount:          1,
+		TimelineSamplingRate: 0.20,
+		RotateMaxMatches:     1000,
+		RotateMaxAgeMinutes:  60,
+		MaxBuildSlots:        DefaultMaxBuildSlots,
+		MinGameDuration:      DefaultMinGameDurationSeconds,
+		PushBufferSize:       10,
+	}
+}
+
+// LoadCollectorConfig starts from the defaults, applies the JSON file at path (if path is
+// non-empty), applies environment overrides read through getenv, and validates the result.
+func LoadCollectorConfig(path string, getenv func(string) string) (CollectorConfig, error) {
+	cfg := DefaultCollectorConfig()
+
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("read collector config: %w", err)
+		}
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return cfg, fmt.Errorf("parse collector config %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(getenv); err != nil {
+		return cfg, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides fields from environment variables. Unparseable values are errors
+// rather than silently falling back to the default.
+func (c *CollectorConfig) applyEnv(getenv func(string) string) error {
+	ints := []struct {
+		key string
+		dst *int
+	}{
+		{"WARM_FILE_THRESHOLD", &c.WarmFileThreshold},
+		{"STALL_TIMEOUT_MINUTES", &c.StallTimeoutMinutes},
+		{"MATCHES_PER_PLAYER", &c.MatchesPerPlayer},
+		{"ARAM_MATCHES_PER_PLAYER", &c.ARAMMatchesPerPlayer},
+		{"MAX_PLAYERS", &c.MaxPlayers},
+		{"WORKER_COUNT", &c.WorkerCount},
+		{"ROTATE_MAX_MATCHES", &c.RotateMaxMatches},
+		{"ROTATE_MAX_AGE_MINUTES", &c.RotateMaxAgeMinutes},
+		{"COLD_MAX_MB", &c.ColdMaxMB},
+		{"MAX_BUILD_SLOTS", &c.MaxBuildSlots},
+		{"MIN_COMPLETED_ITEMS", &c.MinCompletedItems},
+		{"MIN_GAME_DURATION", &c.MinGameDuration},
+		{"PUSH_BUFFER_SIZE", &c.PushBufferSize},
+		{"REDUCE_WORKERS", &c.ReduceWorkers},
+		{"SLOT_SAMPLE_INTERVAL", &c.SlotSampleInterval},
+	}
+	for _, e := range ints {
+		val := getenv(e.key)
+		if val == "" {
+			continue
+		}
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return fmt.Errorf("invalid %s=%q: not an integer", e.key, val)
+		}
+		*e.dst = n
+	}
+
+	if val := getenv("TIMELINE_SAMPLING_RATE"); val != "" {
+		rate, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return fmt.Errorf("invalid TIMELINE_SAMPLING_RATE=%q: not a number", val)
+		}
+		c.TimelineSamplingRate = rate
+	}
+	if val := getenv("SPIDER_SEED"); val != "" {
+		seed, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid SPIDER_SEED=%q: not an integer", val)
+		}
+		c.SpiderSeed = seed
+	}
+	if val := getenv("FINAL_REDUCE_ON_SHUTDOWN"); val != "" {
+		enabled, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("invalid FINAL_REDUCE_ON_SHUTDOWN=%q: not a boolean", val)
+		}
+		c.FinalReduceOnShutdown = enabled
+	}
+	if val := getenv("HEARTBEAT_FILE"); val != "" {
+		c.HeartbeatFile = val
+	}
+	return nil
+}
+
+// Validate reports every out-of-range value, so a bad config fails at startup
+// instead of stalling or spinning hours into a run
+func (c CollectorConfig) Validate() error {
+	var errs []error
+	positive := func(name string, v int) {
+		if v <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %d", name, v))
+		}
+	}
+
+	positive("warmFileThreshold", c.WarmFileThreshold)
+	positive("maxPlayers", c.MaxPlayers)
+	positive("workerCount", c.WorkerCount)
+	positive("rotateMaxMatches", c.RotateMaxMatches)
+	positive("rotateMaxAgeMinutes", c.RotateMaxAgeMinutes)
+	positive("maxBuildSlots", c.MaxBuildSlots)
+	positive("pushBufferSize", c.PushBufferSize)
+
+	if c.StallTimeoutMinutes < 0 {
+		errs = append(errs, fmt.Errorf("stallTimeoutMinutes must be 0 (disabled) or positive, got %d", c.StallTimeoutMinutes))
+	}
+	if c.ColdMaxMB < 0 {
+		errs = append(errs, fmt.Errorf("coldMaxMB must be 0 (disabled) or positive, got %d", c.ColdMaxMB))
+	}
+	if c.MinGameDuration < 0 {
+		errs = append(errs, fmt.Errorf("minGameDuration must be 0 (keep every game) or positive, got %d", c.MinGameDuration))
+	}
+	if c.ReduceWorkers < 0 {
+		errs = append(errs, fmt.Errorf("reduceWorkers must be 0 (every CPU) or positive, got %d", c.ReduceWorkers))
+	}
+	if c.SlotSampleInterval < 0 {
+		errs = append(errs, fmt.Errorf("slotSampleInterval must be 0 (every match) or positive, got %d", c.SlotSampleInterval))
+	}
+	if c.MatchesPerPlayer <= 0 || c.MatchesPerPlayer > maxMatchesPerPlayer {
+		errs = append(errs, fmt.Errorf("matchesPerPlayer must be between 1 and %d, got %d", maxMatchesPerPlayer, c.MatchesPerPlayer))
+	}
+	if c.ARAMMatchesPerPlayer < 0 || c.ARAMMatchesPerPlayer > maxMatchesPerPlayer {
+		errs = append(errs, fmt.Errorf("aramMatchesPerPlayer must be between 0 and %d, got %d", maxMatchesPerPlayer, c.ARAMMatchesPerPlayer))
+	}
+	if c.MinCompletedItems < 0 || c.MinCompletedItems > 6 {
+		errs = append(errs, fmt.Errorf("minCompletedItems must be between 0 and 6, got %d", c.MinCompletedItems))
+	}
+	if c.TimelineSamplingRate < 0 || c.TimelineSamplingRate > 1 {
+		errs = append(errs, fmt.Errorf("timelineSamplingRate must be between 0 and 1, got %g", c.TimelineSamplingRate))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid collector config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// ContinuousConfig returns the ContinuousCollector settings from this config
+func (c CollectorConfig) ContinuousConfig() ContinuousCollectorConfig {
+	config := DefaultConfig()
+	config.WarmFileThreshold = int64(c.WarmFileThreshold)
+	config.StallTimeout = time.Duration(c.StallTimeoutMinutes) * time.Minute
+	config.HeartbeatFile = c.HeartbeatFile
+	return config
+}
+
+// SpiderConfig returns the spider settings from this config. A SpiderSeed gives the
+// spider a seeded random source, so a run's sampling can be repeated.
+func (c CollectorConfig) SpiderConfig() SpiderConfig {
+	cfg := SpiderConfig{
+		MatchesPerPlayer:     c.MatchesPerPlayer,
+		ARAMMatchesPerPlayer: c.ARAMMatchesPerPlayer,
+		MaxPlayers:           c.MaxPlayers,
+		WorkerCount:          c.WorkerCount,
+		TimelineSamplingRate: c.TimelineSamplingRate,
+	}
+	if c.SpiderSeed != 0 {
+		cfg.Rand = rand.New(rand.NewSource(c.SpiderSeed))
+	}
+	return cfg
+}
+
+// ColdMaxBytes returns the cold storage cap in bytes (0 = unlimited)
+func (c CollectorConfig) ColdMaxBytes() int64 {
+	return int64(c.ColdMaxMB) * 1024 * 1024
+}
+
+// RotateMaxAge returns the rotator's max file age
+func (c CollectorConfig) RotateMaxAge() time.Duration {
+	return time.Duration(c.RotateMaxAgeMinutes) * time.Minute
+}
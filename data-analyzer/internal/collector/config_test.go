@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func envMap(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestLoadCollectorConfig_FileThenEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "collector.json")
+	if err := os.WriteFile(path, []byte(`{"warmFileThreshold": 4, "workerCount": 3, "timelineSamplingRate": 0.5}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadCollectorConfig(path, envMap(map[string]string{"WORKER_COUNT": "2"}))
+	if err != nil {
+		t.Fatalf("LoadCollectorConfig: %v", err)
+	}
+	if cfg.WarmFileThreshold != 4 || cfg.TimelineSamplingRate != 0.5 {
+		t.Errorf("file values not applied: %+v", cfg)
+	}
+	if cfg.WorkerCount != 2 {
+		t.Errorf("WorkerCount = %d, want env override 2", cfg.WorkerCount)
+	}
+	if cfg.MaxPlayers != DefaultCollectorConfig().MaxPlayers {
+		t.Errorf("MaxPlayers = %d, want default", cfg.MaxPlayers)
+	}
+
+	cc := cfg.ContinuousConfig()
+	if cc.WarmFileThreshold != 4 || cc.StallTimeout != 15*time.Minute {
+		t.Errorf("ContinuousConfig = %+v", cc)
+	}
+}
+
+func TestLoadCollectorConfig_RejectsOutOfRange(t *testing.T) {
+	_, err := LoadCollectorConfig("", envMap(map[string]string{
+		"WARM_FILE_THRESHOLD":    "0",
+		"TIMELINE_SAMPLING_RATE": "1.5",
+	}))
+	if err == nil {
+		t.Fatal("expected a validation error")
+	}
+	for _, want := range []string{"warmFileThreshold must be positive, got 0", "timelineSamplingRate must be between 0 and 1, got 1.5"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+
+	if _, err := LoadCollectorConfig("", envMap(map[string]string{"MAX_PLAYERS": "lots"})); err == nil || !strings.Contains(err.Error(), "MAX_PLAYERS") {
+		t.Errorf("unparseable env value should name the variable, got %v", err)
+	}
+}
+
+func TestCollectorConfig_SpiderSeedIsReproducible(t *testing.T) {
+	cfg, err := LoadCollectorConfig("", envMap(map[string]string{"SPIDER_SEED": "42"}))
+	if err != nil {
+		t.Fatalf("LoadCollectorConfig: %v", err)
+	}
+	a, b := cfg.SpiderConfig().Rand, cfg.SpiderConfig().Rand
+	if a == nil || b == nil {
+		t.Fatal("SPIDER_SEED should give the spider a seeded source")
+	}
+	for i := 0; i < 5; i++ {
+		if x, y := a.Float64(), b.Float64(); x != y {
+			t.Fatalf("draw %d: %v != %v with the same seed", i, x, y)
+		}
+	}
+
+	if DefaultCollectorConfig().SpiderConfig().Rand != nil {
+		t.Error("without a seed the spider should seed itself from the clock")
+	}
+	if _, err := LoadCollectorConfig("", envMap(map[string]string{"SPIDER_SEED": "abc"})); err == nil {
+		t.Error("non-integer SPIDER_SEED should be rejected")
+	}
+}
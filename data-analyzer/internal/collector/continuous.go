@@ -97,6 +97,16 @@ type ContinuousCollectorConfig struct {
 	ShutdownTimeout time.Duration
 	// BloomResetInterval is how many reduce cycles before resetting bloom filters (default: 5)
 	BloomResetInterval int
+	// StallTimeout is how long COLLECTING may go without a new match before the watchdog
+	// forces a reduce and re-checks the API key (default: 15 minutes, 0 disables)
+	StallTimeout time.Duration
+	// HeartbeatFile, if set, has its mtime refreshed every HeartbeatInterval while the
+	// main loop is running and collection isn't stalled (default interval: 30 seconds)
+	HeartbeatFile     string
+	HeartbeatInterval time.Duration
+	// StateFile, if set, persists the state machine across restarts so a process that
+	// died mid-push runs the push recovery hook on startup (see SetPushRecovery)
+	StateFile string
 }
 
 // DefaultConfig returns a configuration with sensible defaults
@@ -106,6 +116,8 @@ func DefaultConfig() ContinuousCollectorConfig {
 		KeyPollInterval:    5 * time.Minute,
 		ShutdownTimeout:    5 * time.Minute,
 		BloomResetInterval: 5,
+		StallTimeout:       15 * time.Minute,
+		HeartbeatInterval:  30 * time.Second,
 	}
 }
 
@@ -125,10 +137,17 @@ type ContinuousCollector struct {
 	keyValidator KeyValidator
 	keyProvider  KeyProvider
 	notifyFunc   NotifyFunc
+	recoverFunc  func(ctx context.Context) error // Startup on-disk recovery, run before COLLECTING
+	pushRecovery func(ctx context.Context) error // Run at startup when the last process died in PUSHING
+	flushFunc    func(ctx context.Context) error // Final warm flush, run once during shutdown
+	postPushFunc func(ctx context.Context) error // Housekeeping after each push, before collecting resumes
 
 	// Internal state
 	reduceCycleCount atomic.Int64
 	keyExpired       atomic.Bool
+	apiKey           atomic.Value // string; last key handed to the spider, for stall re-checks
+	stalled          atomic.Bool  // Set by the stall watchdog while COLLECTING makes no progress
+	heartbeat        heartbeat
 	shutdownCh       chan struct{}
 	shutdownOnce     sync.Once
 
@@ -136,6 +155,9 @@ type ContinuousCollector struct {
 	matchesCollected atomic.Int64
 	startTime        time.Time
 	lastReduceTime   atomic.Value // stores time.Time
+	gamesByPatch     map[string]int64
+	matchTimes       map[string][]time.Time // recent RecordMatch times per patch, for rate estimates
+	gamesMu          sync.Mutex
 
 	// Synchronization
 	wg sync.WaitGroup
@@ -153,7 +175,7 @@ func NewContinuousCollector(
 ) *ContinuousCollector {
 	cc := &ContinuousCollector{
 		config:       config,
-		stateMachine: NewStateMachine(),
+		stateMachine: NewStateMachineWithPersistence(config.StateFile),
 		warmLock:     NewWarmLock(),
 		spider:       spider,
 		reduceFunc:   reduceFunc,
@@ -162,6 +184,9 @@ func NewContinuousCollector(
 		notifyFunc:   notifyFunc,
 		shutdownCh:   make(chan struct{}),
 		startTime:    time.Now(),
+		gamesByPatch: make(map[string]int64),
+		matchTimes:   make(map[string][]time.Time),
+		heartbeat:    heartbeat{path: config.HeartbeatFile, interval: config.HeartbeatInterval},
 	}
 	cc.lastReduceTime.Store(time.Time{})
 
@@ -178,11 +203,35 @@ func NewContinuousCollector(
 func (cc *ContinuousCollector) Run(ctx context.Context) error {
 	log.Println("[ContinuousCollector] Starting...")
 
+	// Repair anything a killed reduce left half-done before collecting into warm again
+	if cc.recoverFunc != nil {
+		if err := cc.recoverFunc(ctx); err != nil {
+			return fmt.Errorf("startup recovery failed: %w", err)
+		}
+	}
+
+	// A process that died mid-push lost its queued aggregation; recover what was
+	// persisted before moving on to COLLECTING
+	if previous, err := cc.stateMachine.Recover(); err != nil {
+		log.Printf("[ContinuousCollector] Warning: state recovery failed: %v (starting fresh)", err)
+	} else if previous == StatePushing && cc.pushRecovery != nil {
+		log.Println("[ContinuousCollector] Previous run stopped while PUSHING, running push recovery...")
+		if err := cc.pushRecovery(ctx); err != nil {
+			log.Printf("[ContinuousCollector] Push recovery failed: %v", err)
+		}
+	}
+
 	// Initial transition to COLLECTING
 	if err := cc.seedAndStartCollecting(ctx); err != nil {
 		return fmt.Errorf("failed to start: %w", err)
 	}
 
+	// Watch for a spider that stops making progress without erroring
+	if cc.config.StallTimeout > 0 {
+		cc.wg.Add(1)
+		go cc.runStallWatchdog(ctx)
+	}
+
 	// Main loop - monitor state and handle transitions
 	for {
 		select {
@@ -196,6 +245,8 @@ func (cc *ContinuousCollector) Run(ctx context.Context) error {
 			return nil
 
 		default:
+			cc.beatHeartbeat(time.Now())
+
 			// Handle current state
 			switch cc.stateMachine.Current() {
 			case StateStartup:
@@ -270,13 +321,8 @@ func (cc *ContinuousCollector) runSpider(ctx context.Context) {
 			return
 		case <-cc.shutdownCh:
 			return
-		default:
-			// Only run when in COLLECTING state
-			if !cc.stateMachine.IsCollecting() {
-				time.Sleep(50 * time.Millisecond)
-				continue
-			}
-
+		case <-cc.stateMachine.CollectingCh():
+			// Only run when in COLLECTING state; blocks (without polling) otherwise
 			if cc.spider == nil {
 				time.Sleep(100 * time.Millisecond)
 				continue
@@ -309,6 +355,113 @@ func (cc *ContinuousCollector) handleSpiderError(ctx context.Context, err error)
 	return false
 }
 
+// SetStartupRecovery sets a hook run at the start of Run, before the first transition to
+// COLLECTING, to repair on-disk state left by an interrupted reduce
+func (cc *ContinuousCollector) SetStartupRecovery(fn func(ctx context.Context) error) {
+	cc.recoverFunc = fn
+}
+
+// SetPushRecovery sets a hook run at the start of Run when the persisted state shows the
+// last process stopped in PUSHING (e.g. replaying dead-lettered pushes). Errors are
+// logged and collection starts regardless.
+func (cc *ContinuousCollector) SetPushRecovery(fn func(ctx context.Context) error) {
+	cc.pushRecovery = fn
+}
+
+// SetShutdownFlush sets a hook run once during graceful shutdown, after any in-flight
+// reduce, to aggregate and push warm files that never reached the reduce threshold
+func (cc *ContinuousCollector) SetShutdownFlush(fn func(ctx context.Context) error) {
+	cc.flushFunc = fn
+}
+
+// SetPostPush sets a hook run after each push phase, before the transition back to
+// COLLECTING (e.g. pruning cold storage). Errors are logged and don't stop collection.
+func (cc *ContinuousCollector) SetPostPush(fn func(ctx context.Context) error) {
+	cc.postPushFunc = fn
+}
+
+// SetAPIKey hands a key to the spider and remembers it so the stall watchdog can re-validate it
+func (cc *ContinuousCollector) SetAPIKey(key string) {
+	cc.apiKey.Store(key)
+	if cc.spider != nil {
+		cc.spider.SetAPIKey(key)
+	}
+}
+
+// runStallWatchdog forces a reduce when COLLECTING makes no progress for StallTimeout
+func (cc *ContinuousCollector) runStallWatchdog(ctx context.Context) {
+	defer cc.wg.Done()
+
+	ticker := time.NewTicker(cc.config.StallTimeout / 4)
+	defer ticker.Stop()
+
+	lastGames := cc.totalGamesRecorded()
+	lastProgress := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cc.shutdownCh:
+			return
+		case <-ticker.C:
+			games := cc.totalGamesRecorded()
+			if games != lastGames || !cc.stateMachine.IsCollecting() {
+				lastGames = games
+				lastProgress = time.Now()
+				cc.stalled.Store(false)
+				continue
+			}
+			if time.Since(lastProgress) < cc.config.StallTimeout {
+				continue
+			}
+
+			cc.stalled.Store(true)
+			log.Printf("[ContinuousCollector] No new matches for %v, forcing reduce", cc.config.StallTimeout)
+			cc.handleStall()
+			lastProgress = time.Now()
+		}
+	}
+}
+
+// beatHeartbeat refreshes the heartbeat file unless collection is stalled. Only the
+// main loop calls it, so a blocked loop also stops the heartbeat.
+func (cc *ContinuousCollector) beatHeartbeat(now time.Time) {
+	if cc.stalled.Load() {
+		return
+	}
+	if err := cc.heartbeat.beat(now); err != nil {
+		log.Printf("[ContinuousCollector] Heartbeat write failed: %v", err)
+	}
+}
+
+// handleStall re-validates the API key, then triggers a reduce. An invalid key marks it
+// expired so the push phase moves on to WAITING_FOR_KEY instead of resuming collection.
+func (cc *ContinuousCollector) handleStall() {
+	key, _ := cc.apiKey.Load().(string)
+	if cc.keyValidator != nil && key != "" {
+		valid, err := cc.keyValidator.ValidateKey(key)
+		if err != nil {
+			log.Printf("[ContinuousCollector] Stall key check failed: %v", err)
+		} else if !valid {
+			log.Println("[ContinuousCollector] API key appears expired during stall")
+			cc.keyExpired.Store(true)
+		}
+	}
+	cc.triggerReduce()
+}
+
+// totalGamesRecorded sums the per-patch games counter
+func (cc *ContinuousCollector) totalGamesRecorded() int64 {
+	cc.gamesMu.Lock()
+	defer cc.gamesMu.Unlock()
+
+	var total int64
+	for _, games := range cc.gamesByPatch {
+		total += games
+	}
+	return total
+}
+
 // onWarmFileThreshold is called when warm file count reaches threshold
 func (cc *ContinuousCollector) onWarmFileThreshold() {
 	log.Println("[ContinuousCollector] Warm file threshold reached, triggering reduce...")
@@ -378,6 +531,12 @@ func (cc *ContinuousCollector) handlePushing(ctx context.Context) {
 	// Turso push happens here (actual implementation would push data)
 	log.Println("[ContinuousCollector] Push phase complete")
 
+	if cc.postPushFunc != nil {
+		if err := cc.postPushFunc(ctx); err != nil {
+			log.Printf("[ContinuousCollector] Post-push hook failed: %v", err)
+		}
+	}
+
 	// Determine next state based on key expiration flag
 	if cc.keyExpired.Load() {
 		log.Println("[ContinuousCollector] Key expired, transitioning to WAITING_FOR_KEY")
@@ -446,9 +605,7 @@ func (cc *ContinuousCollector) handleWaitingForKey(ctx context.Context) {
 
 			// Key is valid - update the spider's API key
 			log.Println("[ContinuousCollector] Valid key received, updating API key...")
-			if cc.spider != nil {
-				cc.spider.SetAPIKey(newKey)
-			}
+			cc.SetAPIKey(newKey)
 
 			// Transition to FRESH_RESTART
 			log.Println("[ContinuousCollector] Initiating fresh restart...")
@@ -546,6 +703,17 @@ func (cc *ContinuousCollector) initiateShutdown(ctx context.Context) {
 			}
 		}
 
+		// Flush whatever is left in warm so it isn't stranded until the next run
+		if cc.flushFunc != nil {
+			flushCtx, cancel := context.WithTimeout(ctx, cc.config.ShutdownTimeout)
+			cc.warmLock.Lock()
+			if err := cc.flushFunc(flushCtx); err != nil {
+				log.Printf("[ContinuousCollector] Final flush failed: %v", err)
+			}
+			cc.warmLock.Unlock()
+			cancel()
+		}
+
 		// Transition to SHUTDOWN
 		cc.stateMachine.TransitionTo(StateShutdown)
 
@@ -594,7 +762,8 @@ func (cc *ContinuousCollector) onStateTransition(from, to State) {
 type CollectorStats struct {
 	MatchesCollected int64
 	RuntimeSeconds   int64
-	LastReduceAgo    int64 // seconds since last reduce, -1 if never reduced
+	LastReduceAgo    int64            // seconds since last reduce, -1 if never reduced
+	GamesByPatch     map[string]int64 // games collected per patch over the process lifetime
 }
 
 // GetStats returns current collection statistics
@@ -611,6 +780,13 @@ func (cc *ContinuousCollector) GetStats() CollectorStats {
 		}
 	}
 
+	cc.gamesMu.Lock()
+	stats.GamesByPatch = make(map[string]int64, len(cc.gamesByPatch))
+	for patch, games := range cc.gamesByPatch {
+		stats.GamesByPatch[patch] = games
+	}
+	cc.gamesMu.Unlock()
+
 	return stats
 }
 
@@ -619,6 +795,58 @@ func (cc *ContinuousCollector) IncrementMatchCount(count int64) {
 	cc.matchesCollected.Add(count)
 }
 
+// RecordMatch counts one completed match for a patch (called by spider).
+// Per-patch totals are kept for the process lifetime and survive ResetStats.
+func (cc *ContinuousCollector) RecordMatch(patch string) {
+	cc.recordMatchAt(patch, time.Now())
+}
+
+func (cc *ContinuousCollector) recordMatchAt(patch string, at time.Time) {
+	cc.matchesCollected.Add(1)
+
+	cc.gamesMu.Lock()
+	cc.gamesByPatch[patch]++
+
+	// Keep only samples inside the moving-average window
+	times := append(cc.matchTimes[patch], at)
+	cutoff := at.Add(-collectionRateWindow)
+	drop := 0
+	for drop < len(times) && times[drop].Before(cutoff) {
+		drop++
+	}
+	cc.matchTimes[patch] = times[drop:]
+	cc.gamesMu.Unlock()
+}
+
+// collectionRateWindow is how far back EstimateTimeToTarget looks when averaging the collection rate
+const collectionRateWindow = 30 * time.Minute
+
+// EstimateTimeToTarget estimates how long until patch reaches targetGames at the
+// collection rate averaged over the last collectionRateWindow. Returns 0 if the
+// target is already met and -1 if there is not enough recent data to estimate a rate.
+func (cc *ContinuousCollector) EstimateTimeToTarget(patch string, targetGames int) time.Duration {
+	cc.gamesMu.Lock()
+	defer cc.gamesMu.Unlock()
+
+	remaining := int64(targetGames) - cc.gamesByPatch[patch]
+	if remaining <= 0 {
+		return 0
+	}
+
+	times := cc.matchTimes[patch]
+	if len(times) < 2 {
+		return -1
+	}
+	span := times[len(times)-1].Sub(times[0])
+	if span <= 0 {
+		return -1
+	}
+
+	// len-1 intervals between the samples in the window
+	perGame := span / time.Duration(len(times)-1)
+	return perGame * time.Duration(remaining)
+}
+
 // ResetStats resets the statistics for a fresh session
 func (cc *ContinuousCollector) ResetStats() {
 	cc.matchesCollected.Store(0)
@@ -3,6 +3,9 @@ package collector
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -537,3 +540,308 @@ func TestContinuousCollector_KeyExpirationFullCycle(t *testing.T) {
 		t.Fatalf("failed to transition back to COLLECTING")
 	}
 }
+
+// TestContinuousCollector_GamesByPatch tests that completed matches are counted per patch
+func TestContinuousCollector_GamesByPatch(t *testing.T) {
+	cc := NewContinuousCollector(nil, nil, nil, nil, nil, DefaultConfig())
+
+	for i := 0; i < 3; i++ {
+		cc.RecordMatch("15.24")
+	}
+	cc.RecordMatch("15.23")
+
+	stats := cc.GetStats()
+	if stats.GamesByPatch["15.24"] != 3 {
+		t.Errorf("games for 15.24 = %d, want 3", stats.GamesByPatch["15.24"])
+	}
+	if stats.GamesByPatch["15.23"] != 1 {
+		t.Errorf("games for 15.23 = %d, want 1", stats.GamesByPatch["15.23"])
+	}
+	if stats.MatchesCollected != 4 {
+		t.Errorf("matches collected = %d, want 4", stats.MatchesCollected)
+	}
+
+	// Lifetime per-patch totals survive a session reset
+	cc.ResetStats()
+	if got := cc.GetStats().GamesByPatch["15.24"]; got != 3 {
+		t.Errorf("games for 15.24 after reset = %d, want 3", got)
+	}
+}
+
+// TestContinuousCollector_EstimateTimeToTarget tests the moving-average time-to-target estimate
+func TestContinuousCollector_EstimateTimeToTarget(t *testing.T) {
+	cc := NewContinuousCollector(nil, nil, nil, nil, nil, DefaultConfig())
+
+	if got := cc.EstimateTimeToTarget("15.24", 100); got != -1 {
+		t.Errorf("estimate with no data = %v, want -1", got)
+	}
+
+	// 60 games/min for an hour; only the last 30 minutes feed the average
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3600; i++ {
+		cc.recordMatchAt("15.24", start.Add(time.Duration(i)*time.Second))
+	}
+
+	// 6000 remaining at 1 game/sec ≈ 100 minutes
+	got := cc.EstimateTimeToTarget("15.24", 9600)
+	want := 100 * time.Minute
+	if diff := got - want; diff < -time.Minute || diff > time.Minute {
+		t.Errorf("estimate = %v, want %v ± 1m", got, want)
+	}
+
+	if got := cc.EstimateTimeToTarget("15.24", 1000); got != 0 {
+		t.Errorf("estimate for reached target = %v, want 0", got)
+	}
+}
+
+// TestContinuousCollector_StallWatchdogTriggersReduce tests that a stalled COLLECTING state forces a reduce
+func TestContinuousCollector_StallWatchdogTriggersReduce(t *testing.T) {
+	config := DefaultConfig()
+	config.StallTimeout = 40 * time.Millisecond
+	cc := NewContinuousCollector(nil, nil, &mockKeyValidatorForTest{valid: false}, nil, nil, config)
+	cc.SetAPIKey("RGAPI-expired")
+
+	if err := cc.GetStateMachine().TransitionTo(StateCollecting); err != nil {
+		t.Fatalf("failed to transition to COLLECTING: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cc.wg.Add(1)
+	go cc.runStallWatchdog(ctx)
+
+	deadline := time.After(time.Second)
+	for cc.State() != StateReducing {
+		select {
+		case <-deadline:
+			t.Fatalf("watchdog did not trigger reduce, state = %v", cc.State())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	if !cc.keyExpired.Load() {
+		t.Error("invalid key during stall should be marked expired")
+	}
+
+	cancel()
+	cc.wg.Wait()
+}
+
+// TestContinuousCollector_StallWatchdogIgnoresProgress tests that new matches keep the watchdog quiet
+func TestContinuousCollector_StallWatchdogIgnoresProgress(t *testing.T) {
+	config := DefaultConfig()
+	config.StallTimeout = 40 * time.Millisecond
+	cc := NewContinuousCollector(nil, nil, nil, nil, nil, config)
+
+	if err := cc.GetStateMachine().TransitionTo(StateCollecting); err != nil {
+		t.Fatalf("failed to transition to COLLECTING: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cc.wg.Add(1)
+	go cc.runStallWatchdog(ctx)
+
+	for i := 0; i < 20; i++ {
+		cc.RecordMatch("15.24")
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	cc.wg.Wait()
+
+	if cc.State() != StateCollecting {
+		t.Errorf("state = %v, want COLLECTING while matches keep arriving", cc.State())
+	}
+}
+
+// seedCheckingSpider records whether the warm dir was consistent when collection was seeded
+type seedCheckingSpider struct {
+	mockSpiderForTest
+	warmDir        string
+	warmPath       string
+	markerAtSeed   bool
+	archivedAtSeed bool
+}
+
+func (s *seedCheckingSpider) SeedFromChallenger(ctx context.Context) error {
+	s.markerAtSeed = fileExists(filepath.Join(s.warmDir, ReduceMarkerFile))
+	s.archivedAtSeed = fileExists(s.warmPath)
+	return s.mockSpiderForTest.SeedFromChallenger(ctx)
+}
+
+func TestContinuousCollector_RecoversInterruptedReduceBeforeCollecting(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	coldDir := filepath.Join(tempDir, "cold")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	// Reduce killed after archiving but before the warm original was removed
+	content := `{"matchId":"NA1_1","gameVersion":"15.24.1","win":true}` + "\n"
+	warmPath := filepath.Join(warmDir, "raw_matches_killed_001.jsonl")
+	os.WriteFile(warmPath, []byte(content), 0644)
+	if err := MarkReduceStarted(warmDir); err != nil {
+		t.Fatalf("MarkReduceStarted failed: %v", err)
+	}
+	if _, err := ArchiveWarmToCold(warmDir, coldDir); err != nil {
+		t.Fatalf("ArchiveWarmToCold failed: %v", err)
+	}
+	os.WriteFile(warmPath, []byte(content), 0644)
+
+	spider := &seedCheckingSpider{warmDir: warmDir, warmPath: warmPath}
+	config := DefaultConfig()
+	cc := NewContinuousCollector(spider, func(ctx context.Context) error { return nil },
+		&mockKeyValidatorForTest{valid: true}, nil, nil, config)
+
+	recovered := false
+	cc.SetStartupRecovery(func(ctx context.Context) error {
+		var err error
+		recovered, err = RecoverInterruptedReduce(warmDir, coldDir)
+		return err
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_ = cc.Run(ctx)
+
+	if !recovered {
+		t.Error("Marker left by the interrupted reduce should trigger recovery")
+	}
+	if spider.seedCalls.Load() == 0 {
+		t.Fatal("Collector never seeded")
+	}
+	if spider.markerAtSeed || spider.archivedAtSeed {
+		t.Errorf("Seeded before reconciling: marker=%v archived warm file=%v", spider.markerAtSeed, spider.archivedAtSeed)
+	}
+}
+
+func TestContinuousCollector_StartupRecoveryFailureStopsRun(t *testing.T) {
+	spider := &mockSpiderForTest{}
+	cc := NewContinuousCollector(spider, func(ctx context.Context) error { return nil },
+		&mockKeyValidatorForTest{valid: true}, nil, nil, DefaultConfig())
+	cc.SetStartupRecovery(func(ctx context.Context) error { return errors.New("disk gone") })
+
+	if err := cc.Run(context.Background()); err == nil {
+		t.Fatal("Run should fail when startup recovery fails")
+	}
+	if spider.seedCalls.Load() != 0 {
+		t.Error("Collector must not seed after a failed recovery")
+	}
+}
+
+func TestContinuousCollector_PostPushPrunesColdToCap(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	coldDir := filepath.Join(tempDir, "cold")
+	for _, dir := range []string{warmDir, coldDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Three 1KB archives from earlier runs, oldest first
+	old := time.Now().Add(-72 * time.Hour)
+	for i, name := range []string{"raw_matches_a.jsonl.gz", "raw_matches_b.jsonl.gz", "raw_matches_c.jsonl.gz"} {
+		path := filepath.Join(coldDir, name)
+		if err := os.WriteFile(path, make([]byte, 1024), 0644); err != nil {
+			t.Fatal(err)
+		}
+		stamp := old.Add(time.Duration(i) * time.Hour)
+		if err := os.Chtimes(path, stamp, stamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(warmDir, "raw_matches_new.jsonl"), []byte(strings.Repeat(`{"matchId":"NA1_1"}`+"\n", 50)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reduce := func(ctx context.Context) error {
+		_, err := ArchiveWarmToCold(warmDir, coldDir)
+		return err
+	}
+	cc := NewContinuousCollector(&mockSpiderForTest{}, reduce, &mockKeyValidatorForTest{valid: true}, nil, nil, DefaultConfig())
+	const capBytes = 2048
+	cc.SetPostPush(func(ctx context.Context) error {
+		_, err := PruneColdBySize(coldDir, capBytes)
+		return err
+	})
+
+	// One full cycle: REDUCING -> PUSHING -> COLLECTING
+	cc.stateMachine.setState(StateReducing)
+	cc.handleReducing(context.Background())
+	cc.wg.Wait()
+	if cc.stateMachine.Current() != StateCollecting {
+		t.Fatalf("state = %s, want COLLECTING", cc.stateMachine.Current())
+	}
+
+	var total int64
+	remaining, _ := filepath.Glob(filepath.Join(coldDir, "*.jsonl.gz"))
+	for _, path := range remaining {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += info.Size()
+	}
+	if total > capBytes {
+		t.Errorf("cold holds %d bytes, want at most %d", total, capBytes)
+	}
+	for _, name := range []string{"raw_matches_a.jsonl.gz", "raw_matches_b.jsonl.gz"} {
+		if _, err := os.Stat(filepath.Join(coldDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should have been pruned (oldest first)", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(coldDir, "raw_matches_new.jsonl.gz")); err != nil {
+		t.Errorf("fresh archive was pruned: %v", err)
+	}
+}
+
+func TestContinuousCollector_RecoversPushAfterCrash(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "collector_state.json")
+
+	// The previous process died after reducing, mid-push
+	crashed := NewStateMachineWithPersistence(stateFile)
+	crashed.TransitionTo(StateCollecting)
+	crashed.TryTransitionToReducing()
+	crashed.TransitionTo(StatePushing)
+
+	config := DefaultConfig()
+	config.StateFile = stateFile
+	config.StallTimeout = 0
+	cc := NewContinuousCollector(&mockSpiderForTest{}, func(ctx context.Context) error { return nil },
+		&mockKeyValidatorForTest{valid: true}, nil, nil, config)
+	var recovered atomic.Int32
+	cc.SetPushRecovery(func(ctx context.Context) error {
+		recovered.Add(1)
+		if state := cc.State(); state != StatePushing {
+			t.Errorf("push recovery ran in %s, want PUSHING", state)
+		}
+		return errors.New("turso still down")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- cc.Run(ctx) }()
+
+	// A failed recovery is logged and collection starts anyway
+	if !cc.GetStateMachine().WaitForState(StateCollecting, 2*time.Second) {
+		t.Fatalf("state = %s, want COLLECTING after recovery", cc.State())
+	}
+	if recovered.Load() != 1 {
+		t.Errorf("push recovery ran %d times, want 1", recovered.Load())
+	}
+	cancel()
+	<-done
+
+	// A clean start runs no push recovery
+	fresh := NewContinuousCollector(&mockSpiderForTest{}, nil, &mockKeyValidatorForTest{valid: true}, nil, nil, DefaultConfig())
+	fresh.SetPushRecovery(func(ctx context.Context) error {
+		t.Error("push recovery ran without a persisted PUSHING state")
+		return nil
+	})
+	freshCtx, freshCancel := context.WithCancel(context.Background())
+	go func() { done <- fresh.Run(freshCtx) }()
+	fresh.GetStateMachine().WaitForState(StateCollecting, 2*time.Second)
+	freshCancel()
+	<-done
+}
@@ -0,0 +1,197 @@
+package collector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	json "github.com/goccy/go-json"
+)
+
+// DLQFilePattern matches dead-lettered pushes. Names start with a UTC timestamp so
+// a sorted listing replays them oldest first.
+const DLQFilePattern = "failed_push_*.json.gz"
+
+// dlqEntry is one map entry; AggData's maps have struct keys, which JSON objects can't hold
+type dlqEntry[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// dlqRecord is the on-disk form of a failed push. It carries everything PushAggData
+// reads, including the PushID so a replay of a push that did land is applied once.
+type dlqRecord struct {
+	PushID               string                                            `json:"pushId"`
+	DetectedPatch        string                                            `json:"detectedPatch"`
+	FilesProcessed       int                                               `json:"filesProcessed"`
+	TotalRecords         int                                               `json:"totalRecords"`
+	PatchRecords         map[string]int                                    `json:"patchRecords,omitempty"`
+	PatchTimeRanges      map[string]*TimeRange                             `json:"patchTimeRanges,omitempty"`
+	ChampionStats        []dlqEntry[ChampionStatsKey, ChampionStats]       `json:"championStats"`
+	ItemStats            []dlqEntry[ItemStatsKey, ItemStats]               `json:"itemStats"`
+	ItemSlotStats        []dlqEntry[ItemSlotStatsKey, ItemSlotStats]       `json:"itemSlotStats"`
+	ItemPairStats        []dlqEntry[ItemPairStatsKey, ItemPairStats]       `json:"itemPairStats,omitempty"`
+	MatchupStats         []dlqEntry[MatchupStatsKey, MatchupStats]         `json:"matchupStats"`
+	DuoMatchupStats      []dlqEntry[DuoMatchupStatsKey, MatchupStats]      `json:"duoMatchupStats"`
+	MatchupDurationStats []dlqEntry[MatchupDurationStatsKey, MatchupStats] `json:"matchupDurationStats"`
+	AllyPairStats        []dlqEntry[AllyPairStatsKey, MatchupStats]        `json:"allyPairStats"`
+	SpellPairStats       []dlqEntry[SpellPairStatsKey, MatchupStats]       `json:"spellPairStats"`
+}
+
+func toEntries[K comparable, V any](m map[K]*V) []dlqEntry[K, V] {
+	entries := make([]dlqEntry[K, V], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, dlqEntry[K, V]{Key: k, Value: *v})
+	}
+	return entries
+}
+
+func fromEntries[K comparable, V any](entries []dlqEntry[K, V]) map[K]*V {
+	m := make(map[K]*V, len(entries))
+	for _, e := range entries {
+		v := e.Value
+		m[e.Key] = &v
+	}
+	return m
+}
+
+// writeDLQFile saves data as gzipped JSON in dir and returns the file's path. The file
+// is written under a temp name and renamed, so ReplayDLQ never reads a partial one.
+func writeDLQFile(dir string, data *AggData) (string, error) {
+	record := dlqRecord{
+		PushID:               data.PushID,
+		DetectedPatch:        data.DetectedPatch,
+		FilesProcessed:       data.FilesProcessed,
+		TotalRecords:         data.TotalRecords,
+		PatchRecords:         data.PatchRecords,
+		PatchTimeRanges:      data.PatchTimeRanges,
+		ChampionStats:        toEntries(data.ChampionStats),
+		ItemStats:            toEntries(data.ItemStats),
+		ItemSlotStats:        toEntries(data.ItemSlotStats),
+		ItemPairStats:        toEntries(data.ItemPairStats),
+		MatchupStats:         toEntries(data.MatchupStats),
+		DuoMatchupStats:      toEntries(data.DuoMatchupStats),
+		MatchupDurationStats: toEntries(data.MatchupDurationStats),
+		AllyPairStats:        toEntries(data.AllyPairStats),
+		SpellPairStats:       toEntries(data.SpellPairStats),
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(record); err != nil {
+		return "", fmt.Errorf("encode failed push: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return "", fmt.Errorf("compress failed push: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create dlq dir: %w", err)
+	}
+	patch := data.DetectedPatch
+	if patch == "" {
+		patch = "unknown"
+	}
+	name := fmt.Sprintf("failed_push_%s_%s", time.Now().UTC().Format("20060102T150405.000000000"), patch)
+	if data.PushID != "" {
+		name += "_" + data.PushID
+	}
+	path := filepath.Join(dir, name+".json.gz")
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("write failed push: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("rename failed push: %w", err)
+	}
+	return path, nil
+}
+
+// readDLQFile decodes a dead-lettered push back into an AggData
+func readDLQFile(path string) (*AggData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("open gzip %s: %w", path, err)
+	}
+	defer gz.Close()
+
+	var record dlqRecord
+	if err := json.NewDecoder(gz).Decode(&record); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+
+	data := newAggData()
+	data.PushID = record.PushID
+	data.DetectedPatch = record.DetectedPatch
+	data.FilesProcessed = record.FilesProcessed
+	data.TotalRecords = record.TotalRecords
+	if record.PatchRecords != nil {
+		data.PatchRecords = record.PatchRecords
+	}
+	if record.PatchTimeRanges != nil {
+		data.PatchTimeRanges = record.PatchTimeRanges
+	}
+	data.ChampionStats = fromEntries(record.ChampionStats)
+	data.ItemStats = fromEntries(record.ItemStats)
+	data.ItemSlotStats = fromEntries(record.ItemSlotStats)
+	data.ItemPairStats = fromEntries(record.ItemPairStats)
+	data.MatchupStats = fromEntries(record.MatchupStats)
+	data.DuoMatchupStats = fromEntries(record.DuoMatchupStats)
+	data.MatchupDurationStats = fromEntries(record.MatchupDurationStats)
+	data.AllyPairStats = fromEntries(record.AllyPairStats)
+	data.SpellPairStats = fromEntries(record.SpellPairStats)
+	return data, nil
+}
+
+// ReplayDLQ re-pushes every dead-lettered aggregation, oldest first, and deletes the
+// files that push successfully. Failed files stay for the next replay; their errors
+// are joined into the returned error alongside the count that were replayed.
+func (t *TursoPusher) ReplayDLQ(ctx context.Context) (int, error) {
+	if t.dlqDir == "" {
+		return 0, nil
+	}
+	files, err := filepath.Glob(filepath.Join(t.dlqDir, DLQFilePattern))
+	if err != nil {
+		return 0, err
+	}
+	sort.Strings(files)
+
+	replayed := 0
+	var errs []error
+	for _, path := range files {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		data, err := readDLQFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := t.pusher.PushAggData(ctx, data); err != nil {
+			errs = append(errs, fmt.Errorf("replay %s: %w", filepath.Base(path), err))
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, fmt.Errorf("remove replayed %s: %w", filepath.Base(path), err))
+		}
+		replayed++
+		log.Printf("[TursoPusher] Replayed failed push %s", filepath.Base(path))
+	}
+	return replayed, errors.Join(errs...)
+}
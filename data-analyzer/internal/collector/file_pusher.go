@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"data-analyzer/internal/db"
+)
+
+// FilePusher is a DataPusher backed by an on-disk SQLite file. It shares the Turso
+// schema and upsert path, so it drops in for TursoDataPusher during local development.
+type FilePusher struct {
+	*TursoDataPusher
+	client *db.TursoClient
+}
+
+// NewFilePusher opens (or creates) the SQLite file at path
+func NewFilePusher(path string) (*FilePusher, error) {
+	client, err := db.NewSQLiteClient(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FilePusher{TursoDataPusher: NewTursoDataPusher(client), client: client}, nil
+}
+
+// Close closes the underlying SQLite file
+func (p *FilePusher) Close() error {
+	return p.client.Close()
+}
+
+// ExportToSQLite writes agg into a fresh SQLite file at path, replacing any existing
+// file, as a self-contained stats snapshot. It goes through the same schema and upsert
+// path as FilePusher and only moves the file into place once the write succeeded.
+func ExportToSQLite(agg *AggData, path string) error {
+	tmp := path + ".tmp"
+	if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove stale export: %w", err)
+	}
+
+	pusher, err := NewFilePusher(tmp)
+	if err != nil {
+		return err
+	}
+	if err := pusher.PushAggData(context.Background(), agg); err != nil {
+		pusher.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("write export: %w", err)
+	}
+	if err := pusher.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("close export: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("move export into place: %w", err)
+	}
+	return nil
+}
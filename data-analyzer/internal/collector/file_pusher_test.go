@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilePusher_RowsSurviveReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.db")
+	ctx := context.Background()
+
+	push := func(wins, matches int) {
+		t.Helper()
+		pusher, err := NewFilePusher(path)
+		if err != nil {
+			t.Fatalf("NewFilePusher failed: %v", err)
+		}
+		defer pusher.Close()
+
+		data := newAggData()
+		data.DetectedPatch = "15.24"
+		data.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE"}] = &ChampionStats{Wins: wins, Matches: matches}
+		if err := pusher.PushAggData(ctx, data); err != nil {
+			t.Fatalf("PushAggData failed: %v", err)
+		}
+	}
+
+	// Two pushes from separate pushers, as two pipeline runs would do
+	push(10, 20)
+	push(3, 5)
+
+	sqlDB, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to reopen sqlite: %v", err)
+	}
+	defer sqlDB.Close()
+
+	var wins, matches int
+	if err := sqlDB.QueryRow(`SELECT wins, matches FROM champion_stats WHERE champion_id = 103`).Scan(&wins, &matches); err != nil {
+		t.Fatalf("Failed to read champion stats: %v", err)
+	}
+	if wins != 13 || matches != 25 {
+		t.Errorf("got wins=%d matches=%d, want 13/25 accumulated across runs", wins, matches)
+	}
+
+	var patch string
+	if err := sqlDB.QueryRow(`SELECT patch FROM data_version WHERE id = 1`).Scan(&patch); err != nil {
+		t.Fatalf("Failed to read data version: %v", err)
+	}
+	if patch != "15.24" {
+		t.Errorf("data_version patch = %q, want 15.24", patch)
+	}
+}
+
+func TestExportToSQLite_AllTablesPopulated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.db")
+	// A leftover file must be replaced, not merged into
+	if err := os.WriteFile(path, []byte("not a database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	agg := newAggData()
+	agg.DetectedPatch = "15.24"
+	agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE"}] = &ChampionStats{Wins: 6, Matches: 10}
+	agg.ItemStats[ItemStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", ItemID: 3089}] = &ItemStats{Wins: 4, Matches: 7}
+	agg.ItemSlotStats[ItemSlotStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", ItemID: 3089, BuildSlot: 1}] = &ItemSlotStats{Wins: 2, Matches: 3}
+	matchup := MatchupStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", EnemyChampionID: 238}
+	agg.MatchupStats[matchup] = &MatchupStats{Wins: 5, Matches: 9}
+	agg.MatchupDurationStats[MatchupDurationStatsKey{MatchupStatsKey: matchup, DurationBucket: DurationMid}] = &MatchupStats{Wins: 3, Matches: 5}
+	agg.AllyPairStats[AllyPairStatsKey{Patch: "15.24", ChampionID: 103, AllyChampionID: 64}] = &MatchupStats{Wins: 4, Matches: 6}
+	agg.SpellPairStats[SpellPairStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", Spell1ID: 4, Spell2ID: 14}] = &MatchupStats{Wins: 5, Matches: 8}
+	agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, GameMode: GameModeARAM}] = &ChampionStats{Wins: 2, Matches: 4}
+	agg.ItemStats[ItemStatsKey{Patch: "15.24", ChampionID: 103, GameMode: GameModeARAM, ItemID: 3089}] = &ItemStats{Wins: 1, Matches: 2}
+
+	if err := ExportToSQLite(agg, path); err != nil {
+		t.Fatalf("ExportToSQLite failed: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary export file left behind: %v", err)
+	}
+
+	sqlDB, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to reopen export: %v", err)
+	}
+	defer sqlDB.Close()
+
+	tables := []string{
+		"champion_stats", "champion_items", "champion_item_slots", "champion_matchups",
+		"champion_matchup_durations", "champion_ally_pairs", "champion_spells",
+		"aram_champion_stats", "aram_champion_items", "data_version",
+	}
+	for _, table := range tables {
+		var n int
+		if err := sqlDB.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+			t.Errorf("%s: %v", table, err)
+			continue
+		}
+		if n != 1 {
+			t.Errorf("%s has %d rows, want 1", table, n)
+		}
+	}
+}
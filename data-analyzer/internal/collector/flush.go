@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"data-analyzer/internal/storage"
+)
+
+// WarmFlush aggregates, archives and pushes whatever is left in warm in one pass.
+// It backs the final reduce on shutdown, when warm usually holds fewer files than
+// the reduce threshold.
+type WarmFlush struct {
+	WarmDir      string
+	ColdDir      string
+	ItemFilter   ItemFilter
+	Options      AggregateOptions
+	Compressor   storage.Compressor
+	ShardByPatch bool       // Archive into per-patch cold subdirectories
+	Pusher       DataPusher // Pushed synchronously; nil skips the push
+}
+
+// Run flushes warm and returns the aggregation, or nil if there was nothing to flush
+func (f WarmFlush) Run(ctx context.Context) (*AggData, error) {
+	files, err := filepath.Glob(filepath.Join(f.WarmDir, WarmFilePattern))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		log.Println("[Reduce] Final flush: warm is empty")
+		return nil, nil
+	}
+
+	// Same marker as a normal reduce, so a crash here is reconciled on restart
+	if err := MarkReduceStarted(f.WarmDir); err != nil {
+		return nil, fmt.Errorf("write reduce marker: %w", err)
+	}
+
+	agg, err := AggregateWarmFilesWithOptions(f.WarmDir, f.ItemFilter, f.Options)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate warm files: %w", err)
+	}
+
+	archive := ArchiveWarmToColdCounted
+	if f.ShardByPatch {
+		archive = ArchiveWarmToColdByPatchCounted
+	}
+	archived, err := archive(f.WarmDir, f.ColdDir, f.Compressor)
+	if err != nil {
+		return agg, fmt.Errorf("archive warm files: %w", err)
+	}
+	if err := ClearReduceMarker(f.WarmDir); err != nil {
+		log.Printf("[Reduce] Warning: failed to clear reduce marker: %v", err)
+	}
+	log.Printf("[Reduce] Final flush: aggregated %d records, archived %d files", agg.TotalRecords, archived.Files)
+	log.Printf("[Reduce] Final flush anomaly report: %s", agg.AnomalyReport())
+
+	if f.Pusher != nil && agg.TotalRecords > 0 {
+		if err := f.Pusher.PushAggData(ctx, agg); err != nil {
+			return agg, fmt.Errorf("push final aggregation: %w", err)
+		}
+		log.Println("[Reduce] Final flush: pushed")
+	}
+	return agg, nil
+}
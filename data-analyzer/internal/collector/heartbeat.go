@@ -0,0 +1,39 @@
+package collector
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// heartbeat touches a file on an interval so external watchdogs can detect a hung
+// collector from the file's age
+type heartbeat struct {
+	path     string
+	interval time.Duration
+	last     time.Time
+}
+
+// beat updates the file's mtime to now if at least interval has passed since the last beat
+func (h *heartbeat) beat(now time.Time) error {
+	if h.path == "" || (!h.last.IsZero() && now.Sub(h.last) < h.interval) {
+		return nil
+	}
+	h.last = now // a failed write is retried next interval, not on every loop
+	return touchFile(h.path, now)
+}
+
+// touchFile creates path if needed and sets its access and modification times to t
+func touchFile(path string, t time.Time) error {
+	err := os.Chtimes(path, t, t)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Chtimes(path, t, t)
+}
@@ -0,0 +1,69 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat_AdvancesPerTickAndStopsOnStall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "heartbeat")
+	config := DefaultConfig()
+	config.HeartbeatFile = path
+	config.HeartbeatInterval = 30 * time.Second
+	cc := NewContinuousCollector(nil, nil, nil, nil, nil, config)
+
+	mtime := func() time.Time {
+		t.Helper()
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("heartbeat file missing: %v", err)
+		}
+		return info.ModTime()
+	}
+
+	// Fake clock: the main loop runs many times per interval
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cc.beatHeartbeat(now)
+	if !mtime().Equal(now) {
+		t.Fatalf("first beat: mtime %v, want %v", mtime(), now)
+	}
+
+	cc.beatHeartbeat(now.Add(10 * time.Second))
+	if !mtime().Equal(now) {
+		t.Errorf("beat within the interval should not touch the file")
+	}
+
+	for i := 1; i <= 3; i++ {
+		tick := now.Add(time.Duration(i) * 30 * time.Second)
+		cc.beatHeartbeat(tick)
+		if !mtime().Equal(tick) {
+			t.Errorf("tick %d: mtime %v, want %v", i, mtime(), tick)
+		}
+	}
+	last := mtime()
+
+	// The stall watchdog flags no progress; the heartbeat must go stale
+	cc.stalled.Store(true)
+	cc.beatHeartbeat(now.Add(5 * time.Minute))
+	if !mtime().Equal(last) {
+		t.Errorf("heartbeat advanced during a stall: %v", mtime())
+	}
+
+	// Progress resumes
+	cc.stalled.Store(false)
+	resumed := now.Add(6 * time.Minute)
+	cc.beatHeartbeat(resumed)
+	if !mtime().Equal(resumed) {
+		t.Errorf("heartbeat should resume after the stall clears")
+	}
+}
+
+func TestHeartbeat_DisabledWithoutPath(t *testing.T) {
+	cc := NewContinuousCollector(nil, nil, nil, nil, nil, DefaultConfig())
+	cc.beatHeartbeat(time.Now()) // must not panic or write anywhere
+	if cc.heartbeat.last != (time.Time{}) {
+		t.Error("heartbeat without a file should stay idle")
+	}
+}
@@ -2,12 +2,24 @@ package collector
 
 import (
 	"bufio"
-	"compress/gzip"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
 	"io"
+	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"data-analyzer/internal/riot"
 	"data-analyzer/internal/storage"
 
 	json "github.com/goccy/go-json"
@@ -18,6 +30,7 @@ type ChampionStatsKey struct {
 	Patch        string
 	ChampionID   int
 	TeamPosition string
+	GameMode     string // "" for Summoner's Rift ranked, GameModeARAM for ARAM (no position)
 }
 
 // ChampionStats holds aggregated champion statistics
@@ -31,6 +44,7 @@ type ItemStatsKey struct {
 	Patch        string
 	ChampionID   int
 	TeamPosition string
+	GameMode     string // Same convention as ChampionStatsKey.GameMode
 	ItemID       int
 }
 
@@ -54,13 +68,67 @@ type MatchupStats struct {
 	Matches int
 }
 
+// DuoMatchupStatsKey is the composite key for bot-lane duo matchups (ADC+Support vs ADC+Support)
+type DuoMatchupStatsKey struct {
+	Patch             string
+	ADCChampionID     int
+	SupportChampionID int
+	EnemyADCID        int
+	EnemySupportID    int
+}
+
+// AllyPairStatsKey is the composite key for two champions on the same team.
+// ChampionID is always the lower of the two IDs so each pair has one key.
+type AllyPairStatsKey struct {
+	Patch          string
+	ChampionID     int
+	AllyChampionID int
+}
+
+// SpellPairStatsKey is the composite key for a champion's summoner spell pair.
+// Spell1ID is always the lower ID, so D/F placement doesn't split the stats.
+type SpellPairStatsKey struct {
+	Patch        string
+	ChampionID   int
+	TeamPosition string
+	Spell1ID     int
+	Spell2ID     int
+}
+
+// MatchupDurationStatsKey is a matchup key split by game-length bucket
+type MatchupDurationStatsKey struct {
+	MatchupStatsKey
+	DurationBucket string
+}
+
+// Game-length buckets used for duration-split matchup stats
+const (
+	DurationEarly = "early" // under 25 minutes
+	DurationMid   = "mid"   // 25 to 35 minutes
+	DurationLate  = "late"  // 35 minutes and over
+)
+
+// durationBucket maps a game duration in seconds to its bucket, or "" if unknown
+func durationBucket(seconds int) string {
+	switch {
+	case seconds <= 0:
+		return ""
+	case seconds < 25*60:
+		return DurationEarly
+	case seconds < 35*60:
+		return DurationMid
+	default:
+		return DurationLate
+	}
+}
+
 // ItemSlotStatsKey is the composite key for item slot stats
 type ItemSlotStatsKey struct {
 	Patch        string
 	ChampionID   int
 	TeamPosition string
 	ItemID       int
-	BuildSlot    int // 1-6 for first through sixth completed item
+	BuildSlot    int // 1-based completed item order (1-6 unless the slot cap is raised)
 }
 
 // ItemSlotStats holds aggregated item slot statistics
@@ -69,122 +137,606 @@ type ItemSlotStats struct {
 	Matches int
 }
 
+// ItemPairStatsKey is the composite key for two completed items in one final inventory.
+// ItemA is always the lower ID so each pair has one key.
+type ItemPairStatsKey struct {
+	Patch        string
+	ChampionID   int
+	TeamPosition string
+	ItemA        int
+	ItemB        int
+}
+
+// ItemPairStats holds aggregated item pair statistics
+type ItemPairStats struct {
+	Wins    int
+	Matches int
+}
+
 // AggData holds all aggregated statistics from warm files
 type AggData struct {
-	ChampionStats  map[ChampionStatsKey]*ChampionStats
-	ItemStats      map[ItemStatsKey]*ItemStats
-	ItemSlotStats  map[ItemSlotStatsKey]*ItemSlotStats
-	MatchupStats   map[MatchupStatsKey]*MatchupStats
-	DetectedPatch  string
-	FilesProcessed int
-	TotalRecords   int
+	ChampionStats        map[ChampionStatsKey]*ChampionStats
+	ItemStats            map[ItemStatsKey]*ItemStats
+	ItemSlotStats        map[ItemSlotStatsKey]*ItemSlotStats
+	ItemPairStats        map[ItemPairStatsKey]*ItemPairStats // Completed items finished together, not pushed yet
+	MatchupStats         map[MatchupStatsKey]*MatchupStats
+	DuoMatchupStats      map[DuoMatchupStatsKey]*MatchupStats      // Bot lane 2v2 matchups, not pushed by default
+	MatchupDurationStats map[MatchupDurationStatsKey]*MatchupStats // Matchups split by game length
+	AllyPairStats        map[AllyPairStatsKey]*MatchupStats        // Win rates of champion pairs on the same team
+	SpellPairStats       map[SpellPairStatsKey]*MatchupStats       // Win rates per summoner spell pair
+	DetectedPatch        string
+	FilesProcessed       int
+	TotalRecords         int
+	FileTimings          []FileTiming              // Per-file aggregation time, in processing order
+	TotalDuration        time.Duration             // Wall time spent aggregating all files
+	PushID               string                    // Idempotency key so a retried push is applied once
+	PatchTimeRanges      map[string]*TimeRange     // gameCreation range seen per patch
+	CorruptMatches       int                       // Full matches with no winner, skipped for matchups
+	DuplicateRecords     int                       // Repeated matchId+puuid rows, counted once
+	PatchRecords         map[string]int            // Records seen per patch; DetectedPatch is the largest
+	PatchVersions        map[string]map[string]int // Records per full GameVersion under each normalized patch
+	ItemCompleteness     map[int]int               // Completed items in final inventory (0-6) -> participants
+	LowItemRecords       int                       // Participants left out of item stats by MinCompletedItems
+	SkippedLines         int                       // Lines that failed to parse as a RawMatch
+	AsymmetricLanes      int                       // Match positions without one winner and one loser, skipped for matchups
+	PartialFiles         int                       // Files whose read failed partway; records before the failure are kept
+	DuplicateMatches     int                       // Matches already counted from an earlier file, skipped whole
+	ShortGameRecords     int                       // Participants of games shorter than MinGameDurationSeconds (remakes)
+
+	matchIDs map[string]bool // Match IDs read from a single file, for dedup across files
+}
+
+// TimeRange is the earliest and latest gameCreation (Unix ms) seen for a patch,
+// so callers can derive how long after release a patch's games were played
+type TimeRange struct {
+	First int64
+	Last  int64
+}
+
+// observe widens the range to include ts (zero timestamps are ignored)
+func (r *TimeRange) observe(ts int64) {
+	if ts <= 0 {
+		return
+	}
+	if r.First == 0 || ts < r.First {
+		r.First = ts
+	}
+	if ts > r.Last {
+		r.Last = ts
+	}
+}
+
+// FileTiming records how long a single warm file took to aggregate. Duration is the
+// file's own time on its worker; with several workers the files overlap, so the
+// durations can add up to more than AggData.TotalDuration.
+type FileTiming struct {
+	Path     string
+	Records  int
+	Duration time.Duration
+}
+
+// RecordsPerSecond returns overall aggregation throughput, or 0 if nothing was timed
+func (a *AggData) RecordsPerSecond() float64 {
+	if a.TotalDuration <= 0 {
+		return 0
+	}
+	return float64(a.TotalRecords) / a.TotalDuration.Seconds()
 }
 
 // ItemFilter is a function that determines if an item should be included in stats
 type ItemFilter func(itemID int) bool
 
+// newAggData returns an empty AggData with a fresh push ID
+func newAggData() *AggData {
+	agg := emptyAggData()
+	agg.PushID = newPushID()
+	return agg
+}
+
+// emptyAggData returns an AggData with all maps initialized
+func emptyAggData() *AggData {
+	return &AggData{
+		ChampionStats:        make(map[ChampionStatsKey]*ChampionStats),
+		ItemStats:            make(map[ItemStatsKey]*ItemStats),
+		ItemSlotStats:        make(map[ItemSlotStatsKey]*ItemSlotStats),
+		ItemPairStats:        make(map[ItemPairStatsKey]*ItemPairStats),
+		MatchupStats:         make(map[MatchupStatsKey]*MatchupStats),
+		DuoMatchupStats:      make(map[DuoMatchupStatsKey]*MatchupStats),
+		PatchTimeRanges:      make(map[string]*TimeRange),
+		PatchRecords:         make(map[string]int),
+		PatchVersions:        make(map[string]map[string]int),
+		ItemCompleteness:     make(map[int]int),
+		MatchupDurationStats: make(map[MatchupDurationStatsKey]*MatchupStats),
+		AllyPairStats:        make(map[AllyPairStatsKey]*MatchupStats),
+		SpellPairStats:       make(map[SpellPairStatsKey]*MatchupStats),
+	}
+}
+
+// newPushID returns a random identifier used to make pushes of one AggData idempotent
+func newPushID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// DefaultMaxBuildSlots is the number of build-order slots tracked per match
+const DefaultMaxBuildSlots = 6
+
+// UnknownPosition is the champion-stats bucket for records without a TeamPosition
+const UnknownPosition = "UNKNOWN"
+
+// GameModeARAM marks stat keys aggregated from ARAM games
+const GameModeARAM = "ARAM"
+
+// gameModeForQueue maps a queue ID to the stat keys' GameMode; "" is Summoner's Rift
+func gameModeForQueue(queueID int) string {
+	if queueID == riot.QueueARAM {
+		return GameModeARAM
+	}
+	return ""
+}
+
+// AggregateOptions tunes how match records are aggregated
+type AggregateOptions struct {
+	MaxBuildSlots int // Build-order slots tracked per match
+
+	// CountUnknownPosition counts records with an empty TeamPosition under
+	// UnknownPosition in champion stats (never matchups). Default skips them.
+	CountUnknownPosition bool
+
+	// MinCompletedItems leaves participants with fewer completed items in their final
+	// inventory (early surrenders) out of item and item-slot stats. 0 counts everyone.
+	MinCompletedItems int
+
+	// MinGameDurationSeconds drops participants of shorter games (remakes) before any
+	// stats are counted. Records without a duration are kept. 0 keeps every game.
+	MinGameDurationSeconds int
+
+	// Progress, if set, is called after each file with its path and the running count.
+	// Calls come from the merging goroutine in file order.
+	Progress func(path string, done, total int)
+
+	// Workers is how many files are aggregated in parallel; 0 uses runtime.NumCPU()
+	Workers int
+
+	// SlotSampleInterval feeds only 1 in N matches into item-slot stats, which are the
+	// costly part of a reduce and only need a sample. Champion, item and matchup stats
+	// still see every match. 0 or 1 uses every match.
+	SlotSampleInterval int
+}
+
+// slotSampled reports whether a match is in the item-slot sample. Hashing the match ID
+// keeps every participant of a match, and every file holding it, on the same side.
+func (o AggregateOptions) slotSampled(matchID string) bool {
+	if o.SlotSampleInterval <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(matchID))
+	return h.Sum32()%uint32(o.SlotSampleInterval) == 0
+}
+
+// DefaultAggregateOptions returns the options used by AggregateWarmFiles
+func DefaultAggregateOptions() AggregateOptions {
+	return AggregateOptions{
+		MaxBuildSlots:          DefaultMaxBuildSlots,
+		MinGameDurationSeconds: DefaultMinGameDurationSeconds,
+	}
+}
+
+// DefaultMinGameDurationSeconds drops remakes, which end around the 3-4 minute mark
+const DefaultMinGameDurationSeconds = 300
+
+// WarmFilePattern matches the files the rotator writes to warm. Other .jsonl files
+// (exports, temp files) are left alone by aggregation and archiving.
+const WarmFilePattern = "raw_matches_*.jsonl"
+
 // AggregateWarmFiles reads all JSONL files from the warm directory and aggregates stats
 func AggregateWarmFiles(warmDir string, itemFilter ItemFilter) (*AggData, error) {
-	agg := &AggData{
-		ChampionStats: make(map[ChampionStatsKey]*ChampionStats),
-		ItemStats:     make(map[ItemStatsKey]*ItemStats),
-		ItemSlotStats: make(map[ItemSlotStatsKey]*ItemSlotStats),
-		MatchupStats:  make(map[MatchupStatsKey]*MatchupStats),
-	}
+	return AggregateWarmFilesWithOptions(warmDir, itemFilter, DefaultAggregateOptions())
+}
+
+// AggregateWarmFilesWithSlots is AggregateWarmFiles with a custom build-slot cap, so
+// purchase sequences longer than six completed items can be analyzed.
+func AggregateWarmFilesWithSlots(warmDir string, itemFilter ItemFilter, maxBuildSlots int) (*AggData, error) {
+	opts := DefaultAggregateOptions()
+	opts.MaxBuildSlots = maxBuildSlots
+	return AggregateWarmFilesWithOptions(warmDir, itemFilter, opts)
+}
 
-	// Scan warm directory for .jsonl files
-	files, err := filepath.Glob(filepath.Join(warmDir, "*.jsonl"))
+// AggregateWarmFilesWithOptions is AggregateWarmFiles with explicit aggregation options
+func AggregateWarmFilesWithOptions(warmDir string, itemFilter ItemFilter, opts AggregateOptions) (*AggData, error) {
+	agg := newAggData()
+
+	// Scan warm directory for rotated match files
+	files, err := filepath.Glob(filepath.Join(warmDir, WarmFilePattern))
 	if err != nil {
 		return nil, err
 	}
 
+	aggregateFiles(agg, files, itemFilter, opts)
+	return agg, nil
+}
+
+// AggregateColdFiles re-aggregates archived files from the cold directory.
+// Both gzip (.gz) and zstd (.zst) archives are read, flat or sharded by patch, along
+// with any uncompressed .jsonl files copied in beside them.
+func AggregateColdFiles(coldDir string, itemFilter ItemFilter) (*AggData, error) {
+	agg := newAggData()
+
+	var files []string
+	for _, pattern := range []string{"*.jsonl", "*.jsonl.gz", "*.jsonl.zst"} {
+		matches, err := globCold(coldDir, pattern)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+
+	aggregateFiles(agg, files, itemFilter, DefaultAggregateOptions())
+	return agg, nil
+}
+
+// aggregateFiles aggregates each file and merges the results into agg
+func aggregateFiles(agg *AggData, files []string, itemFilter ItemFilter, opts AggregateOptions) {
+	start := time.Now()
+	defer func() { agg.TotalDuration = time.Since(start) }()
+
+	// Sort so results and logs don't depend on directory iteration order
+	files = append([]string(nil), files...)
+	sort.Strings(files)
 	if len(files) == 0 {
-		return agg, nil
+		return
 	}
 
-	// Process each file and accumulate stats
-	for _, filePath := range files {
-		championStats, itemStats, itemSlotStats, matchupStats, patch, records, err := aggregateFile(filePath, itemFilter)
-		if err != nil {
-			continue // Skip files with errors
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	// Workers aggregate files independently; results are merged strictly in file order,
+	// so the output is the same as a sequential pass whatever order workers finish in.
+	// A file is only handed out while fewer than mergeWindowPerWorker*workers files
+	// await merging, so a slow early file can't leave every later result held in memory.
+	jobs := make(chan int)
+	results := make(chan fileResult, workers)
+	window := make(chan struct{}, mergeWindowPerWorker*workers)
+	go func() {
+		defer close(jobs)
+		for i := range files {
+			window <- struct{}{}
+			jobs <- i
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fileStart := time.Now()
+				fileAgg, err := aggregateFile(files[i], itemFilter, opts)
+				results <- fileResult{index: i, agg: fileAgg, err: err, duration: time.Since(fileStart)}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]fileResult)
+	seen := make(map[string]bool) // Match IDs from files already merged
+	next := 0
+	for res := range results {
+		pending[res.index] = res
+		for {
+			ready, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			ready = skipSeenMatches(files[ready.index], ready, seen, itemFilter, opts)
+			agg.mergeFileResult(files[ready.index], ready, next, len(files), opts)
+			<-window
 		}
+	}
+}
+
+// mergeWindowPerWorker bounds how many files per worker may be in flight or waiting to
+// merge. Files merge in order, so this caps the per-file results held in memory.
+const mergeWindowPerWorker = 2
 
-		agg.FilesProcessed++
-		agg.TotalRecords += records
+// fileResult is one worker's aggregation of a single file
+type fileResult struct {
+	index    int
+	agg      *AggData
+	err      error
+	duration time.Duration
+}
 
-		// Track the patch (use the last one seen)
-		if patch != "" {
-			agg.DetectedPatch = patch
+// skipSeenMatches records a file's match IDs in seen. If some of them were already
+// merged from an earlier file (a rotation split the match, or the spider fetched it
+// twice), the file is aggregated again without those matches, so each counts once.
+func skipSeenMatches(filePath string, res fileResult, seen map[string]bool, itemFilter ItemFilter, opts AggregateOptions) fileResult {
+	if res.agg == nil {
+		return res
+	}
+	duplicates := make(map[string]bool)
+	for id := range res.agg.matchIDs {
+		if seen[id] {
+			duplicates[id] = true
 		}
+		seen[id] = true
+	}
+	if len(duplicates) == 0 {
+		return res
+	}
 
-		// Merge champion stats
-		for k, v := range championStats {
-			if existing, ok := agg.ChampionStats[k]; ok {
-				existing.Wins += v.Wins
-				existing.Matches += v.Matches
-			} else {
-				agg.ChampionStats[k] = v
-			}
+	start := time.Now()
+	res.agg, res.err = aggregateFileSkipping(filePath, itemFilter, opts, duplicates)
+	res.duration += time.Since(start)
+	if res.agg != nil {
+		res.agg.DuplicateMatches = len(duplicates)
+	}
+	return res
+}
+
+// mergeFileResult folds one file's result into agg; done is the 1-based position in file order
+func (agg *AggData) mergeFileResult(filePath string, res fileResult, done, total int, opts AggregateOptions) {
+	if opts.Progress != nil {
+		opts.Progress(filePath, done, total)
+	}
+	fileAgg := res.agg
+	if res.err != nil {
+		if fileAgg == nil {
+			log.Printf("[Reduce] Warning: skipping %s: %v", filepath.Base(filePath), res.err)
+			return
 		}
+		// Keep what was read before the failure rather than dropping the whole file
+		log.Printf("[Reduce] Warning: %s only partially aggregated: %v", filepath.Base(filePath), res.err)
+		fileAgg.PartialFiles = 1
+	}
 
-		// Merge item stats
-		for k, v := range itemStats {
-			if existing, ok := agg.ItemStats[k]; ok {
-				existing.Wins += v.Wins
-				existing.Matches += v.Matches
-			} else {
-				agg.ItemStats[k] = v
-			}
+	agg.merge(fileAgg)
+	agg.FileTimings = append(agg.FileTimings, FileTiming{
+		Path:     filePath,
+		Records:  fileAgg.TotalRecords,
+		Duration: res.duration,
+	})
+}
+
+// merge adds another (per-file) aggregation into agg
+func (agg *AggData) merge(other *AggData) {
+	agg.FilesProcessed++
+	agg.TotalRecords += other.TotalRecords
+	agg.CorruptMatches += other.CorruptMatches
+	agg.DuplicateRecords += other.DuplicateRecords
+
+	// Track the dominant patch across everything merged so far
+	for patch, n := range other.PatchRecords {
+		agg.PatchRecords[patch] += n
+	}
+	agg.DetectedPatch = dominantPatch(agg.PatchRecords)
+
+	for n, count := range other.ItemCompleteness {
+		agg.ItemCompleteness[n] += count
+	}
+	agg.LowItemRecords += other.LowItemRecords
+	agg.SkippedLines += other.SkippedLines
+	agg.AsymmetricLanes += other.AsymmetricLanes
+	agg.PartialFiles += other.PartialFiles
+	agg.DuplicateMatches += other.DuplicateMatches
+	agg.ShortGameRecords += other.ShortGameRecords
+
+	// Merge champion stats
+	for k, v := range other.ChampionStats {
+		if existing, ok := agg.ChampionStats[k]; ok {
+			existing.Wins += v.Wins
+			existing.Matches += v.Matches
+		} else {
+			agg.ChampionStats[k] = v
+		}
+	}
+
+	// Merge item stats
+	for k, v := range other.ItemStats {
+		if existing, ok := agg.ItemStats[k]; ok {
+			existing.Wins += v.Wins
+			existing.Matches += v.Matches
+		} else {
+			agg.ItemStats[k] = v
 		}
+	}
 
-		// Merge item slot stats
-		for k, v := range itemSlotStats {
-			if existing, ok := agg.ItemSlotStats[k]; ok {
-				existing.Wins += v.Wins
-				existing.Matches += v.Matches
-			} else {
-				agg.ItemSlotStats[k] = v
-			}
+	// Merge item slot stats
+	for k, v := range other.ItemSlotStats {
+		if existing, ok := agg.ItemSlotStats[k]; ok {
+			existing.Wins += v.Wins
+			existing.Matches += v.Matches
+		} else {
+			agg.ItemSlotStats[k] = v
 		}
+	}
 
-		// Merge matchup stats
-		for k, v := range matchupStats {
-			if existing, ok := agg.MatchupStats[k]; ok {
-				existing.Wins += v.Wins
-				existing.Matches += v.Matches
-			} else {
-				agg.MatchupStats[k] = v
-			}
+	// Merge item pair stats
+	for k, v := range other.ItemPairStats {
+		if existing, ok := agg.ItemPairStats[k]; ok {
+			existing.Wins += v.Wins
+			existing.Matches += v.Matches
+		} else {
+			agg.ItemPairStats[k] = v
 		}
 	}
 
-	return agg, nil
+	// Merge matchup stats
+	for k, v := range other.MatchupStats {
+		if existing, ok := agg.MatchupStats[k]; ok {
+			existing.Wins += v.Wins
+			existing.Matches += v.Matches
+		} else {
+			agg.MatchupStats[k] = v
+		}
+	}
+
+	// Merge duo matchup stats
+	for k, v := range other.DuoMatchupStats {
+		if existing, ok := agg.DuoMatchupStats[k]; ok {
+			existing.Wins += v.Wins
+			existing.Matches += v.Matches
+		} else {
+			agg.DuoMatchupStats[k] = v
+		}
+	}
+
+	// Merge duration-split matchup stats
+	for k, v := range other.MatchupDurationStats {
+		if existing, ok := agg.MatchupDurationStats[k]; ok {
+			existing.Wins += v.Wins
+			existing.Matches += v.Matches
+		} else {
+			agg.MatchupDurationStats[k] = v
+		}
+	}
+
+	// Merge ally pair stats
+	for k, v := range other.AllyPairStats {
+		if existing, ok := agg.AllyPairStats[k]; ok {
+			existing.Wins += v.Wins
+			existing.Matches += v.Matches
+		} else {
+			agg.AllyPairStats[k] = v
+		}
+	}
+
+	// Merge spell pair stats
+	for k, v := range other.SpellPairStats {
+		if existing, ok := agg.SpellPairStats[k]; ok {
+			existing.Wins += v.Wins
+			existing.Matches += v.Matches
+		} else {
+			agg.SpellPairStats[k] = v
+		}
+	}
+
+	// Merge full game versions
+	for patch, versions := range other.PatchVersions {
+		for version, n := range versions {
+			agg.addVersion(patch, version, n)
+		}
+	}
+
+	// Merge gameCreation ranges
+	for patch, r := range other.PatchTimeRanges {
+		existing, ok := agg.PatchTimeRanges[patch]
+		if !ok {
+			existing = &TimeRange{}
+			agg.PatchTimeRanges[patch] = existing
+		}
+		existing.observe(r.First)
+		existing.observe(r.Last)
+	}
+}
+
+// FilterByMinMatches removes ChampionStats, ItemStats, ItemSlotStats and MatchupStats
+// entries with fewer than min matches, and returns how many entries were dropped.
+// Turso pushes add to the stored counts, so filter only aggregations that are written
+// whole (exports), not incremental pushes, or the dropped games are lost for good.
+func (agg *AggData) FilterByMinMatches(min int) int {
+	dropped := 0
+	for k, v := range agg.ChampionStats {
+		if v.Matches < min {
+			delete(agg.ChampionStats, k)
+			dropped++
+		}
+	}
+	for k, v := range agg.ItemStats {
+		if v.Matches < min {
+			delete(agg.ItemStats, k)
+			dropped++
+		}
+	}
+	for k, v := range agg.ItemSlotStats {
+		if v.Matches < min {
+			delete(agg.ItemSlotStats, k)
+			dropped++
+		}
+	}
+	for k, v := range agg.MatchupStats {
+		if v.Matches < min {
+			delete(agg.MatchupStats, k)
+			dropped++
+		}
+	}
+	return dropped
 }
 
-// aggregateFile processes a single JSONL file and returns per-file stats
-func aggregateFile(filePath string, itemFilter ItemFilter) (
-	map[ChampionStatsKey]*ChampionStats,
-	map[ItemStatsKey]*ItemStats,
-	map[ItemSlotStatsKey]*ItemSlotStats,
-	map[MatchupStatsKey]*MatchupStats,
-	string, int, error,
-) {
-	file, err := os.Open(filePath)
+// aggregateFile processes a single JSONL file (plain or compressed) and returns per-file stats.
+// If reading fails partway, the stats gathered before the failure are returned with the error.
+func aggregateFile(filePath string, itemFilter ItemFilter, opts AggregateOptions) (*AggData, error) {
+	return aggregateFileSkipping(filePath, itemFilter, opts, nil)
+}
+
+// aggregateFileSkipping is aggregateFile ignoring every row of the matches in skip
+func aggregateFileSkipping(filePath string, itemFilter ItemFilter, opts AggregateOptions, skip map[string]bool) (*AggData, error) {
+	file, err := storage.OpenMaybeCompressed(filePath)
 	if err != nil {
-		return nil, nil, nil, nil, "", 0, err
+		return nil, err
 	}
 	defer file.Close()
 
-	championStats := make(map[ChampionStatsKey]*ChampionStats)
-	itemStats := make(map[ItemStatsKey]*ItemStats)
-	itemSlotStats := make(map[ItemSlotStatsKey]*ItemSlotStats)
-	matchupStats := make(map[MatchupStatsKey]*MatchupStats)
-	var detectedPatch string
+	return aggregateReaderSkipping(file, itemFilter, opts, skip)
+}
+
+// recordChampionStats counts one game for the champion key
+func recordChampionStats(stats map[ChampionStatsKey]*ChampionStats, key ChampionStatsKey, win bool) {
+	if _, exists := stats[key]; !exists {
+		stats[key] = &ChampionStats{}
+	}
+	stats[key].Matches++
+	if win {
+		stats[key].Wins++
+	}
+}
+
+// recordItemStats counts one game for the item key
+func recordItemStats(stats map[ItemStatsKey]*ItemStats, key ItemStatsKey, win bool) {
+	if _, exists := stats[key]; !exists {
+		stats[key] = &ItemStats{}
+	}
+	stats[key].Matches++
+	if win {
+		stats[key].Wins++
+	}
+}
+
+// aggregateReader aggregates a stream of JSONL match records
+func aggregateReader(r io.Reader, itemFilter ItemFilter, opts AggregateOptions) (*AggData, error) {
+	return aggregateReaderSkipping(r, itemFilter, opts, nil)
+}
+
+// aggregateReaderSkipping is aggregateReader ignoring every row of the matches in skip
+func aggregateReaderSkipping(r io.Reader, itemFilter ItemFilter, opts AggregateOptions, skip map[string]bool) (*AggData, error) {
+	result := emptyAggData()
+	result.matchIDs = make(map[string]bool)
+	championStats := result.ChampionStats
+	itemStats := result.ItemStats
+	itemSlotStats := result.ItemSlotStats
+	matchupStats := result.MatchupStats
+	duoStats := result.DuoMatchupStats
 
 	// First pass: group all participants by matchId
 	matchParticipants := make(map[string][]storage.RawMatch)
+	seenParticipants := make(map[[2]string]bool) // matchId+puuid, to drop rows repeated by write retries
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
 
 	recordCount := 0
@@ -193,21 +745,80 @@ func aggregateFile(filePath string, itemFilter ItemFilter) (
 
 		var match storage.RawMatch
 		if err := json.Unmarshal(line, &match); err != nil {
+			result.SkippedLines++
 			continue
 		}
-
 		recordCount++
 
-		// Skip if no position
-		if match.TeamPosition == "" {
+		// Counted in an earlier file; like repeated rows, still counts as a record read
+		if skip[match.MatchID] {
+			continue
+		}
+		if match.MatchID != "" {
+			result.matchIDs[match.MatchID] = true
+		}
+
+		// Remakes: nobody finishes a build and the result says nothing about the matchup
+		if match.GameDuration > 0 && match.GameDuration < opts.MinGameDurationSeconds {
+			result.ShortGameRecords++
 			continue
 		}
 
+		// Keep only the first row per participant
+		if match.PUUID != "" {
+			key := [2]string{match.MatchID, match.PUUID}
+			if seenParticipants[key] {
+				result.DuplicateRecords++
+				continue
+			}
+			seenParticipants[key] = true
+		}
+
 		// Normalize patch version
 		patch := normalizePatch(match.GameVersion)
-		if detectedPatch == "" {
-			detectedPatch = patch
+		result.addVersion(patch, match.GameVersion, 1)
+
+		// ARAM has no lanes: keep it in its own keys and out of positional stats
+		if mode := gameModeForQueue(match.QueueID); mode != "" {
+			result.PatchRecords[patch]++
+			recordChampionStats(championStats, ChampionStatsKey{
+				Patch:      patch,
+				ChampionID: match.ChampionID,
+				GameMode:   mode,
+			}, match.Win)
+			for _, itemID := range uniqueCompletedItems([]int{match.Item0, match.Item1, match.Item2, match.Item3, match.Item4, match.Item5}, itemFilter) {
+				recordItemStats(itemStats, ItemStatsKey{
+					Patch:      patch,
+					ChampionID: match.ChampionID,
+					GameMode:   mode,
+					ItemID:     itemID,
+				}, match.Win)
+			}
+			continue
+		}
+
+		// No position: skip, or count toward champion stats only when enabled
+		if match.TeamPosition == "" {
+			if opts.CountUnknownPosition {
+				result.PatchRecords[patch]++
+				recordChampionStats(championStats, ChampionStatsKey{
+					Patch:        patch,
+					ChampionID:   match.ChampionID,
+					TeamPosition: UnknownPosition,
+				}, match.Win)
+			}
+			continue
+		}
+
+		result.PatchRecords[patch]++
+
+		// Track when this patch's games were played
+		timeRange, ok := result.PatchTimeRanges[patch]
+		if !ok {
+			timeRange = &TimeRange{}
+			result.PatchTimeRanges[patch] = timeRange
 		}
+		timeRange.observe(match.GameCreation)
 
 		// Aggregate champion stats
 		champKey := ChampionStatsKey{
@@ -216,69 +827,57 @@ func aggregateFile(filePath string, itemFilter ItemFilter) (
 			TeamPosition: match.TeamPosition,
 		}
 
-		if _, exists := championStats[champKey]; !exists {
-			championStats[champKey] = &ChampionStats{}
-		}
-		championStats[champKey].Matches++
-		if match.Win {
-			championStats[champKey].Wins++
-		}
-
-		// ITEM STATS: Always use final inventory (item0-5) for 100% of matches
-		finalItems := []int{match.Item0, match.Item1, match.Item2, match.Item3, match.Item4, match.Item5}
-		seenItems := make(map[int]bool)
-		for _, itemID := range finalItems {
-			// Skip empty, duplicates, and non-completed items
-			if itemID == 0 || seenItems[itemID] || !itemFilter(itemID) {
-				continue
+		recordChampionStats(championStats, champKey, match.Win)
+		recordSpellPair(result.SpellPairStats, champKey, match)
+
+		// Short games end with few items; optionally keep them out of item stats
+		finalItems := uniqueCompletedItems([]int{match.Item0, match.Item1, match.Item2, match.Item3, match.Item4, match.Item5}, itemFilter)
+		result.ItemCompleteness[len(finalItems)]++
+		if len(finalItems) < opts.MinCompletedItems {
+			result.LowItemRecords++
+		} else {
+			// ITEM STATS: Always use final inventory (item0-5) for 100% of matches
+			for _, itemID := range finalItems {
+				recordItemStats(itemStats, ItemStatsKey{
+					Patch:        patch,
+					ChampionID:   match.ChampionID,
+					TeamPosition: match.TeamPosition,
+					ItemID:       itemID,
+				}, match.Win)
 			}
-			seenItems[itemID] = true
 
-			itemKey := ItemStatsKey{
-				Patch:        patch,
-				ChampionID:   match.ChampionID,
-				TeamPosition: match.TeamPosition,
-				ItemID:       itemID,
-			}
+			// ITEM PAIR STATS: Every pair of completed items, at most 15 per participant
+			recordItemPairs(result.ItemPairStats, champKey, finalItems, match.Win)
 
-			if _, exists := itemStats[itemKey]; !exists {
-				itemStats[itemKey] = &ItemStats{}
-			}
-			itemStats[itemKey].Matches++
-			if match.Win {
-				itemStats[itemKey].Wins++
+			// ITEM SLOT STATS: Only process when BuildOrder exists (sampled matches), and
+			// only for the 1-in-SlotSampleInterval share of those when sampling is on.
+			// Slots are numbered after dedup, so a repeated purchase never consumes a slot.
+			buildOrder := match.BuildOrder
+			if !opts.slotSampled(match.MatchID) {
+				buildOrder = nil
 			}
-		}
+			for i, itemID := range uniqueCompletedItems(buildOrder, itemFilter) {
+				buildSlot := i + 1
 
-		// ITEM SLOT STATS: Only process when BuildOrder exists (sampled matches)
-		if len(match.BuildOrder) > 0 {
-			seenSlotItems := make(map[int]bool)
-			buildSlot := 0
-			for _, itemID := range match.BuildOrder {
-				// Skip empty, duplicates, and non-completed items
-				if itemID == 0 || seenSlotItems[itemID] || !itemFilter(itemID) {
-					continue
+				// Only track slots 1..maxBuildSlots
+				if buildSlot > opts.MaxBuildSlots {
+					break
+				}
+
+				slotKey := ItemSlotStatsKey{
+					Patch:        patch,
+					ChampionID:   match.ChampionID,
+					TeamPosition: match.TeamPosition,
+					ItemID:       itemID,
+					BuildSlot:    buildSlot,
+				}
+
+				if _, exists := itemSlotStats[slotKey]; !exists {
+					itemSlotStats[slotKey] = &ItemSlotStats{}
 				}
-				seenSlotItems[itemID] = true
-				buildSlot++
-
-				// Only track slots 1-6
-				if buildSlot <= 6 {
-					slotKey := ItemSlotStatsKey{
-						Patch:        patch,
-						ChampionID:   match.ChampionID,
-						TeamPosition: match.TeamPosition,
-						ItemID:       itemID,
-						BuildSlot:    buildSlot,
-					}
-
-					if _, exists := itemSlotStats[slotKey]; !exists {
-						itemSlotStats[slotKey] = &ItemSlotStats{}
-					}
-					itemSlotStats[slotKey].Matches++
-					if match.Win {
-						itemSlotStats[slotKey].Wins++
-					}
+				itemSlotStats[slotKey].Matches++
+				if match.Win {
+					itemSlotStats[slotKey].Wins++
 				}
 			}
 		}
@@ -287,29 +886,37 @@ func aggregateFile(filePath string, itemFilter ItemFilter) (
 		matchParticipants[match.MatchID] = append(matchParticipants[match.MatchID], match)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, nil, nil, nil, "", 0, err
-	}
+	// A read error (e.g. bufio.ErrTooLong) ends the scan, but the records before it are
+	// still good: finish aggregating them and return the partial result with the error
+	scanErr := scanner.Err()
 
 	// Second pass: calculate matchups from grouped participants
 	for _, participants := range matchParticipants {
+		// A complete match where nobody won can't come from a real game; it points at
+		// a serialization bug upstream, so count it and keep it out of matchups
+		if isCorruptMatch(participants) {
+			result.CorruptMatches++
+			continue
+		}
+
+		recordDuoMatchups(duoStats, participants)
+		recordAllyPairs(result.AllyPairStats, participants)
+
 		// Group by position
 		byPosition := make(map[string][]storage.RawMatch)
 		for _, p := range participants {
 			byPosition[p.TeamPosition] = append(byPosition[p.TeamPosition], p)
 		}
 
-		// For each position, find the two opponents (one winner, one loser)
+		// For each position, pair the winner against the loser. With five players a side,
+		// that only happens for a two-player lane split across the teams; a lane holding an
+		// off-role extra (role swaps, smurfs) or two players from one side can't say who
+		// laned against whom, so it's skipped and counted under AsymmetricLanes.
 		for _, posPlayers := range byPosition {
-			if len(posPlayers) != 2 {
-				continue // Skip if not exactly 2 players in this position
-			}
-
-			p1, p2 := posPlayers[0], posPlayers[1]
-
-			// They should be on opposite teams (one won, one lost)
-			if p1.Win == p2.Win {
-				continue // Same result = probably same team, skip
+			p1, p2, ok := laneOpponents(posPlayers)
+			if !ok {
+				result.AsymmetricLanes++
+				continue // Ambiguous or one-sided lane
 			}
 
 			patch := normalizePatch(p1.GameVersion)
@@ -343,10 +950,253 @@ func aggregateFile(filePath string, itemFilter ItemFilter) (
 			if p2.Win {
 				matchupStats[key2].Wins++
 			}
+
+			// Same matchups split by game length, when the duration is known
+			if bucket := durationBucket(p1.GameDuration); bucket != "" {
+				recordMatchupDuration(result.MatchupDurationStats, key1, bucket, p1.Win)
+				recordMatchupDuration(result.MatchupDurationStats, key2, bucket, p2.Win)
+			}
+		}
+	}
+
+	result.DetectedPatch = dominantPatch(result.PatchRecords)
+	result.TotalRecords = recordCount
+	if scanErr != nil {
+		return result, fmt.Errorf("scan stopped after %d records: %w", recordCount, scanErr)
+	}
+	return result, nil
+}
+
+// recordMatchupDuration counts one game for a matchup in a duration bucket
+func recordMatchupDuration(stats map[MatchupDurationStatsKey]*MatchupStats, key MatchupStatsKey, bucket string, win bool) {
+	durKey := MatchupDurationStatsKey{MatchupStatsKey: key, DurationBucket: bucket}
+	if _, exists := stats[durKey]; !exists {
+		stats[durKey] = &MatchupStats{}
+	}
+	stats[durKey].Matches++
+	if win {
+		stats[durKey].Wins++
+	}
+}
+
+// participantsPerMatch is the number of players in a standard 5v5 match
+const participantsPerMatch = 10
+
+// isCorruptMatch reports whether a full match has no winning participant.
+// Partial matches are not flagged since their winners may live in another file.
+func isCorruptMatch(participants []storage.RawMatch) bool {
+	if len(participants) < participantsPerMatch {
+		return false
+	}
+	for _, p := range participants {
+		if p.Win {
+			return false
+		}
+	}
+	return true
+}
+
+// laneOpponents returns the winner and loser in one position of a match. It reports
+// false unless exactly one player on each side is in the position, since any other mix
+// can't say who laned against whom.
+func laneOpponents(posPlayers []storage.RawMatch) (winner, loser storage.RawMatch, ok bool) {
+	var winners, losers int
+	for _, p := range posPlayers {
+		if p.Win {
+			winner = p
+			winners++
+		} else {
+			loser = p
+			losers++
+		}
+	}
+	return winner, loser, winners == 1 && losers == 1
+}
+
+// recordDuoMatchups records the bot-lane 2v2 matchup for one match. Teams are split by
+// result, and each side needs exactly one BOTTOM and one UTILITY player.
+func recordDuoMatchups(duoStats map[DuoMatchupStatsKey]*MatchupStats, participants []storage.RawMatch) {
+	type botLane struct {
+		adc, support []storage.RawMatch
+	}
+	var winners, losers botLane
+	for _, p := range participants {
+		side := &losers
+		if p.Win {
+			side = &winners
+		}
+		switch p.TeamPosition {
+		case "BOTTOM":
+			side.adc = append(side.adc, p)
+		case "UTILITY":
+			side.support = append(side.support, p)
+		}
+	}
+
+	for _, side := range []botLane{winners, losers} {
+		if len(side.adc) != 1 || len(side.support) != 1 {
+			return
+		}
+	}
+
+	patch := normalizePatch(winners.adc[0].GameVersion)
+	record := func(us, them botLane, win bool) {
+		key := DuoMatchupStatsKey{
+			Patch:             patch,
+			ADCChampionID:     us.adc[0].ChampionID,
+			SupportChampionID: us.support[0].ChampionID,
+			EnemyADCID:        them.adc[0].ChampionID,
+			EnemySupportID:    them.support[0].ChampionID,
+		}
+		if _, exists := duoStats[key]; !exists {
+			duoStats[key] = &MatchupStats{}
+		}
+		duoStats[key].Matches++
+		if win {
+			duoStats[key].Wins++
+		}
+	}
+	record(winners, losers, true)
+	record(losers, winners, false)
+}
+
+// recordSpellPair counts one game for the participant's summoner spell pair.
+// Records written before spells were stored are skipped.
+func recordSpellPair(stats map[SpellPairStatsKey]*MatchupStats, champKey ChampionStatsKey, match storage.RawMatch) {
+	a, b := match.Summoner1ID, match.Summoner2ID
+	if a == 0 || b == 0 {
+		return
+	}
+	if a > b {
+		a, b = b, a
+	}
+	key := SpellPairStatsKey{
+		Patch:        champKey.Patch,
+		ChampionID:   champKey.ChampionID,
+		TeamPosition: champKey.TeamPosition,
+		Spell1ID:     a,
+		Spell2ID:     b,
+	}
+	if _, exists := stats[key]; !exists {
+		stats[key] = &MatchupStats{}
+	}
+	stats[key].Matches++
+	if match.Win {
+		stats[key].Wins++
+	}
+}
+
+// recordItemPairs counts one game for every unordered pair of items. items must already
+// be deduped, as uniqueCompletedItems returns them.
+func recordItemPairs(stats map[ItemPairStatsKey]*ItemPairStats, champKey ChampionStatsKey, items []int, win bool) {
+	for i := 0; i < len(items); i++ {
+		for j := i + 1; j < len(items); j++ {
+			a, b := items[i], items[j]
+			if a > b {
+				a, b = b, a
+			}
+			key := ItemPairStatsKey{
+				Patch:        champKey.Patch,
+				ChampionID:   champKey.ChampionID,
+				TeamPosition: champKey.TeamPosition,
+				ItemA:        a,
+				ItemB:        b,
+			}
+			if _, exists := stats[key]; !exists {
+				stats[key] = &ItemPairStats{}
+			}
+			stats[key].Matches++
+			if win {
+				stats[key].Wins++
+			}
+		}
+	}
+}
+
+// recordAllyPairs counts every pair of teammates in one match. Teams come from TeamID,
+// falling back to the game result for records written before TeamID was stored.
+func recordAllyPairs(pairStats map[AllyPairStatsKey]*MatchupStats, participants []storage.RawMatch) {
+	teams := make(map[int][]storage.RawMatch)
+	for _, p := range participants {
+		team := p.TeamID
+		if team == 0 {
+			team = 100
+			if !p.Win {
+				team = 200
+			}
+		}
+		teams[team] = append(teams[team], p)
+	}
+
+	for _, members := range teams {
+		for i := 0; i < len(members); i++ {
+			for j := i + 1; j < len(members); j++ {
+				a, b := members[i].ChampionID, members[j].ChampionID
+				if a == b {
+					continue
+				}
+				if a > b {
+					a, b = b, a
+				}
+				key := AllyPairStatsKey{
+					Patch:          normalizePatch(members[i].GameVersion),
+					ChampionID:     a,
+					AllyChampionID: b,
+				}
+				if _, exists := pairStats[key]; !exists {
+					pairStats[key] = &MatchupStats{}
+				}
+				pairStats[key].Matches++
+				if members[i].Win {
+					pairStats[key].Wins++
+				}
+			}
+		}
+	}
+}
+
+// uniqueCompletedItems returns items in order with empties, duplicates, and
+// non-completed items removed. Both item passes use it so dedup stays consistent.
+func uniqueCompletedItems(items []int, itemFilter ItemFilter) []int {
+	seen := make(map[int]bool, len(items))
+	result := make([]int, 0, len(items))
+	for _, itemID := range items {
+		if itemID == 0 || seen[itemID] || !itemFilter(itemID) {
+			continue
 		}
+		seen[itemID] = true
+		result = append(result, itemID)
 	}
+	return result
+}
 
-	return championStats, itemStats, itemSlotStats, matchupStats, detectedPatch, recordCount, nil
+// addVersion counts n records of a full game version under its normalized patch
+func (agg *AggData) addVersion(patch, version string, n int) {
+	versions, ok := agg.PatchVersions[patch]
+	if !ok {
+		versions = make(map[string]int)
+		agg.PatchVersions[patch] = versions
+	}
+	versions[version] += n
+}
+
+// MergedPatchVersions returns, for each normalized patch fed by more than one full
+// GameVersion, the sorted distinct versions. A stray hotfix label shows up here
+// instead of being silently folded into its patch.
+func (agg *AggData) MergedPatchVersions() map[string][]string {
+	merged := make(map[string][]string)
+	for patch, versions := range agg.PatchVersions {
+		if len(versions) < 2 {
+			continue
+		}
+		list := make([]string, 0, len(versions))
+		for version := range versions {
+			list = append(list, version)
+		}
+		sort.Strings(list)
+		merged[patch] = list
+	}
+	return merged
 }
 
 // normalizePatch truncates version to first two segments (e.g., 14.23.448 -> 14.23)
@@ -358,67 +1208,284 @@ func normalizePatch(version string) string {
 	return version
 }
 
+// dominantPatch returns the patch with the most records. Ties go to the newer patch
+// so the result doesn't depend on map or file order.
+func dominantPatch(counts map[string]int) string {
+	best, bestCount := "", 0
+	for patch, n := range counts {
+		if n > bestCount || (n == bestCount && comparePatches(patch, best) > 0) {
+			best, bestCount = patch, n
+		}
+	}
+	return best
+}
+
+// comparePatches compares dotted patch versions numerically (15.10 > 15.9).
+// Non-numeric segments fall back to string comparison.
+func comparePatches(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		ai, aErr := strconv.Atoi(as[i])
+		bi, bErr := strconv.Atoi(bs[i])
+		if aErr != nil || bErr != nil {
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+			continue
+		}
+		if ai != bi {
+			if ai < bi {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// ArchiveResult summarizes one archive pass
+type ArchiveResult struct {
+	Files int // Files moved to cold
+	Lines int // Non-empty lines in those files, comparable to AggData.TotalRecords
+}
+
+// PruneResult summarizes one cold-storage prune
+type PruneResult struct {
+	Files     int   // Archives removed
+	Bytes     int64 // Bytes freed
+	Remaining int64 // Bytes left in cold
+}
+
+// PruneColdBySize removes the oldest cold archives (by modification time, flat or
+// sharded by patch) until cold holds at most maxBytes. maxBytes <= 0 disables pruning.
+func PruneColdBySize(coldDir string, maxBytes int64) (PruneResult, error) {
+	var result PruneResult
+	if maxBytes <= 0 {
+		return result, nil
+	}
+
+	type archive struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var archives []archive
+	for _, pattern := range []string{"*.jsonl.gz", "*.jsonl.zst"} {
+		matches, err := globCold(coldDir, pattern)
+		if err != nil {
+			return result, err
+		}
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			archives = append(archives, archive{path: path, size: info.Size(), modTime: info.ModTime()})
+			result.Remaining += info.Size()
+		}
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		if !archives[i].modTime.Equal(archives[j].modTime) {
+			return archives[i].modTime.Before(archives[j].modTime)
+		}
+		return archives[i].path < archives[j].path
+	})
+
+	for _, a := range archives {
+		if result.Remaining <= maxBytes {
+			break
+		}
+		if err := os.Remove(a.path); err != nil {
+			return result, fmt.Errorf("remove %s: %w", a.path, err)
+		}
+		result.Files++
+		result.Bytes += a.size
+		result.Remaining -= a.size
+	}
+	return result, nil
+}
+
 // ArchiveWarmToCold moves all .jsonl files from warm to cold with gzip compression.
 // Returns the number of files archived.
 func ArchiveWarmToCold(warmDir, coldDir string) (int, error) {
+	return ArchiveWarmToColdWith(warmDir, coldDir, storage.DefaultCompressor)
+}
+
+// ArchiveWarmToColdWith moves all .jsonl files from warm to cold using the given compressor.
+// Returns the number of files archived.
+func ArchiveWarmToColdWith(warmDir, coldDir string, compressor storage.Compressor) (int, error) {
+	result, err := ArchiveWarmToColdCounted(warmDir, coldDir, compressor)
+	return result.Files, err
+}
+
+// ArchiveWarmToColdCounted is ArchiveWarmToColdWith but also reports how many lines
+// were archived, so callers can check them against the aggregated record count.
+func ArchiveWarmToColdCounted(warmDir, coldDir string, compressor storage.Compressor) (ArchiveResult, error) {
+	var result ArchiveResult
+
 	// Ensure cold directory exists
 	if err := os.MkdirAll(coldDir, 0755); err != nil {
-		return 0, err
+		return result, err
 	}
 
-	// Scan warm directory for .jsonl files only
-	files, err := filepath.Glob(filepath.Join(warmDir, "*.jsonl"))
+	// Scan warm directory for rotated match files only
+	files, err := filepath.Glob(filepath.Join(warmDir, WarmFilePattern))
 	if err != nil {
-		return 0, err
+		return result, err
 	}
 
-	if len(files) == 0 {
-		return 0, nil
+	for _, srcPath := range files {
+		lines, err := archiveFile(srcPath, coldDir, compressor)
+		if err != nil {
+			return result, err
+		}
+		result.Files++
+		result.Lines += lines
+	}
+
+	return result, nil
+}
+
+// ArchiveWarmToColdByPatch is ArchiveWarmToColdWith but shards cold into per-patch
+// subdirectories (cold/15.24/...). The patch is read from the file's first record;
+// files without one go to cold/unknown.
+func ArchiveWarmToColdByPatch(warmDir, coldDir string, compressor storage.Compressor) (int, error) {
+	result, err := ArchiveWarmToColdByPatchCounted(warmDir, coldDir, compressor)
+	return result.Files, err
+}
+
+// ArchiveWarmToColdByPatchCounted is ArchiveWarmToColdByPatch with a line count
+func ArchiveWarmToColdByPatchCounted(warmDir, coldDir string, compressor storage.Compressor) (ArchiveResult, error) {
+	var result ArchiveResult
+
+	files, err := filepath.Glob(filepath.Join(warmDir, WarmFilePattern))
+	if err != nil {
+		return result, err
 	}
 
-	archived := 0
 	for _, srcPath := range files {
-		if err := archiveFile(srcPath, coldDir); err != nil {
-			return archived, err
+		patch := filePatch(srcPath)
+		if patch == "" {
+			patch = "unknown"
+		}
+
+		shardDir := filepath.Join(coldDir, patch)
+		if err := os.MkdirAll(shardDir, 0755); err != nil {
+			return result, err
+		}
+		lines, err := archiveFile(srcPath, shardDir, compressor)
+		if err != nil {
+			return result, err
 		}
-		archived++
+		result.Files++
+		result.Lines += lines
 	}
 
-	return archived, nil
+	return result, nil
 }
 
-// archiveFile compresses a single file to cold directory and removes the original
-func archiveFile(srcPath, coldDir string) error {
+// filePatch returns the normalized patch of the first record in a JSONL file, or ""
+func filePatch(path string) string {
+	file, err := storage.OpenMaybeCompressed(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		var match storage.RawMatch
+		if err := json.Unmarshal(scanner.Bytes(), &match); err != nil || match.GameVersion == "" {
+			continue
+		}
+		return normalizePatch(match.GameVersion)
+	}
+	return ""
+}
+
+// globCold matches pattern in the cold directory and in its per-patch shards
+func globCold(coldDir, pattern string) ([]string, error) {
+	flat, err := filepath.Glob(filepath.Join(coldDir, pattern))
+	if err != nil {
+		return nil, err
+	}
+	sharded, err := filepath.Glob(filepath.Join(coldDir, "*", pattern))
+	if err != nil {
+		return nil, err
+	}
+	return append(flat, sharded...), nil
+}
+
+// lineCounter counts non-empty lines written through it
+type lineCounter struct {
+	lines  int
+	inLine bool
+}
+
+func (c *lineCounter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b == '\n' {
+			if c.inLine {
+				c.lines++
+			}
+			c.inLine = false
+		} else {
+			c.inLine = true
+		}
+	}
+	return len(p), nil
+}
+
+// total includes a final line without a trailing newline
+func (c *lineCounter) total() int {
+	if c.inLine {
+		return c.lines + 1
+	}
+	return c.lines
+}
+
+// archiveFile compresses a single file to cold directory and removes the original.
+// Returns the number of non-empty lines archived.
+func archiveFile(srcPath, coldDir string, compressor storage.Compressor) (int, error) {
 	// Open source file
 	src, err := os.Open(srcPath)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	// Create gzipped destination
-	filename := filepath.Base(srcPath) + ".gz"
+	// Create compressed destination
+	filename := filepath.Base(srcPath) + compressor.Extension()
 	dstPath := filepath.Join(coldDir, filename)
 
 	dst, err := os.Create(dstPath)
 	if err != nil {
 		src.Close()
-		return err
+		return 0, err
 	}
 
-	// Write compressed content
-	gzWriter := gzip.NewWriter(dst)
-	if _, err := io.Copy(gzWriter, src); err != nil {
-		gzWriter.Close()
+	// Write compressed content, counting lines as they pass through
+	writer, err := compressor.NewWriter(dst)
+	if err != nil {
+		dst.Close()
+		src.Close()
+		os.Remove(dstPath)
+		return 0, err
+	}
+	var counter lineCounter
+	if _, err := io.Copy(writer, io.TeeReader(src, &counter)); err != nil {
+		writer.Close()
 		dst.Close()
 		src.Close()
 		os.Remove(dstPath) // Clean up on failure
-		return err
+		return 0, err
 	}
-	if err := gzWriter.Close(); err != nil {
+	if err := writer.Close(); err != nil {
 		dst.Close()
 		src.Close()
 		os.Remove(dstPath)
-		return err
+		return 0, err
 	}
 
 	// Close files before removing (required on Windows)
@@ -427,8 +1494,112 @@ func archiveFile(srcPath, coldDir string) error {
 
 	// Remove original file
 	if err := os.Remove(srcPath); err != nil {
-		return err
+		return 0, err
+	}
+
+	return counter.total(), nil
+}
+
+// ReduceMarkerFile is written to the warm directory while a reduce runs. Finding it
+// at startup means the last reduce was interrupted and warm/cold need reconciling.
+const ReduceMarkerFile = ".reduce-in-progress"
+
+// MarkReduceStarted writes the reduce-in-progress marker
+func MarkReduceStarted(warmDir string) error {
+	stamp := []byte(time.Now().UTC().Format(time.RFC3339))
+	return os.WriteFile(filepath.Join(warmDir, ReduceMarkerFile), stamp, 0644)
+}
+
+// ClearReduceMarker removes the reduce-in-progress marker, if present
+func ClearReduceMarker(warmDir string) error {
+	err := os.Remove(filepath.Join(warmDir, ReduceMarkerFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// RecoverInterruptedReduce reconciles warm with cold if a previous reduce left its marker
+// behind, then clears the marker. Returns whether a recovery was needed.
+func RecoverInterruptedReduce(warmDir, coldDir string) (bool, error) {
+	if _, err := os.Stat(filepath.Join(warmDir, ReduceMarkerFile)); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	removed, err := ReconcileWarmWithCold(warmDir, coldDir)
+	if err != nil {
+		return true, fmt.Errorf("reconcile interrupted reduce: %w", err)
+	}
+	log.Printf("[Reduce] Recovered interrupted reduce: removed %d warm files already archived", removed)
+	return true, ClearReduceMarker(warmDir)
+}
+
+// ReconcileWarmWithCold cleans up after a crash during archiving. For each warm .jsonl
+// that already has a cold copy, the warm original is removed if the cold copy
+// decompresses to the same content; otherwise the incomplete cold copy is removed so
+// the file is archived again. Returns the number of warm duplicates removed.
+func ReconcileWarmWithCold(warmDir, coldDir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(warmDir, WarmFilePattern))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, warmPath := range files {
+		var coldPaths []string
+		for _, ext := range []string{".gz", ".zst"} {
+			matches, err := globCold(coldDir, filepath.Base(warmPath)+ext)
+			if err != nil {
+				return removed, err
+			}
+			coldPaths = append(coldPaths, matches...)
+		}
+
+		for _, coldPath := range coldPaths {
+			if sameContent(warmPath, coldPath) {
+				if err := os.Remove(warmPath); err != nil {
+					return removed, err
+				}
+				removed++
+				break
+			}
+
+			// Partial archive - drop it and let the next archive pass redo it
+			if err := os.Remove(coldPath); err != nil {
+				return removed, err
+			}
+		}
+	}
+
+	return removed, nil
+}
+
+// sameContent reports whether a warm file and its compressed cold copy hold identical data
+func sameContent(warmPath, coldPath string) bool {
+	warmSum, err := fileChecksum(warmPath)
+	if err != nil {
+		return false
 	}
+	coldSum, err := fileChecksum(coldPath)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(warmSum, coldSum)
+}
 
-	return nil
+// fileChecksum returns the SHA-256 of a file's (decompressed) content
+func fileChecksum(path string) ([]byte, error) {
+	r, err := storage.OpenMaybeCompressed(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
 }
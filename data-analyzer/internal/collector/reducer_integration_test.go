@@ -50,7 +50,7 @@ func TestReduceCycle_FullIntegration(t *testing.T) {
 			totalMatches++
 		}
 
-		filePath := filepath.Join(warmDir, fmt.Sprintf("matches_%03d.jsonl", fileNum))
+		filePath := filepath.Join(warmDir, fmt.Sprintf("raw_matches_matches_%03d.jsonl", fileNum))
 		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
 			t.Fatalf("Failed to write file %d: %v", fileNum, err)
 		}
@@ -167,7 +167,7 @@ func TestReduceCycle_WithWarmLock(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		content := fmt.Sprintf(`{"matchId":"NA1_%d","gameVersion":"15.24.1","gameDuration":1800,"gameCreation":1700000000000,"puuid":"p1","championId":103,"championName":"Ahri","teamPosition":"MIDDLE","win":true,"item0":3089,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 `, i)
-		filePath := filepath.Join(warmDir, fmt.Sprintf("batch1_%03d.jsonl", i))
+		filePath := filepath.Join(warmDir, fmt.Sprintf("raw_matches_batch1_%03d.jsonl", i))
 		os.WriteFile(filePath, []byte(content), 0644)
 	}
 
@@ -209,7 +209,7 @@ func TestReduceCycle_WithWarmLock(t *testing.T) {
 		// Add a new file (this should happen AFTER reducer releases lock)
 		content := `{"matchId":"NA1_new","gameVersion":"15.24.1","gameDuration":1800,"gameCreation":1700000000000,"puuid":"p1","championId":103,"championName":"Ahri","teamPosition":"MIDDLE","win":true,"item0":3089,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 `
-		filePath := filepath.Join(warmDir, "batch2_000.jsonl")
+		filePath := filepath.Join(warmDir, "raw_matches_batch2_000.jsonl")
 		os.WriteFile(filePath, []byte(content), 0644)
 		filesAddedDuringReduce.Add(1)
 	}()
@@ -470,7 +470,7 @@ func TestAsyncPush_DoesNotBlockReducer(t *testing.T) {
 	content := `{"matchId":"NA1_1","gameVersion":"15.24.1","gameDuration":1800,"gameCreation":1700000000000,"puuid":"p1","championId":103,"championName":"Ahri","teamPosition":"MIDDLE","win":true,"item0":3089,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 {"matchId":"NA1_1","gameVersion":"15.24.1","gameDuration":1800,"gameCreation":1700000000000,"puuid":"p2","championId":238,"championName":"Zed","teamPosition":"MIDDLE","win":false,"item0":3142,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 `
-	os.WriteFile(filepath.Join(warmDir, "test.jsonl"), []byte(content), 0644)
+	os.WriteFile(filepath.Join(warmDir, "raw_matches_test.jsonl"), []byte(content), 0644)
 
 	// Create a slow mock pusher
 	slowPusher := &SlowMockPusher{delay: 200 * time.Millisecond}
@@ -560,7 +560,7 @@ func TestFullPipeline_Integration(t *testing.T) {
 {"matchId":"%s","gameVersion":"15.24.1","gameDuration":1800,"gameCreation":1700000000000,"puuid":"p2","championId":238,"championName":"Zed","teamPosition":"MIDDLE","win":false,"item0":3142,"item1":3071,"item2":0,"item3":0,"item4":0,"item5":0}
 `, matchID, matchID)
 		}
-		os.WriteFile(filepath.Join(warmDir, fmt.Sprintf("file_%d.jsonl", i)), []byte(content), 0644)
+		os.WriteFile(filepath.Join(warmDir, fmt.Sprintf("raw_matches_file_%d.jsonl", i)), []byte(content), 0644)
 	}
 
 	// Set up in-memory database
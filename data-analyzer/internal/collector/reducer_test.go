@@ -1,11 +1,18 @@
 package collector
 
 import (
+	"bytes"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"data-analyzer/internal/storage"
 )
 
 // Test 3.1: Aggregate warm files to memory
@@ -26,7 +33,7 @@ func TestAggregateWarmFiles_Basic(t *testing.T) {
 {"matchId":"NA1_2","gameVersion":"15.24.1","gameDuration":2100,"gameCreation":1700001000000,"puuid":"p3","championId":7,"championName":"LeBlanc","teamPosition":"MIDDLE","win":true,"item0":3089,"item1":3157,"item2":3020,"item3":0,"item4":0,"item5":0}
 `
 
-	jsonlPath := filepath.Join(warmDir, "test_001.jsonl")
+	jsonlPath := filepath.Join(warmDir, "raw_matches_test_001.jsonl")
 	if err := os.WriteFile(jsonlPath, []byte(sampleData), 0644); err != nil {
 		t.Fatalf("Failed to write sample JSONL: %v", err)
 	}
@@ -107,7 +114,7 @@ func TestAggregateWarmFiles_ItemStats(t *testing.T) {
 {"matchId":"NA1_2","gameVersion":"15.24.1","gameDuration":2100,"gameCreation":1700001000000,"puuid":"p3","championId":7,"championName":"LeBlanc","teamPosition":"MIDDLE","win":true,"item0":3157,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 `
 
-	jsonlPath := filepath.Join(warmDir, "test_001.jsonl")
+	jsonlPath := filepath.Join(warmDir, "raw_matches_test_001.jsonl")
 	if err := os.WriteFile(jsonlPath, []byte(sampleData), 0644); err != nil {
 		t.Fatalf("Failed to write sample JSONL: %v", err)
 	}
@@ -158,7 +165,7 @@ func TestAggregateWarmFiles_MatchupStats(t *testing.T) {
 {"matchId":"NA1_2","gameVersion":"15.24.1","gameDuration":2100,"gameCreation":1700001000000,"puuid":"p3","championId":7,"championName":"LeBlanc","teamPosition":"MIDDLE","win":true,"item0":3157,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 `
 
-	jsonlPath := filepath.Join(warmDir, "test_001.jsonl")
+	jsonlPath := filepath.Join(warmDir, "raw_matches_test_001.jsonl")
 	if err := os.WriteFile(jsonlPath, []byte(sampleData), 0644); err != nil {
 		t.Fatalf("Failed to write sample JSONL: %v", err)
 	}
@@ -241,7 +248,7 @@ func TestAggregateWarmFiles_ItemSlotStats(t *testing.T) {
 {"matchId":"NA1_1","gameVersion":"15.24.1","gameDuration":1800,"gameCreation":1700000000000,"puuid":"p2","championId":238,"championName":"Zed","teamPosition":"MIDDLE","win":false,"item0":3142,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 `
 
-	jsonlPath := filepath.Join(warmDir, "test_001.jsonl")
+	jsonlPath := filepath.Join(warmDir, "raw_matches_test_001.jsonl")
 	if err := os.WriteFile(jsonlPath, []byte(sampleData), 0644); err != nil {
 		t.Fatalf("Failed to write sample JSONL: %v", err)
 	}
@@ -302,28 +309,51 @@ func TestAggregateWarmFiles_MultipleFiles(t *testing.T) {
 		t.Fatalf("Failed to create warm directory: %v", err)
 	}
 
-	// File 1: Ahri wins
+	// File 1: Ahri beats Zed in mid; file 2: Ahri beats LeBlanc
+	GenerateWarmFile(t, warmDir, 1, WithFileName("raw_matches_test_001.jsonl"), WithFirstMatch(1),
+		WithRoles("MIDDLE"), WithChampions(103, 238), WithBlueWinRate(1),
+		WithPlayerItems([]int{3089}, []int{3142}), WithGameDuration(1800), WithGameCreation(1700000000000))
+	GenerateWarmFile(t, warmDir, 1, WithFileName("raw_matches_test_002.jsonl"), WithFirstMatch(2),
+		WithRoles("MIDDLE"), WithChampions(103, 7), WithBlueWinRate(1),
+		WithPlayerItems([]int{3089}, []int{3157}), WithGameDuration(2100), WithGameCreation(1700001000000))
+
+	itemFilter := func(itemID int) bool { return itemID >= 3000 }
+
+	agg, err := AggregateWarmFiles(warmDir, itemFilter)
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+
+	// The same games as the hand-written JSONL this test used before the generator;
+	// aggregating either must give the same stats
+	literalDir := filepath.Join(tempDir, "literal")
+	if err := os.MkdirAll(literalDir, 0755); err != nil {
+		t.Fatalf("Failed to create literal directory: %v", err)
+	}
 	file1Data := `{"matchId":"NA1_1","gameVersion":"15.24.1","gameDuration":1800,"gameCreation":1700000000000,"puuid":"p1","championId":103,"championName":"Ahri","teamPosition":"MIDDLE","win":true,"item0":3089,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 {"matchId":"NA1_1","gameVersion":"15.24.1","gameDuration":1800,"gameCreation":1700000000000,"puuid":"p2","championId":238,"championName":"Zed","teamPosition":"MIDDLE","win":false,"item0":3142,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 `
-
-	// File 2: Ahri wins again
 	file2Data := `{"matchId":"NA1_2","gameVersion":"15.24.1","gameDuration":2100,"gameCreation":1700001000000,"puuid":"p1","championId":103,"championName":"Ahri","teamPosition":"MIDDLE","win":true,"item0":3089,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 {"matchId":"NA1_2","gameVersion":"15.24.1","gameDuration":2100,"gameCreation":1700001000000,"puuid":"p3","championId":7,"championName":"LeBlanc","teamPosition":"MIDDLE","win":false,"item0":3157,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 `
-
-	if err := os.WriteFile(filepath.Join(warmDir, "test_001.jsonl"), []byte(file1Data), 0644); err != nil {
+	if err := os.WriteFile(filepath.Join(literalDir, "raw_matches_test_001.jsonl"), []byte(file1Data), 0644); err != nil {
 		t.Fatalf("Failed to write file1: %v", err)
 	}
-	if err := os.WriteFile(filepath.Join(warmDir, "test_002.jsonl"), []byte(file2Data), 0644); err != nil {
+	if err := os.WriteFile(filepath.Join(literalDir, "raw_matches_test_002.jsonl"), []byte(file2Data), 0644); err != nil {
 		t.Fatalf("Failed to write file2: %v", err)
 	}
-
-	itemFilter := func(itemID int) bool { return itemID >= 3000 }
-
-	agg, err := AggregateWarmFiles(warmDir, itemFilter)
+	literal, err := AggregateWarmFiles(literalDir, itemFilter)
 	if err != nil {
-		t.Fatalf("AggregateWarmFiles failed: %v", err)
+		t.Fatalf("AggregateWarmFiles (literal) failed: %v", err)
+	}
+	if !reflect.DeepEqual(agg.ChampionStats, literal.ChampionStats) {
+		t.Errorf("champion stats differ from the literal fixture:\ngenerated %v\nliteral   %v", agg.ChampionStats, literal.ChampionStats)
+	}
+	if !reflect.DeepEqual(agg.ItemStats, literal.ItemStats) {
+		t.Errorf("item stats differ from the literal fixture:\ngenerated %v\nliteral   %v", agg.ItemStats, literal.ItemStats)
+	}
+	if !reflect.DeepEqual(agg.MatchupStats, literal.MatchupStats) {
+		t.Errorf("matchup stats differ from the literal fixture:\ngenerated %v\nliteral   %v", agg.MatchupStats, literal.MatchupStats)
 	}
 
 	// Ahri MID: 2 matches (from 2 files), 2 wins
@@ -344,6 +374,15 @@ func TestAggregateWarmFiles_MultipleFiles(t *testing.T) {
 	if agg.FilesProcessed != 2 {
 		t.Errorf("FilesProcessed: got %d, want 2", agg.FilesProcessed)
 	}
+	if agg.TotalRecords != 4 {
+		t.Errorf("TotalRecords: got %d, want 4", agg.TotalRecords)
+	}
+	for _, enemy := range []int{238, 7} {
+		key := MatchupStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", EnemyChampionID: enemy}
+		if m := agg.MatchupStats[key]; m == nil || m.Matches != 1 || m.Wins != 1 {
+			t.Errorf("Ahri vs %d matchup: got %+v, want 1 win in 1 match", enemy, m)
+		}
+	}
 }
 
 // Test 3.1 continued: Empty warm directory
@@ -382,7 +421,7 @@ func TestAggregateWarmFiles_SkipsEmptyPosition(t *testing.T) {
 {"matchId":"NA1_1","gameVersion":"15.24.1","gameDuration":1800,"gameCreation":1700000000000,"puuid":"p2","championId":238,"championName":"Zed","teamPosition":"MIDDLE","win":false,"item0":3142,"item1":0,"item2":0,"item3":0,"item4":0,"item5":0}
 `
 
-	jsonlPath := filepath.Join(warmDir, "test_001.jsonl")
+	jsonlPath := filepath.Join(warmDir, "raw_matches_test_001.jsonl")
 	if err := os.WriteFile(jsonlPath, []byte(sampleData), 0644); err != nil {
 		t.Fatalf("Failed to write sample JSONL: %v", err)
 	}
@@ -427,10 +466,10 @@ func TestArchiveWarmToCold_Basic(t *testing.T) {
 	file1Content := "test data line 1\ntest data line 2\n"
 	file2Content := "another file content\n"
 
-	if err := os.WriteFile(filepath.Join(warmDir, "test_001.jsonl"), []byte(file1Content), 0644); err != nil {
+	if err := os.WriteFile(filepath.Join(warmDir, "raw_matches_test_001.jsonl"), []byte(file1Content), 0644); err != nil {
 		t.Fatalf("Failed to write file1: %v", err)
 	}
-	if err := os.WriteFile(filepath.Join(warmDir, "test_002.jsonl"), []byte(file2Content), 0644); err != nil {
+	if err := os.WriteFile(filepath.Join(warmDir, "raw_matches_test_002.jsonl"), []byte(file2Content), 0644); err != nil {
 		t.Fatalf("Failed to write file2: %v", err)
 	}
 
@@ -512,7 +551,7 @@ func TestArchiveWarmToCold_DataIntegrity(t *testing.T) {
 		content += `{"matchId":"NA1_` + string(rune('0'+i%10)) + `","gameVersion":"15.24.1","win":true}` + "\n"
 	}
 
-	if err := os.WriteFile(filepath.Join(warmDir, "large_001.jsonl"), []byte(content), 0644); err != nil {
+	if err := os.WriteFile(filepath.Join(warmDir, "raw_matches_large_001.jsonl"), []byte(content), 0644); err != nil {
 		t.Fatalf("Failed to write large file: %v", err)
 	}
 
@@ -523,7 +562,7 @@ func TestArchiveWarmToCold_DataIntegrity(t *testing.T) {
 	}
 
 	// Verify decompressed content matches original
-	decompressed, err := readGzipFile(filepath.Join(coldDir, "large_001.jsonl.gz"))
+	decompressed, err := readGzipFile(filepath.Join(coldDir, "raw_matches_large_001.jsonl.gz"))
 	if err != nil {
 		t.Fatalf("Failed to read gzip file: %v", err)
 	}
@@ -547,7 +586,7 @@ func TestArchiveWarmToCold_OnlyJsonl(t *testing.T) {
 	}
 
 	// Create various file types
-	os.WriteFile(filepath.Join(warmDir, "test_001.jsonl"), []byte("valid jsonl"), 0644)
+	os.WriteFile(filepath.Join(warmDir, "raw_matches_test_001.jsonl"), []byte("valid jsonl"), 0644)
 	os.WriteFile(filepath.Join(warmDir, "test.txt"), []byte("text file"), 0644)
 	os.WriteFile(filepath.Join(warmDir, "test.json"), []byte("json file"), 0644)
 
@@ -569,6 +608,43 @@ func TestArchiveWarmToCold_OnlyJsonl(t *testing.T) {
 	}
 }
 
+func TestWarmFilePattern_IgnoresStrayJsonl(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	coldDir := filepath.Join(tempDir, "cold")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	match := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}` + "\n"
+	os.WriteFile(filepath.Join(warmDir, "raw_matches_2025-01-01T00-00-00.jsonl"), []byte(match), 0644)
+	// A stats export that happens to share the extension
+	strayPath := filepath.Join(warmDir, "champion_stats_export.jsonl")
+	os.WriteFile(strayPath, []byte(`{"championId":103,"matches":999}`+"\n"), 0644)
+
+	agg, err := AggregateWarmFiles(warmDir, nil)
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+	if agg.FilesProcessed != 1 || agg.TotalRecords != 1 {
+		t.Errorf("Aggregation: got %d files, %d records, want 1 and 1", agg.FilesProcessed, agg.TotalRecords)
+	}
+
+	archived, err := ArchiveWarmToCold(warmDir, coldDir)
+	if err != nil {
+		t.Fatalf("ArchiveWarmToCold failed: %v", err)
+	}
+	if archived != 1 {
+		t.Errorf("Archived count: got %d, want 1", archived)
+	}
+	if !fileExists(strayPath) {
+		t.Error("Stray .jsonl file should be left in warm")
+	}
+	if fileExists(filepath.Join(coldDir, "champion_stats_export.jsonl.gz")) {
+		t.Error("Stray .jsonl file should not be archived")
+	}
+}
+
 // Test 3.2: Archive creates cold directory if it doesn't exist
 func TestArchiveWarmToCold_CreatesColdDir(t *testing.T) {
 	tempDir := t.TempDir()
@@ -579,7 +655,7 @@ func TestArchiveWarmToCold_CreatesColdDir(t *testing.T) {
 		t.Fatalf("Failed to create warm directory: %v", err)
 	}
 
-	os.WriteFile(filepath.Join(warmDir, "test_001.jsonl"), []byte("content"), 0644)
+	os.WriteFile(filepath.Join(warmDir, "raw_matches_test_001.jsonl"), []byte("content"), 0644)
 
 	archived, err := ArchiveWarmToCold(warmDir, coldDir)
 	if err != nil {
@@ -596,7 +672,7 @@ func TestArchiveWarmToCold_CreatesColdDir(t *testing.T) {
 	}
 
 	// Verify file exists in cold
-	if !fileExists(filepath.Join(coldDir, "test_001.jsonl.gz")) {
+	if !fileExists(filepath.Join(coldDir, "raw_matches_test_001.jsonl.gz")) {
 		t.Errorf("Archived file should exist in cold directory")
 	}
 }
@@ -628,3 +704,1164 @@ func readGzipFile(path string) (string, error) {
 
 	return string(content), nil
 }
+
+// Test 3.2: Cold re-aggregation reads both gzip and zstd archives
+func TestAggregateColdFiles_MixedCompression(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	coldDir := filepath.Join(tempDir, "cold")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	itemFilter := func(itemID int) bool { return itemID >= 3000 }
+
+	// Archive one file with each compressor
+	for i, c := range []storage.Compressor{storage.GzipCompressor{}, storage.ZstdCompressor{}} {
+		record := fmt.Sprintf(`{"matchId":"NA1_%d","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}`, i) + "\n"
+		name := filepath.Join(warmDir, "raw_matches_cold_00"+string(rune('1'+i))+".jsonl")
+		if err := os.WriteFile(name, []byte(record), 0644); err != nil {
+			t.Fatalf("Failed to write warm file: %v", err)
+		}
+		if _, err := ArchiveWarmToColdWith(warmDir, coldDir, c); err != nil {
+			t.Fatalf("ArchiveWarmToColdWith failed: %v", err)
+		}
+	}
+
+	agg, err := AggregateColdFiles(coldDir, itemFilter)
+	if err != nil {
+		t.Fatalf("AggregateColdFiles failed: %v", err)
+	}
+
+	if agg.FilesProcessed != 2 {
+		t.Errorf("FilesProcessed: got %d, want 2", agg.FilesProcessed)
+	}
+	key := ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE"}
+	if stats := agg.ChampionStats[key]; stats == nil || stats.Matches != 2 {
+		t.Errorf("Ahri MIDDLE matches: got %+v, want 2", stats)
+	}
+}
+
+// Test 3.1: Final inventories are bucketed by completed item count, and MinCompletedItems
+// leaves thin inventories out of the item passes
+func TestAggregateReader_ItemCompleteness(t *testing.T) {
+	// Full build, two items (early surrender), and nothing completed
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true,"item0":3089,"item1":3157,"item2":3135,"item3":3020,"item4":3165,"item5":3116,"buildOrder":[3089,3157]}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":238,"teamPosition":"MIDDLE","win":false,"item0":3142,"item1":6692,"buildOrder":[3142]}
+{"matchId":"NA1_2","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":false,"item0":1056}
+`
+	itemFilter := func(itemID int) bool { return itemID >= 3000 }
+
+	agg, err := aggregateReader(strings.NewReader(sampleData), itemFilter, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+	wantDist := map[int]int{6: 1, 2: 1, 0: 1}
+	if len(agg.ItemCompleteness) != len(wantDist) {
+		t.Errorf("ItemCompleteness = %v, want %v", agg.ItemCompleteness, wantDist)
+	}
+	for n, want := range wantDist {
+		if agg.ItemCompleteness[n] != want {
+			t.Errorf("ItemCompleteness[%d] = %d, want %d", n, agg.ItemCompleteness[n], want)
+		}
+	}
+	zedItem := ItemStatsKey{Patch: "15.24", ChampionID: 238, TeamPosition: "MIDDLE", ItemID: 3142}
+	if agg.ItemStats[zedItem] == nil || agg.LowItemRecords != 0 {
+		t.Errorf("default options should count every participant's items")
+	}
+
+	opts := DefaultAggregateOptions()
+	opts.MinCompletedItems = 3
+	agg, err = aggregateReader(strings.NewReader(sampleData), itemFilter, opts)
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+	if agg.ItemStats[zedItem] != nil {
+		t.Error("participant with 2 completed items should be left out of item stats")
+	}
+	zedSlot := ItemSlotStatsKey{Patch: "15.24", ChampionID: 238, TeamPosition: "MIDDLE", ItemID: 3142, BuildSlot: 1}
+	if agg.ItemSlotStats[zedSlot] != nil {
+		t.Error("participant with 2 completed items should be left out of item slot stats")
+	}
+	if len(agg.ItemStats) != 6 || agg.LowItemRecords != 2 {
+		t.Errorf("got %d item stats and %d low-item records, want 6 and 2", len(agg.ItemStats), agg.LowItemRecords)
+	}
+	// Champion stats and matchups still count everyone
+	if len(agg.MatchupStats) != 2 || agg.ItemCompleteness[2] != 1 {
+		t.Errorf("filter should not touch matchups or the distribution: %d matchups, %v", len(agg.MatchupStats), agg.ItemCompleteness)
+	}
+}
+
+func TestAggregateReader_SpellPairsIgnoreSlotOrder(t *testing.T) {
+	// Flash on D in one game and on F in the other; the last record predates spells
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true,"summoner1Id":4,"summoner2Id":14}
+{"matchId":"NA1_2","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":false,"summoner1Id":14,"summoner2Id":4}
+{"matchId":"NA1_3","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}
+`
+	agg, err := aggregateReader(strings.NewReader(sampleData), func(int) bool { return true }, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+
+	key := SpellPairStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", Spell1ID: 4, Spell2ID: 14}
+	if len(agg.SpellPairStats) != 1 || agg.SpellPairStats[key] == nil {
+		t.Fatalf("SpellPairStats = %v, want only %+v", agg.SpellPairStats, key)
+	}
+	if got := agg.SpellPairStats[key]; got.Wins != 1 || got.Matches != 2 {
+		t.Errorf("Flash+Ignite = %+v, want 1 win in 2 matches", got)
+	}
+}
+
+func TestAggregateReader_ARAMKeysSeparateFromRift(t *testing.T) {
+	// Ahri in a ranked game and in an ARAM game on the same patch
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","queueId":420,"championId":103,"teamPosition":"MIDDLE","win":true,"item0":3089}
+{"matchId":"NA1_1","gameVersion":"15.24.1","queueId":420,"championId":238,"teamPosition":"MIDDLE","win":false,"item0":3142}
+{"matchId":"NA1_2","gameVersion":"15.24.1","queueId":450,"championId":103,"teamPosition":"","win":false,"item0":3089,"item1":3165}
+{"matchId":"NA1_2","gameVersion":"15.24.1","queueId":450,"championId":238,"teamPosition":"","win":true,"item0":3142}
+`
+	itemFilter := func(itemID int) bool { return itemID >= 3000 }
+
+	agg, err := aggregateReader(strings.NewReader(sampleData), itemFilter, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+
+	rift := agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE"}]
+	aram := agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, GameMode: GameModeARAM}]
+	if rift == nil || rift.Matches != 1 || rift.Wins != 1 {
+		t.Errorf("rift stats = %+v, want 1 win in 1 match", rift)
+	}
+	if aram == nil || aram.Matches != 1 || aram.Wins != 0 {
+		t.Errorf("ARAM stats = %+v, want 0 wins in 1 match", aram)
+	}
+	if len(agg.ChampionStats) != 4 {
+		t.Errorf("got %d champion stats, want 4 (2 rift + 2 ARAM)", len(agg.ChampionStats))
+	}
+
+	aramItem := agg.ItemStats[ItemStatsKey{Patch: "15.24", ChampionID: 103, GameMode: GameModeARAM, ItemID: 3165}]
+	if aramItem == nil || aramItem.Matches != 1 {
+		t.Errorf("ARAM item stats = %+v, want 1 match", aramItem)
+	}
+	if agg.ItemStats[ItemStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", ItemID: 3165}] != nil {
+		t.Error("ARAM items leaked into rift item stats")
+	}
+
+	// Only the ranked game produces a lane matchup
+	if len(agg.MatchupStats) != 2 {
+		t.Errorf("got %d matchup stats, want 2", len(agg.MatchupStats))
+	}
+	if agg.PatchRecords["15.24"] != 4 {
+		t.Errorf("PatchRecords = %v, want 4 records on 15.24", agg.PatchRecords)
+	}
+}
+
+func TestAggregateWarmFiles_KeepsRecordsBeforeTooLongLine(t *testing.T) {
+	warmDir := t.TempDir()
+
+	// Good line, a line past the scanner's 1MB buffer, good line
+	tooLong := `{"matchId":"NA1_2","note":"` + strings.Repeat("x", 1024*1024) + `"}`
+	partial := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}
+` + tooLong + `
+{"matchId":"NA1_3","gameVersion":"15.24.1","championId":238,"teamPosition":"MIDDLE","win":true}
+`
+	clean := `{"matchId":"NA1_4","gameVersion":"15.24.1","championId":86,"teamPosition":"TOP","win":false}
+`
+	if err := os.WriteFile(filepath.Join(warmDir, "raw_matches_a_001.jsonl"), []byte(partial), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(warmDir, "raw_matches_b_001.jsonl"), []byte(clean), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	agg, err := AggregateWarmFiles(warmDir, func(int) bool { return true })
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+
+	if agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE"}] == nil {
+		t.Error("record before the too-long line was dropped")
+	}
+	if agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 86, TeamPosition: "TOP"}] == nil {
+		t.Error("records from the clean file were dropped")
+	}
+	// bufio.Scanner cannot resume after ErrTooLong, so the rest of that file is lost
+	if agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 238, TeamPosition: "MIDDLE"}] != nil {
+		t.Error("did not expect records after the too-long line")
+	}
+	if agg.PartialFiles != 1 || agg.FilesProcessed != 2 || agg.TotalRecords != 2 {
+		t.Errorf("PartialFiles=%d FilesProcessed=%d TotalRecords=%d, want 1, 2, 2", agg.PartialFiles, agg.FilesProcessed, agg.TotalRecords)
+	}
+}
+
+// Test 3.1: Duplicate items are counted once in both item passes
+func TestAggregateWarmFiles_DuplicateItems(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	// Rabadon appears twice in the final inventory and twice in buildOrder,
+	// with a component (1058) in between that the filter drops
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true,"item0":3089,"item1":3089,"item2":3157,"item3":0,"item4":0,"item5":0,"buildOrder":[3089,1058,3089,3157]}
+`
+	if err := os.WriteFile(filepath.Join(warmDir, "raw_matches_dup_001.jsonl"), []byte(sampleData), 0644); err != nil {
+		t.Fatalf("Failed to write sample JSONL: %v", err)
+	}
+
+	itemFilter := func(itemID int) bool {
+		return itemID >= 3000
+	}
+
+	agg, err := AggregateWarmFiles(warmDir, itemFilter)
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+
+	// Final inventory: duplicate counted once
+	rabadonKey := ItemStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", ItemID: 3089}
+	if stats := agg.ItemStats[rabadonKey]; stats == nil || stats.Matches != 1 {
+		t.Errorf("Rabadon item matches: got %+v, want 1", stats)
+	}
+
+	// Build order: duplicate skipped without consuming a slot, so Zhonya is slot 2
+	slotKey := func(itemID, slot int) ItemSlotStatsKey {
+		return ItemSlotStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", ItemID: itemID, BuildSlot: slot}
+	}
+	if stats := agg.ItemSlotStats[slotKey(3089, 1)]; stats == nil || stats.Matches != 1 {
+		t.Errorf("Rabadon slot 1: got %+v, want 1 match", stats)
+	}
+	if stats := agg.ItemSlotStats[slotKey(3157, 2)]; stats == nil || stats.Matches != 1 {
+		t.Errorf("Zhonya slot 2: got %+v, want 1 match", stats)
+	}
+	if len(agg.ItemSlotStats) != 2 {
+		t.Errorf("ItemSlotStats entries: got %d, want 2", len(agg.ItemSlotStats))
+	}
+}
+
+// Test 3.1: Per-file timings are recorded and sum to roughly the total
+func TestAggregateWarmFiles_RecordsTimings(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	// Each file holds its own match, so cross-file dedup skips none of them
+	for i, name := range []string{"raw_matches_t_001.jsonl", "raw_matches_t_002.jsonl", "raw_matches_t_003.jsonl"} {
+		record := fmt.Sprintf(`{"matchId":"NA1_%d","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}`+"\n", i+1)
+		if err := os.WriteFile(filepath.Join(warmDir, name), []byte(strings.Repeat(record, 500)), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// One worker, so file timings don't overlap and must add up to the total
+	opts := DefaultAggregateOptions()
+	opts.Workers = 1
+	agg, err := AggregateWarmFilesWithOptions(warmDir, func(itemID int) bool { return itemID >= 3000 }, opts)
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+
+	if agg.TotalDuration <= 0 {
+		t.Fatalf("TotalDuration: got %v, want > 0", agg.TotalDuration)
+	}
+	if len(agg.FileTimings) != 3 {
+		t.Fatalf("FileTimings: got %d, want 3", len(agg.FileTimings))
+	}
+	if agg.DuplicateMatches != 0 {
+		t.Errorf("DuplicateMatches: got %d, want 0", agg.DuplicateMatches)
+	}
+
+	var sum time.Duration
+	for _, ft := range agg.FileTimings {
+		if ft.Records != 500 {
+			t.Errorf("%s records: got %d, want 500", ft.Path, ft.Records)
+		}
+		sum += ft.Duration
+	}
+	if sum > agg.TotalDuration {
+		t.Errorf("per-file sum %v exceeds total %v", sum, agg.TotalDuration)
+	}
+	if sum < agg.TotalDuration/2 {
+		t.Errorf("per-file sum %v is far below total %v", sum, agg.TotalDuration)
+	}
+	if agg.RecordsPerSecond() <= 0 {
+		t.Errorf("RecordsPerSecond: got %v, want > 0", agg.RecordsPerSecond())
+	}
+}
+
+func TestReconcileWarmWithCold_RemovesArchivedDuplicate(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	coldDir := filepath.Join(tempDir, "cold")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	content := `{"matchId":"NA1_1","gameVersion":"15.24.1","win":true}` + "\n"
+	warmPath := filepath.Join(warmDir, "raw_matches_crash_001.jsonl")
+	partialPath := filepath.Join(warmDir, "raw_matches_crash_002.jsonl")
+	os.WriteFile(warmPath, []byte(content), 0644)
+
+	// Simulate a crash after compressing but before removing the warm original
+	if _, err := ArchiveWarmToCold(warmDir, coldDir); err != nil {
+		t.Fatalf("ArchiveWarmToCold failed: %v", err)
+	}
+	os.WriteFile(warmPath, []byte(content), 0644)
+
+	// And a crash partway through writing the cold copy
+	os.WriteFile(partialPath, []byte(content), 0644)
+	os.WriteFile(filepath.Join(coldDir, "raw_matches_crash_002.jsonl.gz"), []byte{0x1f, 0x8b}, 0644)
+
+	removed, err := ReconcileWarmWithCold(warmDir, coldDir)
+	if err != nil {
+		t.Fatalf("ReconcileWarmWithCold failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Removed count: got %d, want 1", removed)
+	}
+
+	if fileExists(warmPath) {
+		t.Error("Archived warm duplicate should be removed")
+	}
+	decompressed, err := readGzipFile(filepath.Join(coldDir, "raw_matches_crash_001.jsonl.gz"))
+	if err != nil || decompressed != content {
+		t.Errorf("Cold copy should be intact: %q, %v", decompressed, err)
+	}
+
+	if !fileExists(partialPath) {
+		t.Error("Warm file with a partial cold copy must be kept")
+	}
+	if fileExists(filepath.Join(coldDir, "raw_matches_crash_002.jsonl.gz")) {
+		t.Error("Partial cold copy should be removed so it is re-archived")
+	}
+}
+
+func TestAggregateWarmFiles_DuoMatchupStats(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	// Jinx+Thresh (win) vs Caitlyn+Lux (loss) in bot lane, plus a mid matchup
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":222,"teamPosition":"BOTTOM","win":true}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":412,"teamPosition":"UTILITY","win":true}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":51,"teamPosition":"BOTTOM","win":false}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":99,"teamPosition":"UTILITY","win":false}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}
+`
+	if err := os.WriteFile(filepath.Join(warmDir, "raw_matches_test_001.jsonl"), []byte(sampleData), 0644); err != nil {
+		t.Fatalf("Failed to write sample JSONL: %v", err)
+	}
+
+	agg, err := AggregateWarmFiles(warmDir, func(itemID int) bool { return itemID >= 3000 })
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+
+	if len(agg.DuoMatchupStats) != 2 {
+		t.Fatalf("DuoMatchupStats: got %d entries, want 2: %+v", len(agg.DuoMatchupStats), agg.DuoMatchupStats)
+	}
+
+	winKey := DuoMatchupStatsKey{Patch: "15.24", ADCChampionID: 222, SupportChampionID: 412, EnemyADCID: 51, EnemySupportID: 99}
+	if s, ok := agg.DuoMatchupStats[winKey]; !ok || s.Matches != 1 || s.Wins != 1 {
+		t.Errorf("Jinx+Thresh vs Caitlyn+Lux: got %+v, want 1/1", s)
+	}
+
+	lossKey := DuoMatchupStatsKey{Patch: "15.24", ADCChampionID: 51, SupportChampionID: 99, EnemyADCID: 222, EnemySupportID: 412}
+	if s, ok := agg.DuoMatchupStats[lossKey]; !ok || s.Matches != 1 || s.Wins != 0 {
+		t.Errorf("Caitlyn+Lux vs Jinx+Thresh: got %+v, want 0/1", s)
+	}
+
+	// 1v1 bot lane matchups are still produced alongside the duo stat
+	if _, ok := agg.MatchupStats[MatchupStatsKey{Patch: "15.24", ChampionID: 222, TeamPosition: "BOTTOM", EnemyChampionID: 51}]; !ok {
+		t.Error("1v1 ADC matchup should still be recorded")
+	}
+}
+
+func TestAggregateWarmFiles_AllyPairStats(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	// Ahri and Lee Sin win together on blue; Zed and Elise lose on red
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","teamId":100,"win":true}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":64,"teamPosition":"JUNGLE","teamId":100,"win":true}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":238,"teamPosition":"MIDDLE","teamId":200,"win":false}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":60,"teamPosition":"JUNGLE","teamId":200,"win":false}
+`
+	if err := os.WriteFile(filepath.Join(warmDir, "raw_matches_test_001.jsonl"), []byte(sampleData), 0644); err != nil {
+		t.Fatalf("Failed to write sample JSONL: %v", err)
+	}
+
+	agg, err := AggregateWarmFiles(warmDir, func(itemID int) bool { return itemID >= 3000 })
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+
+	if len(agg.AllyPairStats) != 2 {
+		t.Fatalf("AllyPairStats: got %d entries, want 2: %+v", len(agg.AllyPairStats), agg.AllyPairStats)
+	}
+	// Keys store the lower champion ID first
+	if s, ok := agg.AllyPairStats[AllyPairStatsKey{Patch: "15.24", ChampionID: 64, AllyChampionID: 103}]; !ok || s.Wins != 1 || s.Matches != 1 {
+		t.Errorf("Lee Sin+Ahri: got %+v, want 1/1", s)
+	}
+	if s, ok := agg.AllyPairStats[AllyPairStatsKey{Patch: "15.24", ChampionID: 60, AllyChampionID: 238}]; !ok || s.Wins != 0 || s.Matches != 1 {
+		t.Errorf("Elise+Zed: got %+v, want 0/1", s)
+	}
+	if _, ok := agg.AllyPairStats[AllyPairStatsKey{Patch: "15.24", ChampionID: 103, AllyChampionID: 238}]; ok {
+		t.Error("opponents should not be recorded as allies")
+	}
+}
+
+func TestAggregateWarmFilesWithSlots_RaisedCap(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true,"buildOrder":[3001,3002,3003,3004,3005,3006,3007,3008]}
+`
+	if err := os.WriteFile(filepath.Join(warmDir, "raw_matches_test_001.jsonl"), []byte(sampleData), 0644); err != nil {
+		t.Fatalf("Failed to write sample JSONL: %v", err)
+	}
+	itemFilter := func(itemID int) bool { return itemID >= 3000 }
+
+	slotKey := func(itemID, slot int) ItemSlotStatsKey {
+		return ItemSlotStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", ItemID: itemID, BuildSlot: slot}
+	}
+
+	// Default cap drops items past the sixth
+	agg, err := AggregateWarmFiles(warmDir, itemFilter)
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+	if _, ok := agg.ItemSlotStats[slotKey(3007, 7)]; ok {
+		t.Error("Slot 7 should not be tracked with the default cap")
+	}
+
+	agg, err = AggregateWarmFilesWithSlots(warmDir, itemFilter, 8)
+	if err != nil {
+		t.Fatalf("AggregateWarmFilesWithSlots failed: %v", err)
+	}
+	if len(agg.ItemSlotStats) != 8 {
+		t.Errorf("ItemSlotStats: got %d entries, want 8", len(agg.ItemSlotStats))
+	}
+	for _, k := range []ItemSlotStatsKey{slotKey(3007, 7), slotKey(3008, 8)} {
+		if s, ok := agg.ItemSlotStats[k]; !ok || s.Matches != 1 {
+			t.Errorf("Expected slot %d (item %d) to be recorded, got %+v", k.BuildSlot, k.ItemID, s)
+		}
+	}
+}
+
+func TestArchiveWarmToColdByPatch_ShardsByPatch(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	coldDir := filepath.Join(tempDir, "cold")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(warmDir, "raw_matches_a_001.jsonl"), []byte(`{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}`+"\n"), 0644)
+	os.WriteFile(filepath.Join(warmDir, "raw_matches_b_001.jsonl"), []byte(`{"matchId":"NA1_2","gameVersion":"15.23.4","championId":238,"teamPosition":"MIDDLE","win":true}`+"\n"), 0644)
+	os.WriteFile(filepath.Join(warmDir, "raw_matches_c_001.jsonl"), []byte("not json\n"), 0644)
+
+	archived, err := ArchiveWarmToColdByPatch(warmDir, coldDir, storage.GzipCompressor{})
+	if err != nil {
+		t.Fatalf("ArchiveWarmToColdByPatch failed: %v", err)
+	}
+	if archived != 3 {
+		t.Errorf("Archived count: got %d, want 3", archived)
+	}
+
+	for _, path := range []string{
+		filepath.Join(coldDir, "15.24", "raw_matches_a_001.jsonl.gz"),
+		filepath.Join(coldDir, "15.23", "raw_matches_b_001.jsonl.gz"),
+		filepath.Join(coldDir, "unknown", "raw_matches_c_001.jsonl.gz"),
+	} {
+		if !fileExists(path) {
+			t.Errorf("Expected %s to exist", path)
+		}
+	}
+
+	// Re-aggregation reads the sharded layout
+	agg, err := AggregateColdFiles(coldDir, func(itemID int) bool { return itemID >= 3000 })
+	if err != nil {
+		t.Fatalf("AggregateColdFiles failed: %v", err)
+	}
+	if agg.TotalRecords != 2 {
+		t.Errorf("TotalRecords: got %d, want 2", agg.TotalRecords)
+	}
+	if _, ok := agg.ChampionStats[ChampionStatsKey{Patch: "15.23", ChampionID: 238, TeamPosition: "MIDDLE"}]; !ok {
+		t.Error("Expected stats from the 15.23 shard")
+	}
+}
+
+func TestAggregateWarmFiles_PatchTimeRanges(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(warmDir, "raw_matches_a_001.jsonl"), []byte(
+		`{"matchId":"NA1_1","gameVersion":"15.24.1","gameCreation":1700002000000,"championId":103,"teamPosition":"MIDDLE","win":true}
+{"matchId":"NA1_2","gameVersion":"15.23.2","gameCreation":1699000000000,"championId":238,"teamPosition":"MIDDLE","win":true}
+`), 0644)
+	os.WriteFile(filepath.Join(warmDir, "raw_matches_b_001.jsonl"), []byte(
+		`{"matchId":"NA1_3","gameVersion":"15.24.3","gameCreation":1700001000000,"championId":103,"teamPosition":"MIDDLE","win":false}
+{"matchId":"NA1_4","gameVersion":"15.24.3","gameCreation":1700009000000,"championId":7,"teamPosition":"MIDDLE","win":true}
+`), 0644)
+
+	agg, err := AggregateWarmFiles(warmDir, func(itemID int) bool { return itemID >= 3000 })
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+
+	want := map[string]TimeRange{
+		"15.24": {First: 1700001000000, Last: 1700009000000},
+		"15.23": {First: 1699000000000, Last: 1699000000000},
+	}
+	if len(agg.PatchTimeRanges) != len(want) {
+		t.Fatalf("PatchTimeRanges: got %d patches, want %d", len(agg.PatchTimeRanges), len(want))
+	}
+	for patch, w := range want {
+		got, ok := agg.PatchTimeRanges[patch]
+		if !ok || *got != w {
+			t.Errorf("Patch %s range: got %+v, want %+v", patch, got, w)
+		}
+	}
+}
+
+func TestAggregateReader_MatchesFileAggregation(t *testing.T) {
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","gameCreation":1700000000000,"championId":103,"teamPosition":"MIDDLE","win":true,"item0":3089,"buildOrder":[3089,3157]}
+{"matchId":"NA1_1","gameVersion":"15.24.1","gameCreation":1700000000000,"championId":238,"teamPosition":"MIDDLE","win":false,"item0":3142}
+{"matchId":"NA1_1","gameVersion":"15.24.1","gameCreation":1700000000000,"championId":222,"teamPosition":"BOTTOM","win":true}
+`
+	itemFilter := func(itemID int) bool { return itemID >= 3000 }
+
+	path := filepath.Join(t.TempDir(), "raw_matches_test_001.jsonl")
+	if err := os.WriteFile(path, []byte(sampleData), 0644); err != nil {
+		t.Fatalf("Failed to write sample JSONL: %v", err)
+	}
+	fromFile, err := aggregateFile(path, itemFilter, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatalf("aggregateFile failed: %v", err)
+	}
+
+	fromReader, err := aggregateReader(strings.NewReader(sampleData), itemFilter, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(fromFile, fromReader) {
+		t.Errorf("reader aggregation differs from file aggregation:\nfile:   %+v\nreader: %+v", fromFile, fromReader)
+	}
+	if fromReader.TotalRecords != 3 || len(fromReader.ItemSlotStats) != 2 || len(fromReader.MatchupStats) != 2 {
+		t.Errorf("unexpected reader aggregation: %+v", fromReader)
+	}
+}
+
+func TestAggregateReader_CountsMatchesWithNoWinner(t *testing.T) {
+	positions := []string{"TOP", "JUNGLE", "MIDDLE", "BOTTOM", "UTILITY"}
+
+	var sb strings.Builder
+	for i := 0; i < 10; i++ {
+		// NA1_BAD: every participant lost
+		fmt.Fprintf(&sb, `{"matchId":"NA1_BAD","gameVersion":"15.24.1","championId":%d,"teamPosition":%q,"win":false}`+"\n",
+			i+1, positions[i%5])
+		// NA1_OK: first five won
+		fmt.Fprintf(&sb, `{"matchId":"NA1_OK","gameVersion":"15.24.1","championId":%d,"teamPosition":%q,"win":%t}`+"\n",
+			i+101, positions[i%5], i < 5)
+	}
+
+	agg, err := aggregateReader(strings.NewReader(sb.String()), func(int) bool { return true }, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+
+	if agg.CorruptMatches != 1 {
+		t.Errorf("CorruptMatches = %d, want 1", agg.CorruptMatches)
+	}
+	if len(agg.MatchupStats) != 10 {
+		t.Errorf("got %d matchup stats, want 10 from the valid match only", len(agg.MatchupStats))
+	}
+	for key := range agg.MatchupStats {
+		if key.ChampionID <= 10 {
+			t.Errorf("corrupt match contributed matchup %+v", key)
+		}
+	}
+}
+
+func TestAggregateReader_LaneOpponentPairing(t *testing.T) {
+	type player struct {
+		championID int
+		win        bool
+	}
+	tests := []struct {
+		name       string
+		mid        []player
+		matchups   int
+		asymmetric int
+	}{
+		{"one winner and one loser", []player{{103, true}, {238, false}}, 2, 0},
+		{"loser listed first", []player{{238, false}, {103, true}}, 2, 0},
+		{"two winners and one loser", []player{{103, true}, {61, true}, {238, false}}, 0, 1},
+		{"two players on the winning side", []player{{103, true}, {61, true}}, 0, 1},
+		{"lone player", []player{{103, true}}, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			for _, p := range tt.mid {
+				fmt.Fprintf(&sb, `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":%d,"teamPosition":"MIDDLE","win":%t}`+"\n",
+					p.championID, p.win)
+			}
+
+			agg, err := aggregateReader(strings.NewReader(sb.String()), func(int) bool { return true }, DefaultAggregateOptions())
+			if err != nil {
+				t.Fatalf("aggregateReader failed: %v", err)
+			}
+			if len(agg.MatchupStats) != tt.matchups || agg.AsymmetricLanes != tt.asymmetric {
+				t.Fatalf("got %d matchups and %d asymmetric lanes, want %d and %d",
+					len(agg.MatchupStats), agg.AsymmetricLanes, tt.matchups, tt.asymmetric)
+			}
+			if tt.matchups == 0 {
+				return
+			}
+
+			ahri := agg.MatchupStats[MatchupStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", EnemyChampionID: 238}]
+			zed := agg.MatchupStats[MatchupStatsKey{Patch: "15.24", ChampionID: 238, TeamPosition: "MIDDLE", EnemyChampionID: 103}]
+			if ahri == nil || zed == nil || ahri.Wins != 1 || ahri.Matches != 1 || zed.Wins != 0 || zed.Matches != 1 {
+				t.Errorf("got Ahri %+v, Zed %+v; want a 1/1 win against a 0/1 loss", ahri, zed)
+			}
+		})
+	}
+}
+
+func TestAggregateReader_MatchupDurationStats(t *testing.T) {
+	// Ahri beats Zed in a 20 minute game and loses in a 40 minute game
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","gameDuration":1200,"championId":103,"teamPosition":"MIDDLE","win":true}
+{"matchId":"NA1_1","gameVersion":"15.24.1","gameDuration":1200,"championId":238,"teamPosition":"MIDDLE","win":false}
+{"matchId":"NA1_2","gameVersion":"15.24.1","gameDuration":2400,"championId":103,"teamPosition":"MIDDLE","win":false}
+{"matchId":"NA1_2","gameVersion":"15.24.1","gameDuration":2400,"championId":238,"teamPosition":"MIDDLE","win":true}
+{"matchId":"NA1_3","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}
+{"matchId":"NA1_3","gameVersion":"15.24.1","championId":238,"teamPosition":"MIDDLE","win":false}
+`
+	agg, err := aggregateReader(strings.NewReader(sampleData), func(int) bool { return true }, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+
+	ahriVsZed := MatchupStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", EnemyChampionID: 238}
+	if s := agg.MatchupStats[ahriVsZed]; s == nil || s.Matches != 3 {
+		t.Fatalf("overall matchup: got %+v, want 3 games", s)
+	}
+
+	early := agg.MatchupDurationStats[MatchupDurationStatsKey{MatchupStatsKey: ahriVsZed, DurationBucket: DurationEarly}]
+	if early == nil || early.Matches != 1 || early.Wins != 1 {
+		t.Errorf("early: got %+v, want 1/1", early)
+	}
+	late := agg.MatchupDurationStats[MatchupDurationStatsKey{MatchupStatsKey: ahriVsZed, DurationBucket: DurationLate}]
+	if late == nil || late.Matches != 1 || late.Wins != 0 {
+		t.Errorf("late: got %+v, want 0/1", late)
+	}
+	// 2 buckets x 2 perspectives; the game without a duration is not bucketed
+	if len(agg.MatchupDurationStats) != 4 {
+		t.Errorf("got %d duration stats, want 4: %+v", len(agg.MatchupDurationStats), agg.MatchupDurationStats)
+	}
+}
+
+func TestDurationBucket(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{0, ""},
+		{24*60 + 59, DurationEarly},
+		{25 * 60, DurationMid},
+		{34*60 + 59, DurationMid},
+		{35 * 60, DurationLate},
+	}
+	for _, tt := range tests {
+		if got := durationBucket(tt.seconds); got != tt.want {
+			t.Errorf("durationBucket(%d) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestAggregateWarmFiles_DetectedPatchIsDominant(t *testing.T) {
+	dominant := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":238,"teamPosition":"MIDDLE","win":false}
+{"matchId":"NA1_2","gameVersion":"15.24.2","championId":7,"teamPosition":"MIDDLE","win":true}
+`
+	minority := `{"matchId":"NA1_0","gameVersion":"15.23.1","championId":103,"teamPosition":"MIDDLE","win":true}
+{"matchId":"NA1_3","gameVersion":"15.24.1","championId":1,"teamPosition":"TOP","win":true}
+{"matchId":"NA1_0","gameVersion":"15.23.1","championId":238,"teamPosition":"MIDDLE","win":false}
+`
+	// Run both file orders; the minority patch is last in one of them
+	for _, names := range [][2]string{{"raw_matches_a.jsonl", "raw_matches_b.jsonl"}, {"raw_matches_b.jsonl", "raw_matches_a.jsonl"}} {
+		warmDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(warmDir, names[0]), []byte(dominant), 0644); err != nil {
+			t.Fatalf("Failed to write sample JSONL: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(warmDir, names[1]), []byte(minority), 0644); err != nil {
+			t.Fatalf("Failed to write sample JSONL: %v", err)
+		}
+
+		agg, err := AggregateWarmFiles(warmDir, func(int) bool { return true })
+		if err != nil {
+			t.Fatalf("AggregateWarmFiles failed: %v", err)
+		}
+		if agg.DetectedPatch != "15.24" {
+			t.Errorf("files %v: DetectedPatch = %q, want 15.24", names, agg.DetectedPatch)
+		}
+		if agg.PatchRecords["15.24"] != 4 || agg.PatchRecords["15.23"] != 2 {
+			t.Errorf("files %v: PatchRecords = %v, want 15.24:4 15.23:2", names, agg.PatchRecords)
+		}
+	}
+}
+
+func TestDominantPatch_TieGoesToNewerPatch(t *testing.T) {
+	if got := dominantPatch(map[string]int{"15.9": 5, "15.10": 5, "15.8": 2}); got != "15.10" {
+		t.Errorf("dominantPatch = %q, want 15.10", got)
+	}
+	if got := dominantPatch(nil); got != "" {
+		t.Errorf("dominantPatch(nil) = %q, want empty", got)
+	}
+}
+
+func TestAggregateReader_UnknownPositionOption(t *testing.T) {
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"","win":true,"item0":3089}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":238,"teamPosition":"","win":false}
+{"matchId":"NA1_2","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}
+`
+	allItems := func(int) bool { return true }
+	unknownKey := ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: UnknownPosition}
+
+	agg, err := aggregateReader(strings.NewReader(sampleData), allItems, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+	if _, ok := agg.ChampionStats[unknownKey]; ok {
+		t.Error("empty-position records should be skipped by default")
+	}
+	if len(agg.ChampionStats) != 1 {
+		t.Errorf("got %d champion stats by default, want 1", len(agg.ChampionStats))
+	}
+
+	opts := DefaultAggregateOptions()
+	opts.CountUnknownPosition = true
+	agg, err = aggregateReader(strings.NewReader(sampleData), allItems, opts)
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+	if s := agg.ChampionStats[unknownKey]; s == nil || s.Matches != 1 || s.Wins != 1 {
+		t.Errorf("unknown bucket: got %+v, want 1/1", s)
+	}
+	if len(agg.ChampionStats) != 3 {
+		t.Errorf("got %d champion stats, want 3", len(agg.ChampionStats))
+	}
+	if len(agg.MatchupStats) != 0 || len(agg.ItemStats) != 0 {
+		t.Errorf("unknown-position records leaked into matchups/items: %d matchups, %d items",
+			len(agg.MatchupStats), len(agg.ItemStats))
+	}
+}
+
+func TestArchiveWarmToColdCounted_LinesMatchAggregatedRecords(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	coldDir := filepath.Join(tempDir, "cold")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatalf("Failed to create warm directory: %v", err)
+	}
+
+	file1 := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":238,"teamPosition":"MIDDLE","win":false}
+`
+	// Second file has no trailing newline
+	file2 := `{"matchId":"NA1_2","gameVersion":"15.24.1","championId":86,"teamPosition":"TOP","win":true}`
+	os.WriteFile(filepath.Join(warmDir, "raw_matches_a.jsonl"), []byte(file1), 0644)
+	os.WriteFile(filepath.Join(warmDir, "raw_matches_b.jsonl"), []byte(file2), 0644)
+
+	agg, err := AggregateWarmFiles(warmDir, func(int) bool { return true })
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+
+	result, err := ArchiveWarmToColdCounted(warmDir, coldDir, storage.DefaultCompressor)
+	if err != nil {
+		t.Fatalf("ArchiveWarmToColdCounted failed: %v", err)
+	}
+
+	if result.Files != 2 {
+		t.Errorf("Files = %d, want 2", result.Files)
+	}
+	if result.Lines != agg.TotalRecords || result.Lines != 3 {
+		t.Errorf("archived %d lines, aggregated %d records, want both 3", result.Lines, agg.TotalRecords)
+	}
+}
+
+func TestAggregateReader_DedupsRepeatedParticipant(t *testing.T) {
+	// Ahri's row was written twice by a retry
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","puuid":"p1","championId":103,"teamPosition":"MIDDLE","win":true,"item0":3089}
+{"matchId":"NA1_1","gameVersion":"15.24.1","puuid":"p1","championId":103,"teamPosition":"MIDDLE","win":true,"item0":3089}
+{"matchId":"NA1_1","gameVersion":"15.24.1","puuid":"p2","championId":238,"teamPosition":"MIDDLE","win":false}
+{"matchId":"NA1_2","gameVersion":"15.24.1","puuid":"p1","championId":103,"teamPosition":"MIDDLE","win":false}
+`
+	agg, err := aggregateReader(strings.NewReader(sampleData), func(int) bool { return true }, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+
+	ahri := agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE"}]
+	if ahri == nil || ahri.Matches != 2 || ahri.Wins != 1 {
+		t.Errorf("Ahri stats: got %+v, want 2 matches 1 win", ahri)
+	}
+	item := agg.ItemStats[ItemStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", ItemID: 3089}]
+	if item == nil || item.Matches != 1 {
+		t.Errorf("item stats: got %+v, want 1 match", item)
+	}
+	if agg.DuplicateRecords != 1 {
+		t.Errorf("DuplicateRecords = %d, want 1", agg.DuplicateRecords)
+	}
+	matchup := agg.MatchupStats[MatchupStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", EnemyChampionID: 238}]
+	if matchup == nil || matchup.Matches != 1 {
+		t.Errorf("matchup should survive dedup: got %+v", matchup)
+	}
+}
+
+func TestAggregateFiles_ProcessesInSortedOrder(t *testing.T) {
+	dir := t.TempDir()
+	record := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}` + "\n"
+
+	var files []string
+	for _, name := range []string{"raw_matches_c.jsonl", "raw_matches_a.jsonl", "raw_matches_b.jsonl"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(record), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		files = append(files, path)
+	}
+
+	var order []string
+	opts := DefaultAggregateOptions()
+	opts.Progress = func(path string, done, total int) {
+		if total != 3 || done != len(order)+1 {
+			t.Errorf("progress %d/%d out of step after %d files", done, total, len(order))
+		}
+		order = append(order, filepath.Base(path))
+	}
+
+	aggregateFiles(newAggData(), files, func(int) bool { return true }, opts)
+
+	if strings.Join(order, ",") != "raw_matches_a.jsonl,raw_matches_b.jsonl,raw_matches_c.jsonl" {
+		t.Errorf("processed %v, want sorted order", order)
+	}
+	if filepath.Base(files[0]) != "raw_matches_c.jsonl" {
+		t.Error("caller's file slice should not be reordered")
+	}
+}
+
+// writeSyntheticWarmFiles generates fileCount warm files of random full matches,
+// alternating patches between files
+func writeSyntheticWarmFiles(tb testing.TB, dir string, fileCount, matchesPerFile int) []string {
+	tb.Helper()
+	var files []string
+	for f := 0; f < fileCount; f++ {
+		files = append(files, GenerateWarmFile(tb, dir, matchesPerFile,
+			WithPatch([]string{"15.23.1", "15.24.1"}[f%2]),
+			WithFirstMatch(f*matchesPerFile),
+			WithBuildOrderRate(0.2),
+			WithSeed(int64(f)),
+		))
+	}
+	return files
+}
+
+func TestAggregateFiles_ParallelMatchesSequential(t *testing.T) {
+	files := writeSyntheticWarmFiles(t, t.TempDir(), 40, 20)
+	itemFilter := func(itemID int) bool { return itemID >= 3000 }
+
+	run := func(workers int) *AggData {
+		opts := DefaultAggregateOptions()
+		opts.Workers = workers
+		agg := newAggData()
+		aggregateFiles(agg, files, itemFilter, opts)
+		// Timings and push IDs legitimately differ between runs
+		agg.PushID, agg.TotalDuration = "", 0
+		for i := range agg.FileTimings {
+			agg.FileTimings[i].Duration = 0
+		}
+		return agg
+	}
+
+	sequential := run(1)
+	if sequential.FilesProcessed != 40 || sequential.TotalRecords != 40*20*10 {
+		t.Fatalf("sequential run got %d files, %d records", sequential.FilesProcessed, sequential.TotalRecords)
+	}
+	for _, workers := range []int{2, 8, 64} {
+		if parallel := run(workers); !reflect.DeepEqual(parallel, sequential) {
+			t.Errorf("%d workers: aggregation differs from the sequential run", workers)
+		}
+	}
+}
+
+func BenchmarkAggregateFiles(b *testing.B) {
+	files := writeSyntheticWarmFiles(b, b.TempDir(), 200, 20)
+	itemFilter := func(itemID int) bool { return itemID >= 3000 }
+
+	for _, workers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			opts := DefaultAggregateOptions()
+			opts.Workers = workers
+			for i := 0; i < b.N; i++ {
+				aggregateFiles(newAggData(), files, itemFilter, opts)
+			}
+		})
+	}
+}
+
+func TestAggregateColdFiles_GzipMatchesUncompressed(t *testing.T) {
+	plainDir, mixedDir := t.TempDir(), t.TempDir()
+	itemFilter := func(itemID int) bool { return itemID >= 3000 }
+
+	var files []string
+	for f := 0; f < 4; f++ {
+		files = append(files, GenerateWarmFile(t, plainDir, 25, WithFirstMatch(f*25), WithSeed(int64(f))))
+	}
+
+	// Gzip every other file; the rest are copied over uncompressed
+	for i, path := range files {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst := filepath.Join(mixedDir, filepath.Base(path))
+		if i%2 == 0 {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			gz.Write(raw)
+			gz.Close()
+			raw, dst = buf.Bytes(), dst+".gz"
+		}
+		if err := os.WriteFile(dst, raw, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plain, err := AggregateColdFiles(plainDir, itemFilter)
+	if err != nil {
+		t.Fatalf("AggregateColdFiles(plain): %v", err)
+	}
+	mixed, err := AggregateColdFiles(mixedDir, itemFilter)
+	if err != nil {
+		t.Fatalf("AggregateColdFiles(mixed): %v", err)
+	}
+
+	if mixed.FilesProcessed != 4 || mixed.TotalRecords != plain.TotalRecords {
+		t.Errorf("mixed read %d files, %d records; want 4 files, %d records", mixed.FilesProcessed, mixed.TotalRecords, plain.TotalRecords)
+	}
+	if !reflect.DeepEqual(mixed.ChampionStats, plain.ChampionStats) {
+		t.Error("champion stats differ between gzipped and uncompressed input")
+	}
+	if !reflect.DeepEqual(mixed.ItemStats, plain.ItemStats) {
+		t.Error("item stats differ between gzipped and uncompressed input")
+	}
+	if !reflect.DeepEqual(mixed.MatchupStats, plain.MatchupStats) {
+		t.Error("matchup stats differ between gzipped and uncompressed input")
+	}
+}
+
+func TestAggregateReader_TracksVersionsPerPatch(t *testing.T) {
+	input := `{"matchId":"NA1_1","gameVersion":"15.24.1","puuid":"a","championId":103,"teamPosition":"MIDDLE","win":true}
+{"matchId":"NA1_2","gameVersion":"15.24.1","puuid":"b","championId":103,"teamPosition":"MIDDLE","win":true}
+{"matchId":"NA1_3","gameVersion":"15.24.999-hotfix","puuid":"c","championId":103,"teamPosition":"MIDDLE","win":false}
+{"matchId":"NA1_4","gameVersion":"15.23.2","puuid":"d","championId":103,"teamPosition":"MIDDLE","win":false}
+`
+	agg, err := aggregateReader(strings.NewReader(input), func(int) bool { return true }, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]int{
+		"15.24": {"15.24.1": 2, "15.24.999-hotfix": 1},
+		"15.23": {"15.23.2": 1},
+	}
+	if !reflect.DeepEqual(agg.PatchVersions, want) {
+		t.Errorf("PatchVersions = %v, want %v", agg.PatchVersions, want)
+	}
+
+	merged := agg.MergedPatchVersions()
+	if len(merged) != 1 || !reflect.DeepEqual(merged["15.24"], []string{"15.24.1", "15.24.999-hotfix"}) {
+		t.Errorf("MergedPatchVersions = %v, want only 15.24 with both versions", merged)
+	}
+}
+
+func TestFilterByMinMatches(t *testing.T) {
+	agg := newAggData()
+	for matches := 1; matches <= 8; matches++ {
+		agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: matches, TeamPosition: "TOP"}] = &ChampionStats{Matches: matches}
+		agg.ItemStats[ItemStatsKey{Patch: "15.24", ChampionID: matches, TeamPosition: "TOP", ItemID: 3078}] = &ItemStats{Matches: matches}
+		agg.ItemSlotStats[ItemSlotStatsKey{Patch: "15.24", ChampionID: matches, TeamPosition: "TOP", ItemID: 3078, BuildSlot: 1}] = &ItemSlotStats{Matches: matches}
+		agg.MatchupStats[MatchupStatsKey{Patch: "15.24", ChampionID: matches, TeamPosition: "TOP", EnemyChampionID: 86}] = &MatchupStats{Matches: matches}
+	}
+
+	// 1-4 matches drop in each of the four maps; 5-8 stay
+	if dropped := agg.FilterByMinMatches(5); dropped != 16 {
+		t.Errorf("dropped %d entries, want 16", dropped)
+	}
+	counts := []int{len(agg.ChampionStats), len(agg.ItemStats), len(agg.ItemSlotStats), len(agg.MatchupStats)}
+	if !reflect.DeepEqual(counts, []int{4, 4, 4, 4}) {
+		t.Errorf("entries left per map = %v, want 4 each", counts)
+	}
+	for k, v := range agg.ChampionStats {
+		if v.Matches < 5 {
+			t.Errorf("%+v kept with %d matches", k, v.Matches)
+		}
+	}
+	if _, ok := agg.MatchupStats[MatchupStatsKey{Patch: "15.24", ChampionID: 5, TeamPosition: "TOP", EnemyChampionID: 86}]; !ok {
+		t.Error("matchup with exactly 5 matches should be kept")
+	}
+}
+
+func TestAggregateWarmFiles_DedupsMatchAcrossFiles(t *testing.T) {
+	warmDir := t.TempDir()
+
+	// NA1_0 lands in both files, as if a rotation split it or it was fetched twice;
+	// the second file also holds a match of its own
+	GenerateWarmFile(t, warmDir, 1, WithFileName("raw_matches_001.jsonl"),
+		WithRoles("MIDDLE"), WithChampions(103, 238), WithBlueWinRate(1))
+	GenerateWarmFile(t, warmDir, 2, WithFileName("raw_matches_002.jsonl"),
+		WithRoles("MIDDLE"), WithChampions(103, 238), WithBlueWinRate(1))
+
+	agg, err := AggregateWarmFiles(warmDir, func(int) bool { return true })
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+
+	ahri := agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE"}]
+	if ahri == nil || ahri.Matches != 2 || ahri.Wins != 2 {
+		t.Errorf("Ahri MIDDLE: got %+v, want 2 matches (NA1_0 once, NA1_1)", ahri)
+	}
+	matchup := agg.MatchupStats[MatchupStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", EnemyChampionID: 238}]
+	if matchup == nil || matchup.Matches != 2 {
+		t.Errorf("Ahri vs Zed: got %+v, want 2 matches", matchup)
+	}
+	// The duplicate's rows are still records read, so archived line counts match
+	if agg.DuplicateMatches != 1 || agg.TotalRecords != 6 || agg.FilesProcessed != 2 {
+		t.Errorf("got %d duplicate matches, %d records, %d files; want 1, 6, 2",
+			agg.DuplicateMatches, agg.TotalRecords, agg.FilesProcessed)
+	}
+}
+
+func TestAggregateWarmFiles_SkipsRemakes(t *testing.T) {
+	warmDir := t.TempDir()
+	opts := []WarmFileOption{WithRoles("MIDDLE"), WithChampions(103, 238), WithBlueWinRate(1), WithItemPool(3089), WithItemsPerPlayer(1)}
+
+	// Three 30-minute games and two 3-minute remakes with the same champions
+	GenerateWarmFile(t, warmDir, 3, append(opts, WithFileName("raw_matches_full.jsonl"), WithGameDuration(1800))...)
+	GenerateWarmFile(t, warmDir, 2, append(opts, WithFileName("raw_matches_remake.jsonl"), WithGameDuration(180), WithFirstMatch(100))...)
+
+	agg, err := AggregateWarmFiles(warmDir, func(itemID int) bool { return itemID >= 3000 })
+	if err != nil {
+		t.Fatalf("AggregateWarmFiles failed: %v", err)
+	}
+
+	if ahri := agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE"}]; ahri == nil || ahri.Matches != 3 {
+		t.Errorf("Ahri champion stats: got %+v, want 3 matches", ahri)
+	}
+	if item := agg.ItemStats[ItemStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", ItemID: 3089}]; item == nil || item.Matches != 3 {
+		t.Errorf("Ahri Rabadon's stats: got %+v, want 3 matches", item)
+	}
+	if m := agg.MatchupStats[MatchupStatsKey{Patch: "15.24", ChampionID: 238, TeamPosition: "MIDDLE", EnemyChampionID: 103}]; m == nil || m.Matches != 3 || m.Wins != 0 {
+		t.Errorf("Zed vs Ahri: got %+v, want 0 wins in 3 matches", m)
+	}
+	if agg.ShortGameRecords != 4 {
+		t.Errorf("ShortGameRecords: got %d, want 4", agg.ShortGameRecords)
+	}
+
+	// 0 keeps the remakes
+	keepAll := DefaultAggregateOptions()
+	keepAll.MinGameDurationSeconds = 0
+	agg, err = AggregateWarmFilesWithOptions(warmDir, func(itemID int) bool { return itemID >= 3000 }, keepAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ahri := agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE"}]; ahri == nil || ahri.Matches != 5 {
+		t.Errorf("with no minimum, Ahri: got %+v, want 5 matches", ahri)
+	}
+}
+
+func TestAggregateFile_SlotSampleInterval(t *testing.T) {
+	const matches = 2000
+	path := GenerateWarmFile(t, t.TempDir(), matches, WithRoles("MIDDLE"), WithBuildOrderRate(1))
+	allItems := func(int) bool { return true }
+
+	full, err := aggregateFile(path, allItems, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultAggregateOptions()
+	opts.SlotSampleInterval = 4
+	sampled, err := aggregateFile(path, allItems, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cheap stats see every record
+	if !reflect.DeepEqual(sampled.ChampionStats, full.ChampionStats) ||
+		!reflect.DeepEqual(sampled.ItemStats, full.ItemStats) ||
+		!reflect.DeepEqual(sampled.MatchupStats, full.MatchupStats) {
+		t.Error("champion, item and matchup stats should not depend on the slot sample")
+	}
+
+	firstSlots := func(agg *AggData) int {
+		n := 0
+		for key, stats := range agg.ItemSlotStats {
+			if key.BuildSlot == 1 {
+				n += stats.Matches
+			}
+		}
+		return n
+	}
+	if got := firstSlots(full); got != 2*matches {
+		t.Fatalf("unsampled slot 1 saw %d participants, want %d", got, 2*matches)
+	}
+	// Both players of a sampled match are kept, so the count is even and near 1/4
+	got := firstSlots(sampled)
+	if got%2 != 0 || got < 2*matches/4*8/10 || got > 2*matches/4*12/10 {
+		t.Errorf("sampled slot 1 saw %d participants, want about %d", got, 2*matches/4)
+	}
+}
+
+func TestAggregateReader_ItemPairStats(t *testing.T) {
+	// Ahri finishes Zhonya then Rabadon (listed high ID first), plus a repeat and a
+	// component; Zed finishes the same two items and loses
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true,"item0":3157,"item1":3089,"item2":3157,"item3":1058}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":238,"teamPosition":"MIDDLE","win":false,"item0":3089,"item1":3157}
+`
+	completed := func(itemID int) bool { return itemID >= 3000 }
+	agg, err := aggregateReader(strings.NewReader(sampleData), completed, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatalf("aggregateReader failed: %v", err)
+	}
+
+	ahri := agg.ItemPairStats[ItemPairStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", ItemA: 3089, ItemB: 3157}]
+	if ahri == nil || ahri.Matches != 1 || ahri.Wins != 1 {
+		t.Errorf("Ahri Rabadon + Zhonya = %+v, want 1 match and 1 win", ahri)
+	}
+	zed := agg.ItemPairStats[ItemPairStatsKey{Patch: "15.24", ChampionID: 238, TeamPosition: "MIDDLE", ItemA: 3089, ItemB: 3157}]
+	if zed == nil || zed.Matches != 1 || zed.Wins != 0 {
+		t.Errorf("Zed Rabadon + Zhonya = %+v, want 1 match and 0 wins", zed)
+	}
+	// The repeated Zhonya and the component form no pairs of their own
+	if len(agg.ItemPairStats) != 2 {
+		t.Errorf("got %d item pairs, want 2", len(agg.ItemPairStats))
+	}
+}
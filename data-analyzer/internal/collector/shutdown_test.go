@@ -3,11 +3,14 @@ package collector
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"data-analyzer/internal/storage"
 )
 
 // TestSetupSignalHandler tests that the signal handler context works
@@ -367,6 +370,92 @@ func TestShutdown_Integration_MultipleSignals(t *testing.T) {
 	}
 }
 
+// TestShutdown_FinalFlushPushesSubThresholdWarmFiles checks that warm files below the
+// reduce threshold are aggregated, archived and pushed before shutdown returns
+func TestShutdown_FinalFlushPushesSubThresholdWarmFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	warmDir := filepath.Join(tempDir, "warm")
+	coldDir := filepath.Join(tempDir, "cold")
+	if err := os.MkdirAll(warmDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	sampleData := `{"matchId":"NA1_1","gameVersion":"15.24.1","championId":103,"teamPosition":"MIDDLE","win":true}
+{"matchId":"NA1_1","gameVersion":"15.24.1","championId":238,"teamPosition":"MIDDLE","win":false}
+`
+	if err := os.WriteFile(filepath.Join(warmDir, "raw_matches_001.jsonl"), []byte(sampleData), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultConfig()
+	config.ShutdownTimeout = 5 * time.Second
+	// No-op reduce stands in for a cycle that ran before these files arrived
+	cc := NewContinuousCollector(&mockSpiderForTest{}, func(ctx context.Context) error { return nil },
+		&mockKeyValidatorForTest{valid: true}, nil, nil, config)
+
+	pusher := &recordingPusher{}
+	flush := WarmFlush{
+		WarmDir:    warmDir,
+		ColdDir:    coldDir,
+		ItemFilter: func(int) bool { return true },
+		Options:    DefaultAggregateOptions(),
+		Compressor: storage.DefaultCompressor,
+		Pusher:     pusher,
+	}
+	cc.SetShutdownFlush(func(ctx context.Context) error {
+		_, err := flush.Run(ctx)
+		return err
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		_ = cc.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cc.Shutdown(context.Background())
+
+	// Everything must be done by the time Shutdown returns, before the process exits
+	pushed := pusher.pushed()
+	if len(pushed) != 1 || pushed[0].TotalRecords != 2 || len(pushed[0].MatchupStats) != 2 {
+		t.Fatalf("pushed %d aggregations, want 1 with 2 records and 2 matchups: %+v", len(pushed), pushed)
+	}
+	if left, _ := filepath.Glob(filepath.Join(warmDir, WarmFilePattern)); len(left) != 0 {
+		t.Errorf("warm files left after final flush: %v", left)
+	}
+	if archived, _ := filepath.Glob(filepath.Join(coldDir, "raw_matches_001.jsonl.*")); len(archived) != 1 {
+		t.Errorf("cold archive = %v, want one file", archived)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Collector did not shut down")
+	}
+}
+
+// recordingPusher keeps every aggregation pushed to it
+type recordingPusher struct {
+	mu   sync.Mutex
+	aggs []*AggData
+}
+
+func (p *recordingPusher) PushAggData(ctx context.Context, data *AggData) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.aggs = append(p.aggs, data)
+	return nil
+}
+
+func (p *recordingPusher) pushed() []*AggData {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*AggData(nil), p.aggs...)
+}
+
 // mockSpiderForTest is a test helper
 type mockSpiderForTest struct {
 	runCalls   atomic.Int32
@@ -36,11 +36,12 @@ type Spider struct {
 
 	// Configuration
 	matchesPerPlayer     int
+	aramMatchesPerPlayer int // ARAM match IDs fetched per player on top of ranked (0 = none)
 	maxPlayers           int
 	workerCount          int
 	timelineSamplingRate float64 // Probability of fetching timeline (0.0-1.0)
 
-	// Random number generator (thread-safe via per-worker seeding)
+	// Random number generator used for all sampling decisions (guarded by rngMu)
 	rngMu sync.Mutex
 	rng   *rand.Rand
 
@@ -65,6 +66,9 @@ type Spider struct {
 	playersSkippedRank int64 // Players skipped due to low rank
 	startTime          time.Time
 
+	// Called with the match's patch after each match is written
+	onMatchComplete func(patch string)
+
 	// Shutdown
 	wg     sync.WaitGroup
 	cancel context.CancelFunc
@@ -83,9 +87,11 @@ type MatchResult struct {
 // SpiderConfig holds configuration for the spider
 type SpiderConfig struct {
 	MatchesPerPlayer     int
+	ARAMMatchesPerPlayer int // ARAM match IDs fetched per player in addition to ranked (0 disables)
 	MaxPlayers           int
 	WorkerCount          int
-	TimelineSamplingRate float64 // 0.0-1.0, default 0.20 (20%)
+	TimelineSamplingRate float64    // 0.0-1.0, default 0.20 (20%)
+	Rand                 *rand.Rand // Optional source for random selection; seeded from time if nil
 }
 
 // NewSpider creates a new spider with worker pool
@@ -103,15 +109,21 @@ func NewSpider(client *riot.Client, rotator *storage.FileRotator, currentPatch s
 		samplingRate = 1.0
 	}
 
+	rng := cfg.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
 	return &Spider{
 		client:               client,
 		rotator:              rotator,
 		currentPatch:         currentPatch,
 		matchesPerPlayer:     cfg.MatchesPerPlayer,
+		aramMatchesPerPlayer: cfg.ARAMMatchesPerPlayer,
 		maxPlayers:           cfg.MaxPlayers,
 		workerCount:          cfg.WorkerCount,
 		timelineSamplingRate: samplingRate,
-		rng:                  rand.New(rand.NewSource(time.Now().UnixNano())),
+		rng:                  rng,
 		visitedMatches:       bloom.NewWithEstimates(500000, 0.001),
 		visitedPUUIDs:        bloom.NewWithEstimates(1000000, 0.001),
 		playerQueue:          make([]string, 0, 1000),
@@ -120,6 +132,20 @@ func NewSpider(client *riot.Client, rotator *storage.FileRotator, currentPatch s
 	}
 }
 
+// aramMatchHistory fetches the player's recent ARAM match IDs when ARAM collection
+// is enabled. Failures are logged and yield no IDs so ranked collection carries on.
+func (s *Spider) aramMatchHistory(ctx context.Context, puuid string) []string {
+	if s.aramMatchesPerPlayer <= 0 {
+		return nil
+	}
+	matchIDs, err := s.client.GetMatchHistoryForQueue(ctx, puuid, riot.QueueARAM, s.aramMatchesPerPlayer)
+	if err != nil {
+		log.Printf("[Spider] Failed to fetch ARAM history for %s: %v", puuid[:min(16, len(puuid))], err)
+		return nil
+	}
+	return matchIDs
+}
+
 // Run starts the spider with the given starting PUUID
 func (s *Spider) Run(ctx context.Context, startingPUUID string) error {
 	ctx, s.cancel = context.WithCancel(ctx)
@@ -202,6 +228,7 @@ func (s *Spider) producerLoop(ctx context.Context) {
 			log.Printf("[Producer] Failed to fetch match history for %s: %v", puuid[:16], err)
 			continue
 		}
+		matchIDs = append(matchIDs, s.aramMatchHistory(ctx, puuid)...)
 
 		elapsed := time.Since(s.startTime)
 		fmt.Printf("\n[Player %d/%d] [%s] Processing: %s... (%s %s, %d matches)\n",
@@ -370,13 +397,17 @@ func (s *Spider) processResults(ctx context.Context) {
 					GameVersion:  result.Match.Info.GameVersion,
 					GameDuration: result.Match.Info.GameDuration,
 					GameCreation: result.Match.Info.GameCreation,
+					QueueID:      result.Match.Info.QueueID,
 					PUUID:        p.PUUID,
 					GameName:     p.RiotIdGameName,
 					TagLine:      p.RiotIdTagline,
 					ChampionID:   p.ChampionID,
 					ChampionName: p.ChampionName,
 					TeamPosition: p.TeamPosition,
+					TeamID:       p.TeamID,
 					Win:          p.Win,
+					Summoner1ID:  p.Summoner1ID,
+					Summoner2ID:  p.Summoner2ID,
 					Item0:        p.Item0,
 					Item1:        p.Item1,
 					Item2:        p.Item2,
@@ -401,6 +432,8 @@ func (s *Spider) processResults(ctx context.Context) {
 			// Signal match complete
 			if err := s.rotator.MatchComplete(); err != nil {
 				log.Printf("  [Writer] Failed to complete match: %v", err)
+			} else {
+				s.notifyMatchComplete(result.Match.Info.GameVersion)
 			}
 
 			atomic.AddInt64(&s.totalMatches, 1)
@@ -534,6 +567,7 @@ func (s *Spider) RunContinuous(ctx context.Context) error {
 		log.Printf("[Spider] Failed to fetch match history for %s: %v", puuid[:min(16, len(puuid))], err)
 		return nil
 	}
+	matchIDs = append(matchIDs, s.aramMatchHistory(ctx, puuid)...)
 
 	elapsed := time.Since(s.startTime)
 	totalMatches := atomic.LoadInt64(&s.totalMatches)
@@ -575,13 +609,17 @@ func (s *Spider) RunContinuous(ctx context.Context) error {
 				GameVersion:  result.Match.Info.GameVersion,
 				GameDuration: result.Match.Info.GameDuration,
 				GameCreation: result.Match.Info.GameCreation,
+				QueueID:      result.Match.Info.QueueID,
 				PUUID:        p.PUUID,
 				GameName:     p.RiotIdGameName,
 				TagLine:      p.RiotIdTagline,
 				ChampionID:   p.ChampionID,
 				ChampionName: p.ChampionName,
 				TeamPosition: p.TeamPosition,
+				TeamID:       p.TeamID,
 				Win:          p.Win,
+				Summoner1ID:  p.Summoner1ID,
+				Summoner2ID:  p.Summoner2ID,
 				Item0:        p.Item0,
 				Item1:        p.Item1,
 				Item2:        p.Item2,
@@ -604,6 +642,8 @@ func (s *Spider) RunContinuous(ctx context.Context) error {
 
 		if err := s.rotator.MatchComplete(); err != nil {
 			log.Printf("  [Spider] Failed to complete match: %v", err)
+		} else {
+			s.notifyMatchComplete(result.Match.Info.GameVersion)
 		}
 
 		atomic.AddInt64(&s.totalMatches, 1)
@@ -656,6 +696,18 @@ func (s *Spider) ResetPlayerCount() {
 	log.Printf("[Spider] Reset player count (was %d, now 0) - continuing from queue", prevCount)
 }
 
+// SetOnMatchComplete sets a callback invoked with the patch of each match written to storage
+func (s *Spider) SetOnMatchComplete(fn func(patch string)) {
+	s.onMatchComplete = fn
+}
+
+// notifyMatchComplete reports a written match to the callback, if set
+func (s *Spider) notifyMatchComplete(gameVersion string) {
+	if s.onMatchComplete != nil {
+		s.onMatchComplete(normalizePatch(gameVersion))
+	}
+}
+
 // SetAPIKey updates the API key used by the spider's riot client.
 // Implements SpiderRunner interface.
 func (s *Spider) SetAPIKey(key string) {
@@ -2,6 +2,7 @@ package collector
 
 import (
 	"context"
+	"math/rand"
 	"os"
 	"testing"
 	"time"
@@ -318,3 +319,26 @@ func TestDataCollection_InvalidRateLimit(t *testing.T) {
 		t.Logf("Warning: %d requests completed in <10ms (rate limiter may not be enforcing minimum interval)", instantRequests)
 	}
 }
+
+// Test: Timeline sampling is reproducible with a seeded source
+func TestSpider_SeededSamplingIsDeterministic(t *testing.T) {
+	sample := func(seed int64) []bool {
+		s := NewSpider(nil, nil, "", SpiderConfig{
+			TimelineSamplingRate: 0.5,
+			Rand:                 rand.New(rand.NewSource(seed)),
+		})
+		picks := make([]bool, 50)
+		for i := range picks {
+			picks[i] = s.shouldFetchTimeline()
+		}
+		return picks
+	}
+
+	first := sample(42)
+	second := sample(42)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Selection %d differs between runs with the same seed", i)
+		}
+	}
+}
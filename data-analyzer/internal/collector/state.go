@@ -1,7 +1,11 @@
 package collector
 
 import (
+	"encoding/json"
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -92,16 +96,110 @@ type StateMachine struct {
 	callback   func(from, to State)
 	cond       *sync.Cond
 	condMu     sync.Mutex
+
+	// collectingCh is closed while in COLLECTING and replaced when leaving it,
+	// so waiters can block on it instead of polling IsCollecting
+	collectingCh chan struct{}
+
+	// persistPath, if set, receives the state after every transition (see Recover).
+	// persistMu orders the writes, which happen after mu is released.
+	persistPath string
+	persistMu   sync.Mutex
+}
+
+// persistedState is the on-disk form of the last state reached
+type persistedState struct {
+	State     string    `json:"state"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 // NewStateMachine creates a new state machine starting in STARTUP state.
 func NewStateMachine() *StateMachine {
-	sm := &StateMachine{}
+	sm := &StateMachine{collectingCh: make(chan struct{})}
 	sm.state.Store(int32(StateStartup))
 	sm.cond = sync.NewCond(&sm.condMu)
 	return sm
 }
 
+// NewStateMachineWithPersistence creates a state machine that writes its state to path
+// after every successful transition, so a restarted process can Recover where it was.
+// An empty path disables persistence, like NewStateMachine.
+func NewStateMachineWithPersistence(path string) *StateMachine {
+	sm := NewStateMachine()
+	sm.persistPath = path
+	return sm
+}
+
+// Recover reads the state persisted by a previous process and returns it. A crash
+// during PUSHING resumes in PUSHING; every other state restarts from STARTUP, since
+// the reduce marker already reconciles an interrupted REDUCING. A missing file is
+// not an error and returns STARTUP.
+func (sm *StateMachine) Recover() (State, error) {
+	if sm.persistPath == "" {
+		return StateStartup, nil
+	}
+
+	raw, err := os.ReadFile(sm.persistPath)
+	if os.IsNotExist(err) {
+		return StateStartup, nil
+	}
+	if err != nil {
+		return StateStartup, fmt.Errorf("read persisted state: %w", err)
+	}
+
+	var saved persistedState
+	if err := json.Unmarshal(raw, &saved); err != nil {
+		return StateStartup, fmt.Errorf("parse persisted state %s: %w", sm.persistPath, err)
+	}
+	previous, ok := parseState(saved.State)
+	if !ok {
+		return StateStartup, fmt.Errorf("unknown persisted state %q", saved.State)
+	}
+
+	if previous == StatePushing {
+		sm.mu.Lock()
+		sm.storeState(StatePushing)
+		sm.mu.Unlock()
+		log.Printf("[StateMachine] Recovered %s from %s, resuming", previous, saved.UpdatedAt.Format(time.RFC3339))
+	}
+	return previous, nil
+}
+
+// parseState is the inverse of State.String
+func parseState(name string) (State, bool) {
+	for s := StateStartup; s <= StateShutdown; s++ {
+		if s.String() == name {
+			return s, true
+		}
+	}
+	return StateStartup, false
+}
+
+// persist writes the state atomically (temp file + rename) so a crash mid-write
+// leaves the previous file intact. Failures are logged, never fatal.
+// It runs after sm.mu is released, so a slow disk never blocks state readers. Each
+// write saves the state current at write time, so when transitions race the file
+// still ends on the latest one.
+func (sm *StateMachine) persist() {
+	if sm.persistPath == "" {
+		return
+	}
+	sm.persistMu.Lock()
+	defer sm.persistMu.Unlock()
+
+	to := sm.Current()
+	raw, err := json.Marshal(persistedState{State: to.String(), UpdatedAt: time.Now().UTC()})
+	if err == nil {
+		tmp := filepath.Join(filepath.Dir(sm.persistPath), "."+filepath.Base(sm.persistPath)+".tmp")
+		if err = os.WriteFile(tmp, raw, 0644); err == nil {
+			err = os.Rename(tmp, sm.persistPath)
+		}
+	}
+	if err != nil {
+		log.Printf("[StateMachine] Warning: failed to persist state %s: %v", to, err)
+	}
+}
+
 // Current returns the current state.
 func (sm *StateMachine) Current() State {
 	return State(sm.state.Load())
@@ -109,24 +207,46 @@ func (sm *StateMachine) Current() State {
 
 // setState sets the state directly (for testing purposes).
 func (sm *StateMachine) setState(s State) {
-	sm.state.Store(int32(s))
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.storeState(s)
+}
+
+// storeState records the new state and updates the collecting channel.
+// Caller must hold sm.mu.
+func (sm *StateMachine) storeState(to State) {
+	from := State(sm.state.Swap(int32(to)))
+	switch {
+	case to == StateCollecting && from != StateCollecting:
+		close(sm.collectingCh)
+	case to != StateCollecting && from == StateCollecting:
+		sm.collectingCh = make(chan struct{})
+	}
+}
+
+// CollectingCh returns a channel that is closed while the state machine is in
+// COLLECTING. Receiving from it blocks until collecting (re)starts.
+func (sm *StateMachine) CollectingCh() <-chan struct{} {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.collectingCh
 }
 
 // TransitionTo attempts to transition to the target state.
 // Returns an error if the transition is not valid.
 func (sm *StateMachine) TransitionTo(to State) error {
 	sm.mu.Lock()
-	defer sm.mu.Unlock()
 
 	from := State(sm.state.Load())
 
 	// Check if transition is valid
 	if !sm.isValidTransition(from, to) {
+		sm.mu.Unlock()
 		return &InvalidTransitionError{From: from, To: to}
 	}
 
 	// Perform transition
-	sm.state.Store(int32(to))
+	sm.storeState(to)
 
 	// Notify waiters
 	sm.cond.Broadcast()
@@ -135,7 +255,9 @@ func (sm *StateMachine) TransitionTo(to State) error {
 	if sm.callback != nil {
 		sm.callback(from, to)
 	}
+	sm.mu.Unlock()
 
+	sm.persist()
 	return nil
 }
 
@@ -176,15 +298,15 @@ func (sm *StateMachine) CanReduce() bool {
 // This is safe to call concurrently - only one caller will succeed.
 func (sm *StateMachine) TryTransitionToReducing() bool {
 	sm.mu.Lock()
-	defer sm.mu.Unlock()
 
 	from := State(sm.state.Load())
 	if from != StateCollecting {
+		sm.mu.Unlock()
 		return false
 	}
 
 	to := StateReducing
-	sm.state.Store(int32(to))
+	sm.storeState(to)
 
 	// Notify waiters
 	sm.cond.Broadcast()
@@ -193,7 +315,9 @@ func (sm *StateMachine) TryTransitionToReducing() bool {
 	if sm.callback != nil {
 		sm.callback(from, to)
 	}
+	sm.mu.Unlock()
 
+	sm.persist()
 	return true
 }
 
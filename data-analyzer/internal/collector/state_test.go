@@ -2,6 +2,8 @@ package collector
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -368,3 +370,111 @@ func TestStateMachine_WaitForState_Timeout(t *testing.T) {
 		t.Errorf("WaitForState returned too early: %v", elapsed)
 	}
 }
+
+func TestStateMachine_CollectingCh_BlocksWithoutSpinning(t *testing.T) {
+	sm := NewStateMachine()
+	sm.setState(StateCollecting)
+	if !sm.TryTransitionToReducing() {
+		t.Fatal("expected transition to REDUCING")
+	}
+
+	// A rotator-style loop that only iterates while collecting
+	var iterations atomic.Int64
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-sm.CollectingCh():
+				iterations.Add(1)
+				select {
+				case <-stop:
+					return
+				case <-time.After(time.Millisecond):
+				}
+			}
+		}
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if n := iterations.Load(); n != 0 {
+		t.Errorf("loop ran %d iterations while reducing, want 0", n)
+	}
+
+	sm.TransitionTo(StatePushing)
+	sm.TransitionTo(StateCollecting)
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for iterations.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if iterations.Load() == 0 {
+		t.Error("loop did not resume after returning to COLLECTING")
+	}
+
+	close(stop)
+	<-done
+}
+
+func TestStateMachine_PersistenceResumesPushing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	sm := NewStateMachineWithPersistence(path)
+	sm.TransitionTo(StateCollecting)
+	if !sm.TryTransitionToReducing() {
+		t.Fatal("TryTransitionToReducing failed")
+	}
+	sm.TransitionTo(StatePushing)
+
+	// Simulated crash: a new process recovers from the file
+	restarted := NewStateMachineWithPersistence(path)
+	previous, err := restarted.Recover()
+	if err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	if previous != StatePushing || restarted.Current() != StatePushing {
+		t.Errorf("Recover = %s, Current = %s; want PUSHING for both", previous, restarted.Current())
+	}
+	// The resumed push can finish normally
+	if err := restarted.TransitionTo(StateCollecting); err != nil {
+		t.Errorf("PUSHING -> COLLECTING after recovery: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(path), ".state.json.tmp")); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+}
+
+func TestStateMachine_RecoverRestartsOtherStates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	sm := NewStateMachineWithPersistence(path)
+	sm.TransitionTo(StateCollecting)
+	sm.TryTransitionToReducing()
+
+	restarted := NewStateMachineWithPersistence(path)
+	previous, err := restarted.Recover()
+	if err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	if previous != StateReducing || restarted.Current() != StateStartup {
+		t.Errorf("Recover = %s, Current = %s; want REDUCING reported and STARTUP resumed", previous, restarted.Current())
+	}
+
+	// No file yet, or no persistence at all: fresh start
+	if state, err := NewStateMachineWithPersistence(filepath.Join(t.TempDir(), "missing.json")).Recover(); err != nil || state != StateStartup {
+		t.Errorf("missing file: Recover = %s, %v", state, err)
+	}
+	if state, err := NewStateMachine().Recover(); err != nil || state != StateStartup {
+		t.Errorf("no persistence: Recover = %s, %v", state, err)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewStateMachineWithPersistence(path).Recover(); err == nil {
+		t.Error("corrupt state file should return an error")
+	}
+}
@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	json "github.com/goccy/go-json"
+)
+
+// StatusSource is what the status server reads from (implemented by ContinuousCollector)
+type StatusSource interface {
+	State() State
+	GetStats() CollectorStats
+}
+
+// NewStatusHandler returns a read-only handler exposing /state, /metrics and /healthz
+func NewStatusHandler(src StatusSource) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/state", func(w http.ResponseWriter, r *http.Request) {
+		writeStatusJSON(w, map[string]string{"state": src.State().String()})
+	})
+
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		writeStatusJSON(w, src.GetStats())
+	})
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if src.State() == StateShutdown {
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+
+	return mux
+}
+
+func writeStatusJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// StartStatusServer serves the status handler on addr until ctx is cancelled
+func StartStatusServer(ctx context.Context, addr string, src StatusSource) {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           NewStatusHandler(src),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	go func() {
+		log.Printf("[StatusServer] Listening on %s", addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("[StatusServer] Error: %v", err)
+		}
+	}()
+}
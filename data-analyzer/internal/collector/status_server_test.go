@@ -0,0 +1,76 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	json "github.com/goccy/go-json"
+)
+
+type stubStatusSource struct {
+	state State
+	stats CollectorStats
+}
+
+func (s *stubStatusSource) State() State             { return s.state }
+func (s *stubStatusSource) GetStats() CollectorStats { return s.stats }
+
+func TestStatusHandler_StateAndMetrics(t *testing.T) {
+	src := &stubStatusSource{
+		state: StateReducing,
+		stats: CollectorStats{
+			MatchesCollected: 42,
+			RuntimeSeconds:   60,
+			LastReduceAgo:    -1,
+			GamesByPatch:     map[string]int64{"15.24": 42},
+		},
+	}
+	server := httptest.NewServer(NewStatusHandler(src))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/state")
+	if err != nil {
+		t.Fatalf("GET /state: %v", err)
+	}
+	var state map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		t.Fatalf("decode /state: %v", err)
+	}
+	resp.Body.Close()
+	if state["state"] != "REDUCING" {
+		t.Errorf("/state = %q, want REDUCING", state["state"])
+	}
+
+	resp, err = http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	var stats CollectorStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatalf("/metrics is not valid JSON: %v", err)
+	}
+	resp.Body.Close()
+	if stats.MatchesCollected != 42 || stats.GamesByPatch["15.24"] != 42 {
+		t.Errorf("/metrics = %+v, want stub stats", stats)
+	}
+
+	resp, err = http.Get(server.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/healthz status = %d, want 200", resp.StatusCode)
+	}
+
+	src.state = StateShutdown
+	resp, err = http.Get(server.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("/healthz during shutdown = %d, want 503", resp.StatusCode)
+	}
+}
@@ -0,0 +1,211 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"data-analyzer/internal/storage"
+)
+
+// WarmFileSpec describes the matches GenerateWarmFile writes. Every match has one
+// player per role on each team (blue is team 100, red is team 200).
+type WarmFileSpec struct {
+	FileName       string   // Written into dir; defaults to raw_matches_gen_<FirstMatch>.jsonl
+	Patch          string   // gameVersion, e.g. "15.24.1"
+	Roles          []string // Positions each team fields
+	Champions      []int    // Player slot i (blue roles, then red roles) plays Champions[i%len]; nil draws at random
+	BlueWinRate    float64  // Share of matches blue wins; the wins come first so counts are exact
+	ItemPool       []int    // Final items are drawn from this pool
+	ItemsPerPlayer int      // Final items per player, at most 6
+	PlayerItems    [][]int  // Player slot i ends with PlayerItems[i%len]; overrides ItemPool
+	BuildOrderRate float64  // Share of players with a timeline build order
+	GameDuration   int      // Seconds; 0 spreads games between 25 and 40 minutes
+	GameCreation   int64    // First match's creation time in ms; later matches follow 1s apart
+	FirstMatch     int      // Match ID offset, so several files don't share match IDs
+	Seed           int64    // Seeds the random champion, item and build order draws
+}
+
+// WarmFileOption adjusts a WarmFileSpec
+type WarmFileOption func(*WarmFileSpec)
+
+func WithFileName(name string) WarmFileOption {
+	return func(s *WarmFileSpec) { s.FileName = name }
+}
+
+func WithPatch(gameVersion string) WarmFileOption {
+	return func(s *WarmFileSpec) { s.Patch = gameVersion }
+}
+
+func WithRoles(roles ...string) WarmFileOption {
+	return func(s *WarmFileSpec) { s.Roles = roles }
+}
+
+func WithChampions(ids ...int) WarmFileOption {
+	return func(s *WarmFileSpec) { s.Champions = ids }
+}
+
+func WithBlueWinRate(rate float64) WarmFileOption {
+	return func(s *WarmFileSpec) { s.BlueWinRate = rate }
+}
+
+func WithItemPool(items ...int) WarmFileOption {
+	return func(s *WarmFileSpec) { s.ItemPool = items }
+}
+
+func WithItemsPerPlayer(n int) WarmFileOption {
+	return func(s *WarmFileSpec) { s.ItemsPerPlayer = n }
+}
+
+func WithPlayerItems(items ...[]int) WarmFileOption {
+	return func(s *WarmFileSpec) { s.PlayerItems = items }
+}
+
+func WithBuildOrderRate(rate float64) WarmFileOption {
+	return func(s *WarmFileSpec) { s.BuildOrderRate = rate }
+}
+
+func WithGameDuration(seconds int) WarmFileOption {
+	return func(s *WarmFileSpec) { s.GameDuration = seconds }
+}
+
+func WithGameCreation(ms int64) WarmFileOption {
+	return func(s *WarmFileSpec) { s.GameCreation = ms }
+}
+
+func WithFirstMatch(n int) WarmFileOption {
+	return func(s *WarmFileSpec) { s.FirstMatch = n }
+}
+
+func WithSeed(seed int64) WarmFileOption {
+	return func(s *WarmFileSpec) { s.Seed = seed }
+}
+
+// defaultWarmFileSpec is a full 5v5 ranked game on 15.24 with an even win split
+func defaultWarmFileSpec() WarmFileSpec {
+	return WarmFileSpec{
+		Patch:          "15.24.1",
+		Roles:          []string{"TOP", "JUNGLE", "MIDDLE", "BOTTOM", "UTILITY"},
+		BlueWinRate:    0.5,
+		ItemPool:       []int{3020, 3031, 3071, 3089, 3142, 3153, 3157, 6653, 6655, 6672},
+		ItemsPerPlayer: 3,
+		Seed:           1,
+	}
+}
+
+// GenerateWarmFile writes matches RawMatch games as a warm JSONL file in dir and
+// returns its path. Output is deterministic for a given spec.
+func GenerateWarmFile(tb testing.TB, dir string, matches int, opts ...WarmFileOption) string {
+	tb.Helper()
+	spec := defaultWarmFileSpec()
+	for _, opt := range opts {
+		opt(&spec)
+	}
+	if spec.FileName == "" {
+		spec.FileName = fmt.Sprintf("raw_matches_gen_%06d.jsonl", spec.FirstMatch)
+	}
+	if spec.ItemsPerPlayer > 6 {
+		tb.Fatalf("GenerateWarmFile: %d items per player, at most 6 fit", spec.ItemsPerPlayer)
+	}
+	for _, items := range spec.PlayerItems {
+		if len(items) > 6 {
+			tb.Fatalf("GenerateWarmFile: %d player items, at most 6 fit", len(items))
+		}
+	}
+	creation := spec.GameCreation
+	if creation == 0 {
+		creation = 1700000000000 + int64(spec.FirstMatch)*1000
+	}
+
+	rng := rand.New(rand.NewSource(spec.Seed))
+	blueWins := int(spec.BlueWinRate*float64(matches) + 0.5)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for m := 0; m < matches; m++ {
+		n := spec.FirstMatch + m
+		matchID := fmt.Sprintf("NA1_%d", n)
+		blueWon := m < blueWins
+		duration := spec.GameDuration
+		if duration == 0 {
+			duration = 1500 + n%900
+		}
+
+		for slot := 0; slot < 2*len(spec.Roles); slot++ {
+			teamID := 100
+			if slot >= len(spec.Roles) {
+				teamID = 200
+			}
+			championID := 1 + rng.Intn(170)
+			if len(spec.Champions) > 0 {
+				championID = spec.Champions[slot%len(spec.Champions)]
+			}
+
+			record := storage.RawMatch{
+				MatchID:      matchID,
+				GameVersion:  spec.Patch,
+				GameDuration: duration,
+				GameCreation: creation + int64(m)*1000,
+				PUUID:        fmt.Sprintf("%s_p%d", matchID, slot),
+				ChampionID:   championID,
+				TeamPosition: spec.Roles[slot%len(spec.Roles)],
+				TeamID:       teamID,
+				Win:          (teamID == 100) == blueWon,
+				Summoner1ID:  4,
+				Summoner2ID:  []int{3, 7, 11, 12, 14}[rng.Intn(5)],
+			}
+
+			items := make([]int, 6)
+			bought := spec.ItemsPerPlayer
+			if len(spec.PlayerItems) > 0 {
+				bought = copy(items, spec.PlayerItems[slot%len(spec.PlayerItems)])
+			} else {
+				for i := 0; i < bought && len(spec.ItemPool) > 0; i++ {
+					items[i] = spec.ItemPool[rng.Intn(len(spec.ItemPool))]
+				}
+			}
+			record.Item0, record.Item1, record.Item2 = items[0], items[1], items[2]
+			record.Item3, record.Item4, record.Item5 = items[3], items[4], items[5]
+			if spec.BuildOrderRate > 0 && rng.Float64() < spec.BuildOrderRate {
+				record.BuildOrder = append([]int(nil), items[:bought]...)
+			}
+
+			if err := enc.Encode(record); err != nil {
+				tb.Fatalf("GenerateWarmFile: encode record: %v", err)
+			}
+		}
+	}
+
+	path := filepath.Join(dir, spec.FileName)
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		tb.Fatalf("GenerateWarmFile: %v", err)
+	}
+	return path
+}
+
+func TestGenerateWarmFile_ExactWinSplit(t *testing.T) {
+	dir := t.TempDir()
+	path := GenerateWarmFile(t, dir, 10, WithRoles("TOP"), WithChampions(86, 122), WithBlueWinRate(0.7))
+
+	agg, err := aggregateFile(path, func(int) bool { return true }, DefaultAggregateOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	garen := agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 86, TeamPosition: "TOP"}]
+	darius := agg.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 122, TeamPosition: "TOP"}]
+	if garen == nil || darius == nil || garen.Wins != 7 || darius.Wins != 3 || garen.Matches != 10 {
+		t.Errorf("got Garen %+v, Darius %+v; want 7/10 and 3/10", garen, darius)
+	}
+
+	// Same spec, same bytes
+	again := GenerateWarmFile(t, t.TempDir(), 10, WithRoles("TOP"), WithChampions(86, 122), WithBlueWinRate(0.7))
+	a, _ := os.ReadFile(path)
+	b, _ := os.ReadFile(again)
+	if !bytes.Equal(a, b) {
+		t.Error("generator output should be deterministic")
+	}
+}
@@ -37,80 +37,129 @@ func (p *TursoDataPusher) PushAggData(ctx context.Context, data *AggData) error
 		log.Printf("[TursoPusher] Warning: failed to drop indexes: %v", err)
 	}
 
-	// Push champion stats
-	if len(data.ChampionStats) > 0 {
-		stats := make([]db.ChampionStat, 0, len(data.ChampionStats))
-		for k, v := range data.ChampionStats {
-			stats = append(stats, db.ChampionStat{
-				Patch:        k.Patch,
-				ChampionID:   k.ChampionID,
-				TeamPosition: k.TeamPosition,
-				Wins:         v.Wins,
-				Matches:      v.Matches,
+	var batch db.StatsBatch
+
+	// Champion stats
+	batch.ChampionStats = make([]db.ChampionStat, 0, len(data.ChampionStats))
+	for k, v := range data.ChampionStats {
+		if k.GameMode == GameModeARAM {
+			batch.ARAMStats = append(batch.ARAMStats, db.ARAMChampionStat{
+				Patch:      k.Patch,
+				ChampionID: k.ChampionID,
+				Wins:       v.Wins,
+				Matches:    v.Matches,
 			})
+			continue
 		}
-		if err := p.client.InsertChampionStats(ctx, stats); err != nil {
-			return fmt.Errorf("failed to insert champion stats: %w", err)
-		}
-		log.Printf("[TursoPusher] Inserted %d champion stats", len(stats))
+		batch.ChampionStats = append(batch.ChampionStats, db.ChampionStat{
+			Patch:        k.Patch,
+			ChampionID:   k.ChampionID,
+			TeamPosition: k.TeamPosition,
+			Wins:         v.Wins,
+			Matches:      v.Matches,
+		})
 	}
 
-	// Push item stats
-	if len(data.ItemStats) > 0 {
-		items := make([]db.ChampionItem, 0, len(data.ItemStats))
-		for k, v := range data.ItemStats {
-			items = append(items, db.ChampionItem{
-				Patch:        k.Patch,
-				ChampionID:   k.ChampionID,
-				TeamPosition: k.TeamPosition,
-				ItemID:       k.ItemID,
-				Wins:         v.Wins,
-				Matches:      v.Matches,
+	// Item stats
+	batch.Items = make([]db.ChampionItem, 0, len(data.ItemStats))
+	for k, v := range data.ItemStats {
+		if k.GameMode == GameModeARAM {
+			batch.ARAMItems = append(batch.ARAMItems, db.ARAMChampionItem{
+				Patch:      k.Patch,
+				ChampionID: k.ChampionID,
+				ItemID:     k.ItemID,
+				Wins:       v.Wins,
+				Matches:    v.Matches,
 			})
+			continue
 		}
-		if err := p.client.InsertChampionItems(ctx, items); err != nil {
-			return fmt.Errorf("failed to insert champion items: %w", err)
-		}
-		log.Printf("[TursoPusher] Inserted %d item stats", len(items))
+		batch.Items = append(batch.Items, db.ChampionItem{
+			Patch:        k.Patch,
+			ChampionID:   k.ChampionID,
+			TeamPosition: k.TeamPosition,
+			ItemID:       k.ItemID,
+			Wins:         v.Wins,
+			Matches:      v.Matches,
+		})
 	}
 
-	// Push item slot stats
-	if len(data.ItemSlotStats) > 0 {
-		slots := make([]db.ChampionItemSlot, 0, len(data.ItemSlotStats))
-		for k, v := range data.ItemSlotStats {
-			slots = append(slots, db.ChampionItemSlot{
-				Patch:        k.Patch,
-				ChampionID:   k.ChampionID,
-				TeamPosition: k.TeamPosition,
-				ItemID:       k.ItemID,
-				BuildSlot:    k.BuildSlot,
-				Wins:         v.Wins,
-				Matches:      v.Matches,
-			})
-		}
-		if err := p.client.InsertChampionItemSlots(ctx, slots); err != nil {
-			return fmt.Errorf("failed to insert champion item slots: %w", err)
-		}
-		log.Printf("[TursoPusher] Inserted %d item slot stats", len(slots))
+	// Item slot stats
+	batch.ItemSlots = make([]db.ChampionItemSlot, 0, len(data.ItemSlotStats))
+	for k, v := range data.ItemSlotStats {
+		batch.ItemSlots = append(batch.ItemSlots, db.ChampionItemSlot{
+			Patch:        k.Patch,
+			ChampionID:   k.ChampionID,
+			TeamPosition: k.TeamPosition,
+			ItemID:       k.ItemID,
+			BuildSlot:    k.BuildSlot,
+			Wins:         v.Wins,
+			Matches:      v.Matches,
+		})
 	}
 
-	// Push matchup stats
-	if len(data.MatchupStats) > 0 {
-		matchups := make([]db.ChampionMatchup, 0, len(data.MatchupStats))
-		for k, v := range data.MatchupStats {
-			matchups = append(matchups, db.ChampionMatchup{
-				Patch:           k.Patch,
-				ChampionID:      k.ChampionID,
-				TeamPosition:    k.TeamPosition,
-				EnemyChampionID: k.EnemyChampionID,
-				Wins:            v.Wins,
-				Matches:         v.Matches,
-			})
-		}
-		if err := p.client.InsertChampionMatchups(ctx, matchups); err != nil {
-			return fmt.Errorf("failed to insert champion matchups: %w", err)
-		}
-		log.Printf("[TursoPusher] Inserted %d matchup stats", len(matchups))
+	// Matchup stats
+	batch.Matchups = make([]db.ChampionMatchup, 0, len(data.MatchupStats))
+	for k, v := range data.MatchupStats {
+		batch.Matchups = append(batch.Matchups, db.ChampionMatchup{
+			Patch:           k.Patch,
+			ChampionID:      k.ChampionID,
+			TeamPosition:    k.TeamPosition,
+			EnemyChampionID: k.EnemyChampionID,
+			Wins:            v.Wins,
+			Matches:         v.Matches,
+		})
+	}
+
+	// Matchup stats split by game length
+	batch.MatchupDurations = make([]db.ChampionMatchupDuration, 0, len(data.MatchupDurationStats))
+	for k, v := range data.MatchupDurationStats {
+		batch.MatchupDurations = append(batch.MatchupDurations, db.ChampionMatchupDuration{
+			Patch:           k.Patch,
+			ChampionID:      k.ChampionID,
+			TeamPosition:    k.TeamPosition,
+			EnemyChampionID: k.EnemyChampionID,
+			DurationBucket:  k.DurationBucket,
+			Wins:            v.Wins,
+			Matches:         v.Matches,
+		})
+	}
+
+	// Same-team champion pairs
+	batch.AllyPairs = make([]db.ChampionAllyPair, 0, len(data.AllyPairStats))
+	for k, v := range data.AllyPairStats {
+		batch.AllyPairs = append(batch.AllyPairs, db.ChampionAllyPair{
+			Patch:          k.Patch,
+			ChampionID:     k.ChampionID,
+			AllyChampionID: k.AllyChampionID,
+			Wins:           v.Wins,
+			Matches:        v.Matches,
+		})
+	}
+
+	// Summoner spell pairs
+	batch.SpellPairs = make([]db.ChampionSpellPair, 0, len(data.SpellPairStats))
+	for k, v := range data.SpellPairStats {
+		batch.SpellPairs = append(batch.SpellPairs, db.ChampionSpellPair{
+			Patch:        k.Patch,
+			ChampionID:   k.ChampionID,
+			TeamPosition: k.TeamPosition,
+			Spell1ID:     k.Spell1ID,
+			Spell2ID:     k.Spell2ID,
+			Wins:         v.Wins,
+			Matches:      v.Matches,
+		})
+	}
+
+	// Upsert everything in one transaction, keyed by the push ID so retries are no-ops
+	applied, err := p.client.PushStatsOnce(ctx, data.PushID, batch)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		log.Printf("[TursoPusher] Push %s already applied, skipping", data.PushID)
+	} else {
+		log.Printf("[TursoPusher] Inserted %d champion stats, %d item stats, %d item slot stats, %d matchup stats, %d ARAM champion stats",
+			len(batch.ChampionStats), len(batch.Items), len(batch.ItemSlots), len(batch.Matchups), len(batch.ARAMStats))
 	}
 
 	// Update data version
@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"data-analyzer/internal/db"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestTursoDataPusher_RetriedPushIsIdempotent(t *testing.T) {
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open sqlite: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1) // keep the in-memory DB on one connection
+	defer sqlDB.Close()
+
+	pusher := NewTursoDataPusher(db.NewTursoClientFromDB(sqlDB))
+	ctx := context.Background()
+
+	data := newAggData()
+	data.DetectedPatch = "15.24"
+	data.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE"}] = &ChampionStats{Wins: 10, Matches: 20}
+	data.MatchupStats[MatchupStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", EnemyChampionID: 238}] = &MatchupStats{Wins: 6, Matches: 10}
+
+	// Same AggData pushed twice, as a retry after an ambiguous failure would
+	for i := 0; i < 2; i++ {
+		if err := pusher.PushAggData(ctx, data); err != nil {
+			t.Fatalf("PushAggData attempt %d failed: %v", i+1, err)
+		}
+	}
+
+	var wins, matches int
+	if err := sqlDB.QueryRow(`SELECT wins, matches FROM champion_stats WHERE champion_id = 103`).Scan(&wins, &matches); err != nil {
+		t.Fatalf("Failed to read champion stats: %v", err)
+	}
+	if wins != 10 || matches != 20 {
+		t.Errorf("Champion stats doubled: got wins=%d matches=%d, want 10/20", wins, matches)
+	}
+	if err := sqlDB.QueryRow(`SELECT matches FROM champion_matchups WHERE champion_id = 103`).Scan(&matches); err != nil {
+		t.Fatalf("Failed to read matchup stats: %v", err)
+	}
+	if matches != 10 {
+		t.Errorf("Matchup matches doubled: got %d, want 10", matches)
+	}
+
+	// A new aggregation gets a new push ID and accumulates normally
+	next := newAggData()
+	next.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE"}] = &ChampionStats{Wins: 1, Matches: 2}
+	if err := pusher.PushAggData(ctx, next); err != nil {
+		t.Fatalf("PushAggData failed: %v", err)
+	}
+	sqlDB.QueryRow(`SELECT matches FROM champion_stats WHERE champion_id = 103`).Scan(&matches)
+	if matches != 22 {
+		t.Errorf("Second aggregation should accumulate: got %d matches, want 22", matches)
+	}
+}
+
+func TestTursoDataPusher_PushesMatchupDurations(t *testing.T) {
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open sqlite: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	defer sqlDB.Close()
+
+	pusher := NewTursoDataPusher(db.NewTursoClientFromDB(sqlDB))
+
+	data := newAggData()
+	key := MatchupStatsKey{Patch: "15.24", ChampionID: 103, TeamPosition: "MIDDLE", EnemyChampionID: 238}
+	data.MatchupDurationStats[MatchupDurationStatsKey{MatchupStatsKey: key, DurationBucket: DurationLate}] = &MatchupStats{Wins: 2, Matches: 5}
+
+	if err := pusher.PushAggData(context.Background(), data); err != nil {
+		t.Fatalf("PushAggData failed: %v", err)
+	}
+
+	var wins, matches int
+	err = sqlDB.QueryRow(`SELECT wins, matches FROM champion_matchup_durations
+		WHERE champion_id = 103 AND enemy_champion_id = 238 AND duration_bucket = 'late'`).Scan(&wins, &matches)
+	if err != nil {
+		t.Fatalf("Failed to read duration stats: %v", err)
+	}
+	if wins != 2 || matches != 5 {
+		t.Errorf("got wins=%d matches=%d, want 2/5", wins, matches)
+	}
+}
@@ -2,9 +2,16 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"log"
+	"os"
 	"sync"
+	"time"
 )
 
+// ErrPusherStopped is returned by Start and Push once Wait has closed the push queue
+var ErrPusherStopped = errors.New("turso pusher already stopped")
+
 // DataPusher is an interface for pushing aggregated data to a data store
 type DataPusher interface {
 	PushAggData(ctx context.Context, data *AggData) error
@@ -13,12 +20,32 @@ type DataPusher interface {
 // TursoPusher handles asynchronous, sequential pushes to Turso
 type TursoPusher struct {
 	pusher   DataPusher
-	pushChan chan *AggData
+	pushChan chan queuedPush
 	wg       sync.WaitGroup
+	pending  sync.WaitGroup // Pushes queued or in flight
 	started  bool
-	mu       sync.Mutex
+	stopped  bool         // Set by Wait once the queue is closed
+	mu       sync.RWMutex // Push holds the read lock while sending so Wait can't close mid-send
+
+	maxAttempts int           // Tries per push before giving up
+	retryDelay  time.Duration // Wait before the second try, doubled after each failure
+	dlqDir      string        // Failed pushes are written here once retries run out; empty drops them
 }
 
+// queuedPush is an aggregation waiting in the push queue. With a DLQ, dlqPath is the
+// copy written when it was queued, so a crash before the push lands leaves it for
+// ReplayDLQ; the copy is removed once the push succeeds.
+type queuedPush struct {
+	data    *AggData
+	dlqPath string
+}
+
+// DLQ pushers retry a failed push this many times in total before dead-lettering it
+const (
+	defaultDLQPushAttempts = 3
+	defaultDLQRetryDelay   = 5 * time.Second
+)
+
 // NewTursoPusher creates a new TursoPusher with default buffer size
 func NewTursoPusher(pusher DataPusher) *TursoPusher {
 	return NewTursoPusherWithBuffer(pusher, 10)
@@ -27,23 +54,45 @@ func NewTursoPusher(pusher DataPusher) *TursoPusher {
 // NewTursoPusherWithBuffer creates a new TursoPusher with specified buffer size
 func NewTursoPusherWithBuffer(pusher DataPusher, bufferSize int) *TursoPusher {
 	return &TursoPusher{
-		pusher:   pusher,
-		pushChan: make(chan *AggData, bufferSize),
+		pusher:      pusher,
+		pushChan:    make(chan queuedPush, bufferSize),
+		maxAttempts: 1,
 	}
 }
 
-// Start begins processing pushes in a background goroutine
-func (t *TursoPusher) Start(ctx context.Context) {
+// NewTursoPusherWithDLQ creates a TursoPusher that retries failed pushes and, once
+// retries run out, writes the aggregation to dlqDir for ReplayDLQ to pick up later
+func NewTursoPusherWithDLQ(pusher DataPusher, dlqDir string) *TursoPusher {
+	t := NewTursoPusher(pusher)
+	t.SetDLQ(dlqDir)
+	return t
+}
+
+// SetDLQ turns on retries and dead-lettering to dlqDir, as NewTursoPusherWithDLQ does.
+// Queued pushes are also written there until they land. Call it before Start.
+func (t *TursoPusher) SetDLQ(dlqDir string) {
+	t.maxAttempts = defaultDLQPushAttempts
+	t.retryDelay = defaultDLQRetryDelay
+	t.dlqDir = dlqDir
+}
+
+// Start begins processing pushes in a background goroutine. It reports whether this
+// call started the loop (false if it was already running), and fails after Wait.
+func (t *TursoPusher) Start(ctx context.Context) (bool, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	if t.stopped {
+		return false, ErrPusherStopped
+	}
 	if t.started {
-		return
+		return false, nil
 	}
 	t.started = true
 
 	t.wg.Add(1)
 	go t.processLoop(ctx)
+	return true, nil
 }
 
 // processLoop reads from the channel and processes pushes sequentially
@@ -52,7 +101,7 @@ func (t *TursoPusher) processLoop(ctx context.Context) {
 
 	for {
 		select {
-		case data, ok := <-t.pushChan:
+		case queued, ok := <-t.pushChan:
 			if !ok {
 				// Channel closed, drain any remaining items
 				return
@@ -60,7 +109,8 @@ func (t *TursoPusher) processLoop(ctx context.Context) {
 			// Process the push (blocking, sequential)
 			// We use a background context here to ensure pushes complete
 			// even if the parent context is cancelled
-			_ = t.pusher.PushAggData(context.Background(), data)
+			t.push(queued)
+			t.pending.Done()
 
 		case <-ctx.Done():
 			// Context cancelled, but drain remaining items
@@ -74,38 +124,112 @@ func (t *TursoPusher) processLoop(ctx context.Context) {
 func (t *TursoPusher) drainChannel() {
 	for {
 		select {
-		case data, ok := <-t.pushChan:
+		case queued, ok := <-t.pushChan:
 			if !ok {
 				return
 			}
-			_ = t.pusher.PushAggData(context.Background(), data)
+			t.push(queued)
+			t.pending.Done()
 		default:
 			return
 		}
 	}
 }
 
+// push pushes one aggregation, retrying up to maxAttempts and dead-lettering it if
+// every attempt fails
+func (t *TursoPusher) push(queued queuedPush) {
+	data := queued.data
+	delay := t.retryDelay
+	var err error
+	for attempt := 1; attempt <= t.maxAttempts; attempt++ {
+		if err = t.pusher.PushAggData(context.Background(), data); err == nil {
+			if queued.dlqPath != "" {
+				if rmErr := os.Remove(queued.dlqPath); rmErr != nil {
+					log.Printf("[TursoPusher] Warning: failed to remove queued copy %s: %v", queued.dlqPath, rmErr)
+				}
+			}
+			return
+		}
+		if attempt < t.maxAttempts {
+			log.Printf("[TursoPusher] Push attempt %d/%d failed: %v (retrying in %v)", attempt, t.maxAttempts, err, delay)
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	if t.dlqDir == "" {
+		return
+	}
+	if queued.dlqPath != "" {
+		log.Printf("[TursoPusher] Push failed after %d attempts: %v (saved to %s)", t.maxAttempts, err, queued.dlqPath)
+		return
+	}
+	path, dlqErr := writeDLQFile(t.dlqDir, data)
+	if dlqErr != nil {
+		log.Printf("[TursoPusher] Push failed (%v) and could not be dead-lettered: %v", err, dlqErr)
+		return
+	}
+	log.Printf("[TursoPusher] Push failed after %d attempts: %v (saved to %s)", t.maxAttempts, err, path)
+}
+
 // Push sends data to the push queue. Blocks if the queue is full.
 func (t *TursoPusher) Push(ctx context.Context, data *AggData) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.stopped {
+		return ErrPusherStopped
+	}
+
+	// Keep a copy on disk until the push lands; if that fails, push() still
+	// dead-letters the aggregation should every attempt fail
+	queued := queuedPush{data: data}
+	if t.dlqDir != "" {
+		path, err := writeDLQFile(t.dlqDir, data)
+		if err != nil {
+			log.Printf("[TursoPusher] Warning: failed to save queued push: %v", err)
+		}
+		queued.dlqPath = path
+	}
+
+	t.pending.Add(1)
 	select {
-	case t.pushChan <- data:
+	case t.pushChan <- queued:
 		return nil
 	case <-ctx.Done():
+		t.pending.Done()
+		if queued.dlqPath != "" {
+			os.Remove(queued.dlqPath)
+		}
 		return ctx.Err()
 	}
 }
 
-// Wait blocks until all pending pushes are complete
+// Drain blocks until every queued push has been processed. Unlike Wait, the pusher
+// keeps accepting pushes afterwards.
+func (t *TursoPusher) Drain() {
+	t.mu.RLock()
+	started := t.started
+	t.mu.RUnlock()
+	if !started {
+		return
+	}
+	t.pending.Wait()
+}
+
+// Wait blocks until all pending pushes are complete. The pusher can't be restarted
+// afterwards; calling Wait again is a no-op.
 func (t *TursoPusher) Wait() {
 	t.mu.Lock()
-	if !t.started {
+	if !t.started || t.stopped {
 		t.mu.Unlock()
 		return
 	}
-	t.mu.Unlock()
+	t.stopped = true
 
 	// Close channel to signal no more pushes
 	close(t.pushChan)
+	t.mu.Unlock()
 
 	// Wait for processing to complete
 	t.wg.Wait()
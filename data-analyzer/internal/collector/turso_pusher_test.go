@@ -2,6 +2,10 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -302,3 +306,138 @@ func TestTursoPusher_ContextCancellation(t *testing.T) {
 
 	pusher.Wait()
 }
+
+func TestTursoPusher_StartAfterWaitErrors(t *testing.T) {
+	pusher := NewTursoPusher(&MockTursoClient{})
+	ctx := context.Background()
+
+	if started, err := pusher.Start(ctx); !started || err != nil {
+		t.Fatalf("first Start = %v, %v; want true, nil", started, err)
+	}
+	if started, err := pusher.Start(ctx); started || err != nil {
+		t.Errorf("repeat Start = %v, %v; want false, nil", started, err)
+	}
+
+	pusher.Wait()
+	pusher.Wait() // second Wait must not close the channel again
+
+	if started, err := pusher.Start(ctx); started || !errors.Is(err, ErrPusherStopped) {
+		t.Errorf("Start after Wait = %v, %v; want false, ErrPusherStopped", started, err)
+	}
+	// Push would otherwise panic sending on the closed channel
+	if err := pusher.Push(ctx, newAggData()); !errors.Is(err, ErrPusherStopped) {
+		t.Errorf("Push after Wait = %v, want ErrPusherStopped", err)
+	}
+}
+
+func TestTursoPusher_DeadLettersAndReplays(t *testing.T) {
+	dlqDir := t.TempDir()
+	mock := &MockTursoClient{shouldError: true, lastError: errors.New("turso unavailable")}
+	pusher := NewTursoPusherWithDLQ(mock, dlqDir)
+	pusher.retryDelay = time.Millisecond
+
+	ctx := context.Background()
+	if _, err := pusher.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, patch := range []string{"15.23", "15.24"} {
+		data := newAggData()
+		data.DetectedPatch = patch
+		data.TotalRecords = 10
+		data.ChampionStats[ChampionStatsKey{Patch: patch, ChampionID: 103, TeamPosition: "MIDDLE"}] = &ChampionStats{Wins: 6, Matches: 10}
+		if err := pusher.Push(ctx, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pusher.Wait()
+
+	files, _ := filepath.Glob(filepath.Join(dlqDir, DLQFilePattern))
+	if len(files) != 2 {
+		t.Fatalf("dead-lettered files = %d, want 2", len(files))
+	}
+	if !strings.Contains(filepath.Base(files[0]), "_15.23_") {
+		t.Errorf("file name %s should carry the patch", filepath.Base(files[0]))
+	}
+
+	// Still failing: files stay put
+	if n, err := pusher.ReplayDLQ(ctx); n != 0 || err == nil {
+		t.Errorf("replay while failing = %d, %v; want 0 and an error", n, err)
+	}
+
+	mock.mu.Lock()
+	mock.shouldError = false
+	mock.mu.Unlock()
+
+	n, err := pusher.ReplayDLQ(ctx)
+	if n != 2 || err != nil {
+		t.Fatalf("replay = %d, %v; want 2, nil", n, err)
+	}
+	if order := mock.GetPushOrder(); len(order) != 2 || order[0] != "15.23" || order[1] != "15.24" {
+		t.Errorf("replay order = %v, want oldest first", order)
+	}
+	if left, _ := filepath.Glob(filepath.Join(dlqDir, "*")); len(left) != 0 {
+		t.Errorf("files left after replay: %v", left)
+	}
+}
+
+func TestDLQFile_RoundTrip(t *testing.T) {
+	data := newAggData()
+	data.DetectedPatch = "15.24"
+	data.TotalRecords = 4
+	data.ChampionStats[ChampionStatsKey{Patch: "15.24", ChampionID: 1, TeamPosition: "TOP"}] = &ChampionStats{Wins: 1, Matches: 2}
+	data.MatchupStats[MatchupStatsKey{Patch: "15.24", ChampionID: 1, TeamPosition: "TOP", EnemyChampionID: 2}] = &MatchupStats{Wins: 1, Matches: 2}
+
+	path, err := writeDLQFile(t.TempDir(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := readDLQFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PushID != data.PushID || got.DetectedPatch != "15.24" || got.TotalRecords != 4 {
+		t.Errorf("got push %s patch %s records %d", got.PushID, got.DetectedPatch, got.TotalRecords)
+	}
+	if !reflect.DeepEqual(got.ChampionStats, data.ChampionStats) || !reflect.DeepEqual(got.MatchupStats, data.MatchupStats) {
+		t.Error("stats did not round-trip")
+	}
+}
+
+func TestTursoPusher_QueuedPushSurvivesCrash(t *testing.T) {
+	dlqDir := t.TempDir()
+	ctx := context.Background()
+
+	// Queued but never processed: the process died before the push loop got to it
+	crashed := NewTursoPusherWithDLQ(&MockTursoClient{}, dlqDir)
+	data := newAggData()
+	data.DetectedPatch = "15.24"
+	if err := crashed.Push(ctx, data); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dlqDir, DLQFilePattern)); len(files) != 1 {
+		t.Fatalf("queued copies = %d, want 1", len(files))
+	}
+
+	// The next process replays it
+	mock := &MockTursoClient{}
+	restarted := NewTursoPusherWithDLQ(mock, dlqDir)
+	if n, err := restarted.ReplayDLQ(ctx); n != 1 || err != nil {
+		t.Fatalf("replay = %d, %v; want 1, nil", n, err)
+	}
+
+	// A push that lands removes its copy
+	if _, err := restarted.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := restarted.Push(ctx, newAggData()); err != nil {
+		t.Fatal(err)
+	}
+	restarted.Wait()
+	if left, _ := filepath.Glob(filepath.Join(dlqDir, "*")); len(left) != 0 {
+		t.Errorf("files left after a successful push: %v", left)
+	}
+	if order := mock.GetPushOrder(); len(order) != 2 {
+		t.Errorf("pushes = %v, want the replay and the new push", order)
+	}
+}
@@ -29,6 +29,7 @@ type WarmLock struct {
 	// Read lock metrics
 	readLockCount          int64
 	activeReadLocks        atomic.Int64
+	peakActiveReadLocks    atomic.Int64 // highest activeReadLocks seen since the last reset
 	readLockHoldStartTimes sync.Map // goroutine tracking for hold times
 }
 
@@ -39,6 +40,7 @@ type WarmLockMetrics struct {
 	TotalExclusiveHoldTime  time.Duration
 	ReadLockCount           int64
 	CurrentActiveReadLocks  int64
+	PeakActiveReadLocks     int64
 }
 
 // NewWarmLock creates a new WarmLock
@@ -57,7 +59,7 @@ func (w *WarmLock) RLock() {
 	w.readLockCount++
 	w.metricsLock.Unlock()
 
-	w.activeReadLocks.Add(1)
+	w.recordPeakReadLocks(w.activeReadLocks.Add(1))
 
 	// Store start time for this read lock (keyed by current time as unique ID)
 	// In a real scenario, we'd use goroutine ID, but Go doesn't expose that
@@ -67,6 +69,16 @@ func (w *WarmLock) RLock() {
 	}
 }
 
+// recordPeakReadLocks raises the peak to active if it's a new high
+func (w *WarmLock) recordPeakReadLocks(active int64) {
+	for {
+		peak := w.peakActiveReadLocks.Load()
+		if active <= peak || w.peakActiveReadLocks.CompareAndSwap(peak, active) {
+			return
+		}
+	}
+}
+
 // RUnlock releases a read lock
 func (w *WarmLock) RUnlock() {
 	w.activeReadLocks.Add(-1)
@@ -115,6 +127,7 @@ func (w *WarmLock) Metrics() WarmLockMetrics {
 		TotalExclusiveHoldTime:  w.totalExclusiveHoldTime,
 		ReadLockCount:           w.readLockCount,
 		CurrentActiveReadLocks:  w.activeReadLocks.Load(),
+		PeakActiveReadLocks:     w.peakActiveReadLocks.Load(),
 	}
 }
 
@@ -127,4 +140,5 @@ func (w *WarmLock) ResetMetrics() {
 	w.totalExclusiveWaitTime = 0
 	w.totalExclusiveHoldTime = 0
 	w.readLockCount = 0
+	w.peakActiveReadLocks.Store(w.activeReadLocks.Load())
 }
@@ -144,6 +144,48 @@ func TestWarmLock_ReadMetricsTracking(t *testing.T) {
 	}
 }
 
+func TestWarmLock_PeakActiveReadLocks(t *testing.T) {
+	lock := NewWarmLock()
+
+	const holders = 4
+	acquired := make(chan struct{}, holders)
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+
+	// All holders keep their RLock until every one has acquired it
+	for i := 0; i < holders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lock.RLock()
+			acquired <- struct{}{}
+			<-release
+			lock.RUnlock()
+		}()
+	}
+	for i := 0; i < holders; i++ {
+		<-acquired
+	}
+	close(release)
+	wg.Wait()
+
+	metrics := lock.Metrics()
+	if metrics.PeakActiveReadLocks != holders {
+		t.Errorf("expected peak of %d read locks, got %d", holders, metrics.PeakActiveReadLocks)
+	}
+	if metrics.CurrentActiveReadLocks != 0 {
+		t.Errorf("expected no active read locks, got %d", metrics.CurrentActiveReadLocks)
+	}
+
+	// Reset starts the peak over from the current count
+	lock.ResetMetrics()
+	lock.RLock()
+	lock.RUnlock()
+	if peak := lock.Metrics().PeakActiveReadLocks; peak != 1 {
+		t.Errorf("expected peak of 1 after reset, got %d", peak)
+	}
+}
+
 func TestWarmLock_WaitTimeMetrics(t *testing.T) {
 	lock := NewWarmLock()
 
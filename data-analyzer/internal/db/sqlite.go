@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// NewSQLiteClient opens (or creates) an on-disk SQLite database with the same
+// schema and upsert semantics as Turso, for running the pipeline locally
+func NewSQLiteClient(path string) (*TursoClient, error) {
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite %s: %w", path, err)
+	}
+	// SQLite allows one writer; a single connection avoids SQLITE_BUSY during bulk pushes
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping SQLite %s: %w", path, err)
+	}
+
+	return &TursoClient{db: db}, nil
+}
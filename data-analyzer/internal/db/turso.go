@@ -39,11 +39,20 @@ func NewTursoClient(url, authToken string) (*TursoClient, error) {
 	return &TursoClient{db: db}, nil
 }
 
+// NewTursoClientFromDB wraps an already-open database (e.g. a local SQLite file)
+func NewTursoClientFromDB(db *sql.DB) *TursoClient {
+	return &TursoClient{db: db}
+}
+
 // Close closes the Turso connection
 func (c *TursoClient) Close() error {
 	return c.db.Close()
 }
 
+// SchemaVersion is bumped whenever the stats tables change shape. The desktop app
+// reads it to warn when the database is newer than it understands.
+const SchemaVersion = 4
+
 // CreateTables creates the required tables if they don't exist (without indexes for bulk loading)
 func (c *TursoClient) CreateTables(ctx context.Context) error {
 	queries := []string{
@@ -88,6 +97,58 @@ func (c *TursoClient) CreateTables(ctx context.Context) error {
 			matches INTEGER NOT NULL DEFAULT 0,
 			PRIMARY KEY (patch, champion_id, team_position, enemy_champion_id)
 		)`,
+		`CREATE TABLE IF NOT EXISTS champion_matchup_durations (
+			patch TEXT NOT NULL,
+			champion_id INTEGER NOT NULL,
+			team_position TEXT NOT NULL,
+			enemy_champion_id INTEGER NOT NULL,
+			duration_bucket TEXT NOT NULL,
+			wins INTEGER NOT NULL DEFAULT 0,
+			matches INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (patch, champion_id, team_position, enemy_champion_id, duration_bucket)
+		)`,
+		`CREATE TABLE IF NOT EXISTS champion_ally_pairs (
+			patch TEXT NOT NULL,
+			champion_id INTEGER NOT NULL,
+			ally_champion_id INTEGER NOT NULL,
+			wins INTEGER NOT NULL DEFAULT 0,
+			matches INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (patch, champion_id, ally_champion_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS champion_spells (
+			patch TEXT NOT NULL,
+			champion_id INTEGER NOT NULL,
+			team_position TEXT NOT NULL,
+			spell1_id INTEGER NOT NULL,
+			spell2_id INTEGER NOT NULL,
+			wins INTEGER NOT NULL DEFAULT 0,
+			matches INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (patch, champion_id, team_position, spell1_id, spell2_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS aram_champion_stats (
+			patch TEXT NOT NULL,
+			champion_id INTEGER NOT NULL,
+			wins INTEGER NOT NULL DEFAULT 0,
+			matches INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (patch, champion_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS aram_champion_items (
+			patch TEXT NOT NULL,
+			champion_id INTEGER NOT NULL,
+			item_id INTEGER NOT NULL,
+			wins INTEGER NOT NULL DEFAULT 0,
+			matches INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (patch, champion_id, item_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS push_log (
+			push_id TEXT PRIMARY KEY,
+			pushed_at TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS schema_version (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			version INTEGER NOT NULL
+		)`,
+		fmt.Sprintf(`INSERT OR REPLACE INTO schema_version (id, version) VALUES (1, %d)`, SchemaVersion),
 		// Note: Indexes are created separately via CreateIndexes() for bulk loading optimization
 	}
 
@@ -116,7 +177,7 @@ func (c *TursoClient) ClearData(ctx context.Context) error {
 	}
 	defer tx.Rollback()
 
-	tables := []string{"data_version", "champion_stats", "champion_items", "champion_item_slots", "champion_matchups"}
+	tables := []string{"data_version", "champion_stats", "champion_items", "champion_item_slots", "champion_matchups", "champion_matchup_durations", "champion_ally_pairs", "champion_spells", "aram_champion_stats", "aram_champion_items", "push_log"}
 	for _, table := range tables {
 		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", table)); err != nil {
 			return fmt.Errorf("failed to clear %s: %w", table, err)
@@ -174,6 +235,54 @@ type ChampionMatchup struct {
 	Matches         int
 }
 
+// ChampionMatchupDuration represents a matchup row for one game-length bucket
+type ChampionMatchupDuration struct {
+	Patch           string
+	ChampionID      int
+	TeamPosition    string
+	EnemyChampionID int
+	DurationBucket  string
+	Wins            int
+	Matches         int
+}
+
+// ChampionAllyPair represents two champions on the same team; ChampionID < AllyChampionID
+type ChampionAllyPair struct {
+	Patch          string
+	ChampionID     int
+	AllyChampionID int
+	Wins           int
+	Matches        int
+}
+
+// ChampionSpellPair represents a champion's summoner spell pair; Spell1ID < Spell2ID
+type ChampionSpellPair struct {
+	Patch        string
+	ChampionID   int
+	TeamPosition string
+	Spell1ID     int
+	Spell2ID     int
+	Wins         int
+	Matches      int
+}
+
+// ARAMChampionStat represents champion stats from ARAM games, which have no position
+type ARAMChampionStat struct {
+	Patch      string
+	ChampionID int
+	Wins       int
+	Matches    int
+}
+
+// ARAMChampionItem represents final-item stats from ARAM games
+type ARAMChampionItem struct {
+	Patch      string
+	ChampionID int
+	ItemID     int
+	Wins       int
+	Matches    int
+}
+
 const batchSize = 100 // Reduced to avoid Turso HTTP size limits (502 errors)
 
 // InsertChampionStats inserts champion stats using multi-value INSERT
@@ -188,6 +297,72 @@ func (c *TursoClient) InsertChampionStats(ctx context.Context, stats []ChampionS
 	}
 	defer tx.Rollback()
 
+	if err := insertChampionStats(ctx, tx, stats); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// InsertChampionItems inserts champion items using upsert
+func (c *TursoClient) InsertChampionItems(ctx context.Context, items []ChampionItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertChampionItems(ctx, tx, items); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// InsertChampionItemSlots inserts champion item slots using upsert
+func (c *TursoClient) InsertChampionItemSlots(ctx context.Context, slots []ChampionItemSlot) error {
+	if len(slots) == 0 {
+		return nil
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertChampionItemSlots(ctx, tx, slots); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// InsertChampionMatchups inserts champion matchups using upsert
+func (c *TursoClient) InsertChampionMatchups(ctx context.Context, matchups []ChampionMatchup) error {
+	if len(matchups) == 0 {
+		return nil
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertChampionMatchups(ctx, tx, matchups); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertChampionStats upserts stats within an existing transaction
+func insertChampionStats(ctx context.Context, tx *sql.Tx, stats []ChampionStat) error {
 	for i := 0; i < len(stats); i += batchSize {
 		end := i + batchSize
 		if end > len(stats) {
@@ -216,21 +391,11 @@ func (c *TursoClient) InsertChampionStats(ctx context.Context, stats []ChampionS
 		}
 	}
 
-	return tx.Commit()
+	return nil
 }
 
-// InsertChampionItems inserts champion items using upsert
-func (c *TursoClient) InsertChampionItems(ctx context.Context, items []ChampionItem) error {
-	if len(items) == 0 {
-		return nil
-	}
-
-	tx, err := c.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
+// insertChampionItems upserts items within an existing transaction
+func insertChampionItems(ctx context.Context, tx *sql.Tx, items []ChampionItem) error {
 	for i := 0; i < len(items); i += batchSize {
 		end := i + batchSize
 		if end > len(items) {
@@ -258,21 +423,11 @@ func (c *TursoClient) InsertChampionItems(ctx context.Context, items []ChampionI
 		}
 	}
 
-	return tx.Commit()
+	return nil
 }
 
-// InsertChampionItemSlots inserts champion item slots using upsert
-func (c *TursoClient) InsertChampionItemSlots(ctx context.Context, slots []ChampionItemSlot) error {
-	if len(slots) == 0 {
-		return nil
-	}
-
-	tx, err := c.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
+// insertChampionItemSlots upserts slots within an existing transaction
+func insertChampionItemSlots(ctx context.Context, tx *sql.Tx, slots []ChampionItemSlot) error {
 	for i := 0; i < len(slots); i += batchSize {
 		end := i + batchSize
 		if end > len(slots) {
@@ -300,21 +455,11 @@ func (c *TursoClient) InsertChampionItemSlots(ctx context.Context, slots []Champ
 		}
 	}
 
-	return tx.Commit()
+	return nil
 }
 
-// InsertChampionMatchups inserts champion matchups using upsert
-func (c *TursoClient) InsertChampionMatchups(ctx context.Context, matchups []ChampionMatchup) error {
-	if len(matchups) == 0 {
-		return nil
-	}
-
-	tx, err := c.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
+// insertChampionMatchups upserts matchups within an existing transaction
+func insertChampionMatchups(ctx context.Context, tx *sql.Tx, matchups []ChampionMatchup) error {
 	for i := 0; i < len(matchups); i += batchSize {
 		end := i + batchSize
 		if end > len(matchups) {
@@ -342,7 +487,236 @@ func (c *TursoClient) InsertChampionMatchups(ctx context.Context, matchups []Cha
 		}
 	}
 
-	return tx.Commit()
+	return nil
+}
+
+// insertChampionMatchupDurations upserts duration-split matchups within an existing transaction
+func insertChampionMatchupDurations(ctx context.Context, tx *sql.Tx, matchups []ChampionMatchupDuration) error {
+	for i := 0; i < len(matchups); i += batchSize {
+		end := i + batchSize
+		if end > len(matchups) {
+			end = len(matchups)
+		}
+		batch := matchups[i:end]
+
+		placeholders := make([]string, len(batch))
+		args := make([]interface{}, 0, len(batch)*7)
+
+		for j, m := range batch {
+			placeholders[j] = "(?, ?, ?, ?, ?, ?, ?)"
+			args = append(args, m.Patch, m.ChampionID, m.TeamPosition, m.EnemyChampionID, m.DurationBucket, m.Wins, m.Matches)
+		}
+
+		query := fmt.Sprintf(
+			`INSERT INTO champion_matchup_durations (patch, champion_id, team_position, enemy_champion_id, duration_bucket, wins, matches) VALUES %s
+			ON CONFLICT(patch, champion_id, team_position, enemy_champion_id, duration_bucket) DO UPDATE SET
+				wins = wins + excluded.wins,
+				matches = matches + excluded.matches`,
+			strings.Join(placeholders, ", "))
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertChampionAllyPairs upserts ally pair stats within an existing transaction
+func insertChampionAllyPairs(ctx context.Context, tx *sql.Tx, pairs []ChampionAllyPair) error {
+	for i := 0; i < len(pairs); i += batchSize {
+		end := i + batchSize
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+		batch := pairs[i:end]
+
+		placeholders := make([]string, len(batch))
+		args := make([]interface{}, 0, len(batch)*5)
+
+		for j, p := range batch {
+			placeholders[j] = "(?, ?, ?, ?, ?)"
+			args = append(args, p.Patch, p.ChampionID, p.AllyChampionID, p.Wins, p.Matches)
+		}
+
+		query := fmt.Sprintf(
+			`INSERT INTO champion_ally_pairs (patch, champion_id, ally_champion_id, wins, matches) VALUES %s
+			ON CONFLICT(patch, champion_id, ally_champion_id) DO UPDATE SET
+				wins = wins + excluded.wins,
+				matches = matches + excluded.matches`,
+			strings.Join(placeholders, ", "))
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertChampionSpellPairs upserts summoner spell pair stats within an existing transaction
+func insertChampionSpellPairs(ctx context.Context, tx *sql.Tx, pairs []ChampionSpellPair) error {
+	for i := 0; i < len(pairs); i += batchSize {
+		end := i + batchSize
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+		batch := pairs[i:end]
+
+		placeholders := make([]string, len(batch))
+		args := make([]interface{}, 0, len(batch)*7)
+
+		for j, p := range batch {
+			placeholders[j] = "(?, ?, ?, ?, ?, ?, ?)"
+			args = append(args, p.Patch, p.ChampionID, p.TeamPosition, p.Spell1ID, p.Spell2ID, p.Wins, p.Matches)
+		}
+
+		query := fmt.Sprintf(
+			`INSERT INTO champion_spells (patch, champion_id, team_position, spell1_id, spell2_id, wins, matches) VALUES %s
+			ON CONFLICT(patch, champion_id, team_position, spell1_id, spell2_id) DO UPDATE SET
+				wins = wins + excluded.wins,
+				matches = matches + excluded.matches`,
+			strings.Join(placeholders, ", "))
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertARAMChampionStats upserts ARAM champion stats within an existing transaction
+func insertARAMChampionStats(ctx context.Context, tx *sql.Tx, stats []ARAMChampionStat) error {
+	for i := 0; i < len(stats); i += batchSize {
+		end := i + batchSize
+		if end > len(stats) {
+			end = len(stats)
+		}
+		batch := stats[i:end]
+
+		placeholders := make([]string, len(batch))
+		args := make([]interface{}, 0, len(batch)*4)
+
+		for j, s := range batch {
+			placeholders[j] = "(?, ?, ?, ?)"
+			args = append(args, s.Patch, s.ChampionID, s.Wins, s.Matches)
+		}
+
+		query := fmt.Sprintf(
+			`INSERT INTO aram_champion_stats (patch, champion_id, wins, matches) VALUES %s
+			ON CONFLICT(patch, champion_id) DO UPDATE SET
+				wins = wins + excluded.wins,
+				matches = matches + excluded.matches`,
+			strings.Join(placeholders, ", "))
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertARAMChampionItems upserts ARAM item stats within an existing transaction
+func insertARAMChampionItems(ctx context.Context, tx *sql.Tx, items []ARAMChampionItem) error {
+	for i := 0; i < len(items); i += batchSize {
+		end := i + batchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		batch := items[i:end]
+
+		placeholders := make([]string, len(batch))
+		args := make([]interface{}, 0, len(batch)*5)
+
+		for j, it := range batch {
+			placeholders[j] = "(?, ?, ?, ?, ?)"
+			args = append(args, it.Patch, it.ChampionID, it.ItemID, it.Wins, it.Matches)
+		}
+
+		query := fmt.Sprintf(
+			`INSERT INTO aram_champion_items (patch, champion_id, item_id, wins, matches) VALUES %s
+			ON CONFLICT(patch, champion_id, item_id) DO UPDATE SET
+				wins = wins + excluded.wins,
+				matches = matches + excluded.matches`,
+			strings.Join(placeholders, ", "))
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StatsBatch holds every row written by one aggregation push
+type StatsBatch struct {
+	ChampionStats    []ChampionStat
+	Items            []ChampionItem
+	ItemSlots        []ChampionItemSlot
+	Matchups         []ChampionMatchup
+	MatchupDurations []ChampionMatchupDuration
+	AllyPairs        []ChampionAllyPair
+	SpellPairs       []ChampionSpellPair
+	ARAMStats        []ARAMChampionStat
+	ARAMItems        []ARAMChampionItem
+}
+
+// PushStatsOnce upserts a batch in a single transaction, recording pushID in push_log
+// first. If pushID was already logged (a retry of a push that committed), nothing is
+// written and applied is false. An empty pushID skips the check.
+func (c *TursoClient) PushStatsOnce(ctx context.Context, pushID string, batch StatsBatch) (applied bool, err error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if pushID != "" {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO push_log (push_id, pushed_at) VALUES (?, ?) ON CONFLICT(push_id) DO NOTHING`,
+			pushID, time.Now().UTC().Format(time.RFC3339))
+		if err != nil {
+			return false, fmt.Errorf("failed to record push: %w", err)
+		}
+		if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+			return false, nil
+		}
+	}
+
+	if err := insertChampionStats(ctx, tx, batch.ChampionStats); err != nil {
+		return false, fmt.Errorf("failed to insert champion stats: %w", err)
+	}
+	if err := insertChampionItems(ctx, tx, batch.Items); err != nil {
+		return false, fmt.Errorf("failed to insert champion items: %w", err)
+	}
+	if err := insertChampionItemSlots(ctx, tx, batch.ItemSlots); err != nil {
+		return false, fmt.Errorf("failed to insert champion item slots: %w", err)
+	}
+	if err := insertChampionMatchups(ctx, tx, batch.Matchups); err != nil {
+		return false, fmt.Errorf("failed to insert champion matchups: %w", err)
+	}
+	if err := insertChampionMatchupDurations(ctx, tx, batch.MatchupDurations); err != nil {
+		return false, fmt.Errorf("failed to insert champion matchup durations: %w", err)
+	}
+	if err := insertChampionAllyPairs(ctx, tx, batch.AllyPairs); err != nil {
+		return false, fmt.Errorf("failed to insert champion ally pairs: %w", err)
+	}
+	if err := insertChampionSpellPairs(ctx, tx, batch.SpellPairs); err != nil {
+		return false, fmt.Errorf("failed to insert champion spell pairs: %w", err)
+	}
+	if err := insertARAMChampionStats(ctx, tx, batch.ARAMStats); err != nil {
+		return false, fmt.Errorf("failed to insert ARAM champion stats: %w", err)
+	}
+	if err := insertARAMChampionItems(ctx, tx, batch.ARAMItems); err != nil {
+		return false, fmt.Errorf("failed to insert ARAM champion items: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // GetDataVersion returns the current data version from the database
@@ -367,6 +741,9 @@ var indexDefinitions = []string{
 	`CREATE INDEX IF NOT EXISTS idx_champion_item_slots_champ_pos_slot ON champion_item_slots(champion_id, team_position, build_slot)`,
 	`CREATE INDEX IF NOT EXISTS idx_champion_matchups_champ_pos ON champion_matchups(champion_id, team_position)`,
 	`CREATE INDEX IF NOT EXISTS idx_champion_matchups_enemy ON champion_matchups(champion_id, team_position, enemy_champion_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_champion_matchup_durations_enemy ON champion_matchup_durations(champion_id, team_position, enemy_champion_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_champion_ally_pairs_pair ON champion_ally_pairs(champion_id, ally_champion_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_champion_spells_champ_pos ON champion_spells(champion_id, team_position)`,
 }
 
 var indexNames = []string{
@@ -377,6 +754,9 @@ var indexNames = []string{
 	"idx_champion_item_slots_champ_pos_slot",
 	"idx_champion_matchups_champ_pos",
 	"idx_champion_matchups_enemy",
+	"idx_champion_matchup_durations_enemy",
+	"idx_champion_ally_pairs_pair",
+	"idx_champion_spells_champ_pos",
 }
 
 // DropIndexes drops all indexes for faster bulk inserts
@@ -413,7 +793,7 @@ func (c *TursoClient) DeleteOldPatches(ctx context.Context, minPatch string) (in
 	}
 	defer tx.Rollback()
 
-	tables := []string{"champion_stats", "champion_items", "champion_item_slots", "champion_matchups"}
+	tables := []string{"champion_stats", "champion_items", "champion_item_slots", "champion_matchups", "champion_matchup_durations", "champion_ally_pairs", "champion_spells", "aram_champion_stats", "aram_champion_items"}
 	var totalDeleted int64
 
 	for _, table := range tables {
@@ -23,6 +23,12 @@ const (
 	minRequestInterval = 50 * time.Millisecond // Max ~20 req/sec
 )
 
+// Queue IDs used when fetching match history
+const (
+	QueueRankedSolo = 420
+	QueueARAM       = 450
+)
+
 // Client is a Riot API client that handles 429 rate limit responses
 type Client struct {
 	apiKey       string
@@ -181,10 +187,15 @@ func (c *Client) GetAccountByPUUID(ctx context.Context, puuid string) (*AccountR
 	return &account, err
 }
 
-// GetMatchHistory fetches match IDs for a player
+// GetMatchHistory fetches ranked solo queue match IDs for a player
 func (c *Client) GetMatchHistory(ctx context.Context, puuid string, count int) ([]string, error) {
-	url := fmt.Sprintf("%s/lol/match/v5/matches/by-puuid/%s/ids?queue=420&count=%d",
-		americasBaseURL, puuid, count)
+	return c.GetMatchHistoryForQueue(ctx, puuid, QueueRankedSolo, count)
+}
+
+// GetMatchHistoryForQueue fetches match IDs for a player in a single queue
+func (c *Client) GetMatchHistoryForQueue(ctx context.Context, puuid string, queue, count int) ([]string, error) {
+	url := fmt.Sprintf("%s/lol/match/v5/matches/by-puuid/%s/ids?queue=%d&count=%d",
+		americasBaseURL, puuid, queue, count)
 
 	var matchIDs []string
 	err := c.doRequest(ctx, url, &matchIDs)
@@ -34,7 +34,10 @@ type MatchParticipant struct {
 	ChampionID     int    `json:"championId"`
 	ChampionName   string `json:"championName"`
 	TeamPosition   string `json:"teamPosition"` // TOP, JUNGLE, MIDDLE, BOTTOM, UTILITY
+	TeamID         int    `json:"teamId"`       // 100 (blue) or 200 (red)
 	Win            bool   `json:"win"`
+	Summoner1ID    int    `json:"summoner1Id"`
+	Summoner2ID    int    `json:"summoner2Id"`
 	Item0          int    `json:"item0"`
 	Item1          int    `json:"item1"`
 	Item2          int    `json:"item2"`
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compressor creates writers and readers for a cold storage compression format
+type Compressor interface {
+	// Extension is appended to archived filenames (e.g. ".gz")
+	Extension() string
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// GzipCompressor compresses cold files with gzip (the default)
+type GzipCompressor struct{}
+
+// Extension returns ".gz"
+func (GzipCompressor) Extension() string { return ".gz" }
+
+// NewWriter returns a gzip writer
+func (GzipCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+// NewReader returns a gzip reader
+func (GzipCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// ZstdCompressor compresses cold files with zstd (smaller and faster for JSONL)
+type ZstdCompressor struct{}
+
+// Extension returns ".zst"
+func (ZstdCompressor) Extension() string { return ".zst" }
+
+// NewWriter returns a zstd encoder
+func (ZstdCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w)
+}
+
+// NewReader returns a zstd decoder
+func (ZstdCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	dec, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return dec.IOReadCloser(), nil
+}
+
+// DefaultCompressor is used when no compressor is configured
+var DefaultCompressor Compressor = GzipCompressor{}
+
+// CompressorByName returns the compressor for "gzip" or "zstd" (empty means default)
+func CompressorByName(name string) (Compressor, error) {
+	switch strings.ToLower(name) {
+	case "", "gzip", "gz":
+		return GzipCompressor{}, nil
+	case "zstd", "zst":
+		return ZstdCompressor{}, nil
+	default:
+		return nil, fmt.Errorf("unknown compression format: %q", name)
+	}
+}
+
+// CompressorForPath detects the compressor from a file's extension.
+// Returns nil for uncompressed files.
+func CompressorForPath(path string) Compressor {
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		return GzipCompressor{}
+	case strings.HasSuffix(path, ".zst"):
+		return ZstdCompressor{}
+	default:
+		return nil
+	}
+}
+
+// OpenMaybeCompressed opens a plain or compressed (.gz/.zst) file for reading.
+// Closing the returned reader closes the underlying file.
+func OpenMaybeCompressed(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := CompressorForPath(path)
+	if c == nil {
+		return file, nil
+	}
+
+	r, err := c.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return &compressedFile{ReadCloser: r, file: file}, nil
+}
+
+// compressedFile closes both the decompressor and the underlying file
+type compressedFile struct {
+	io.ReadCloser
+	file *os.File
+}
+
+func (c *compressedFile) Close() error {
+	err := c.ReadCloser.Close()
+	if ferr := c.file.Close(); err == nil {
+		err = ferr
+	}
+	return err
+}
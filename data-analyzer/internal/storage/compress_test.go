@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Test: gzip and zstd round-trip the same data to identical content
+func TestCompressors_RoundTrip(t *testing.T) {
+	content := strings.Repeat(`{"matchId":"NA1_123","championId":1,"teamPosition":"MIDDLE","win":true}`+"\n", 200)
+
+	for _, c := range []Compressor{GzipCompressor{}, ZstdCompressor{}} {
+		t.Run(c.Extension(), func(t *testing.T) {
+			warmDir := t.TempDir()
+			coldDir := t.TempDir()
+
+			warmPath := filepath.Join(warmDir, "matches.jsonl")
+			if err := os.WriteFile(warmPath, []byte(content), 0644); err != nil {
+				t.Fatalf("failed to write warm file: %v", err)
+			}
+
+			if err := CompressToColdWith(warmPath, coldDir, c); err != nil {
+				t.Fatalf("CompressToColdWith failed: %v", err)
+			}
+
+			coldPath := filepath.Join(coldDir, "matches.jsonl"+c.Extension())
+			r, err := OpenMaybeCompressed(coldPath)
+			if err != nil {
+				t.Fatalf("OpenMaybeCompressed failed: %v", err)
+			}
+			defer r.Close()
+
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("failed to read cold file: %v", err)
+			}
+			if string(got) != content {
+				t.Errorf("decompressed content differs: got %d bytes, want %d", len(got), len(content))
+			}
+		})
+	}
+}
+
+// Test: compressor is detected from the file extension
+func TestCompressorForPath(t *testing.T) {
+	if _, ok := CompressorForPath("a.jsonl.gz").(GzipCompressor); !ok {
+		t.Error("expected gzip for .gz")
+	}
+	if _, ok := CompressorForPath("a.jsonl.zst").(ZstdCompressor); !ok {
+		t.Error("expected zstd for .zst")
+	}
+	if CompressorForPath("a.jsonl") != nil {
+		t.Error("expected nil for uncompressed file")
+	}
+}
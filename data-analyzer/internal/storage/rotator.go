@@ -2,7 +2,6 @@ package storage
 
 import (
 	"bufio"
-	"compress/gzip"
 	json "github.com/goccy/go-json"
 	"fmt"
 	"io"
@@ -34,6 +33,10 @@ type FileRotator struct {
 	matchCount    int
 	fileOpenedAt  time.Time
 
+	// Rotation limits (default MaxMatchesPerFile / MaxFileAge)
+	maxMatches int
+	maxAge     time.Duration
+
 	// Callback when a file is rotated to warm (optional)
 	onRotateToWarm func()
 }
@@ -52,9 +55,11 @@ func NewFileRotator(baseDir string) (*FileRotator, error) {
 	}
 
 	r := &FileRotator{
-		hotDir:  hotDir,
-		warmDir: warmDir,
-		coldDir: coldDir,
+		hotDir:     hotDir,
+		warmDir:    warmDir,
+		coldDir:    coldDir,
+		maxMatches: MaxMatchesPerFile,
+		maxAge:     MaxFileAge,
 	}
 
 	// Open initial file
@@ -76,6 +81,19 @@ func (r *FileRotator) SetColdDir(path string) error {
 	return nil
 }
 
+// SetRotationLimits overrides how many matches or how much time a hot file may hold
+// before rotating to warm. Non-positive values keep the current limit.
+func (r *FileRotator) SetRotationLimits(maxMatches int, maxAge time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if maxMatches > 0 {
+		r.maxMatches = maxMatches
+	}
+	if maxAge > 0 {
+		r.maxAge = maxAge
+	}
+}
+
 // SetOnRotateCallback sets a callback that fires when a file is rotated to warm storage.
 // Used by ContinuousCollector to track warm file count and trigger reduce cycles.
 func (r *FileRotator) SetOnRotateCallback(callback func()) {
@@ -134,10 +152,10 @@ func (r *FileRotator) shouldRotate() bool {
 	if r.currentFile == nil {
 		return true
 	}
-	if r.matchCount >= MaxMatchesPerFile {
+	if r.matchCount >= r.maxMatches {
 		return true
 	}
-	if time.Since(r.fileOpenedAt) >= MaxFileAge {
+	if time.Since(r.fileOpenedAt) >= r.maxAge {
 		return true
 	}
 	return false
@@ -187,7 +205,9 @@ func (r *FileRotator) rotate() error {
 	return nil
 }
 
-// Close flushes and closes the current file
+// Close flushes and closes the current file, moving it to warm if it holds any data
+// (including records from a partially written match) so nothing is stranded in hot.
+// An empty hot file is removed.
 func (r *FileRotator) Close() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -200,12 +220,17 @@ func (r *FileRotator) Close() error {
 		return err
 	}
 
+	info, err := r.currentFile.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat current file: %w", err)
+	}
+
 	if err := r.currentFile.Close(); err != nil {
 		return err
 	}
 
 	// Move to warm if it has data
-	if r.matchCount > 0 {
+	if r.matchCount > 0 || info.Size() > 0 {
 		warmPath := filepath.Join(r.warmDir, filepath.Base(r.currentPath))
 		if err := os.Rename(r.currentPath, warmPath); err != nil {
 			return err
@@ -299,8 +324,13 @@ func (r *FileRotator) FlushAndRotate() (rotated bool, err error) {
 	return true, nil
 }
 
-// CompressToCold compresses a warm file and moves it to cold storage
+// CompressToCold compresses a warm file with the default compressor and moves it to cold storage
 func CompressToCold(warmPath, coldDir string) error {
+	return CompressToColdWith(warmPath, coldDir, DefaultCompressor)
+}
+
+// CompressToColdWith compresses a warm file using c and moves it to cold storage
+func CompressToColdWith(warmPath, coldDir string, c Compressor) error {
 	// Open source file
 	src, err := os.Open(warmPath)
 	if err != nil {
@@ -308,7 +338,7 @@ func CompressToCold(warmPath, coldDir string) error {
 	}
 
 	// Create compressed file
-	filename := filepath.Base(warmPath) + ".gz"
+	filename := filepath.Base(warmPath) + c.Extension()
 	coldPath := filepath.Join(coldDir, filename)
 	dst, err := os.Create(coldPath)
 	if err != nil {
@@ -317,13 +347,18 @@ func CompressToCold(warmPath, coldDir string) error {
 	}
 
 	// Compress
-	gzWriter := gzip.NewWriter(dst)
-	if _, err := io.Copy(gzWriter, src); err != nil {
+	writer, err := c.NewWriter(dst)
+	if err != nil {
+		src.Close()
+		dst.Close()
+		return err
+	}
+	if _, err := io.Copy(writer, src); err != nil {
 		src.Close()
 		dst.Close()
 		return err
 	}
-	if err := gzWriter.Close(); err != nil {
+	if err := writer.Close(); err != nil {
 		src.Close()
 		dst.Close()
 		return err
@@ -3,6 +3,7 @@ package storage
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -252,3 +253,64 @@ func TestFlushAndRotate_ReturnsMatchCount(t *testing.T) {
 		t.Error("expected rotated=true for file with matches")
 	}
 }
+
+// Test 1.1: Close moves a partially written match to warm
+func TestClose_FlushesPartialMatchToWarm(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	r, err := NewFileRotator(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create rotator: %v", err)
+	}
+
+	// Write 3 of 10 participants without completing the match
+	for i := 0; i < 3; i++ {
+		if err := r.WriteLine(&RawMatch{MatchID: "PARTIAL_1", ChampionID: i + 1, TeamPosition: "TOP"}); err != nil {
+			t.Fatalf("failed to write record: %v", err)
+		}
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	hotFiles, _ := filepath.Glob(filepath.Join(tmpDir, "hot", "*.jsonl"))
+	if len(hotFiles) != 0 {
+		t.Errorf("expected hot to be empty, found %d files", len(hotFiles))
+	}
+
+	warmFiles, _ := filepath.Glob(filepath.Join(tmpDir, "warm", "*.jsonl"))
+	if len(warmFiles) != 1 {
+		t.Fatalf("expected 1 warm file, found %d", len(warmFiles))
+	}
+	data, err := os.ReadFile(warmFiles[0])
+	if err != nil {
+		t.Fatalf("failed to read warm file: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 3 {
+		t.Errorf("warm file lines: got %d, want 3", lines)
+	}
+}
+
+// Test 1.1: Close with an empty hot file leaves warm untouched
+func TestClose_EmptyHotFileIsNoop(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	r, err := NewFileRotator(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create rotator: %v", err)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	warmFiles, _ := filepath.Glob(filepath.Join(tmpDir, "warm", "*.jsonl"))
+	if len(warmFiles) != 0 {
+		t.Errorf("expected no warm files, found %d", len(warmFiles))
+	}
+	hotFiles, _ := filepath.Glob(filepath.Join(tmpDir, "hot", "*.jsonl"))
+	if len(hotFiles) != 0 {
+		t.Errorf("expected empty hot file to be removed, found %d", len(hotFiles))
+	}
+}
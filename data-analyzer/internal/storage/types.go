@@ -8,6 +8,7 @@ type RawMatch struct {
 	GameVersion  string `json:"gameVersion"`
 	GameDuration int    `json:"gameDuration"`
 	GameCreation int64  `json:"gameCreation"`
+	QueueID      int    `json:"queueId,omitempty"` // 420 ranked solo, 450 ARAM; missing in older records
 
 	// Participant data
 	PUUID        string `json:"puuid"`
@@ -15,9 +16,14 @@ type RawMatch struct {
 	TagLine      string `json:"tagLine,omitempty"`
 	ChampionID   int    `json:"championId"`
 	ChampionName string `json:"championName"`
-	TeamPosition string `json:"teamPosition"` // TOP, JUNGLE, MIDDLE, BOTTOM, UTILITY
+	TeamPosition string `json:"teamPosition"`     // TOP, JUNGLE, MIDDLE, BOTTOM, UTILITY
+	TeamID       int    `json:"teamId,omitempty"` // 100 or 200; missing in older records
 	Win          bool   `json:"win"`
 
+	// Summoner spells (missing in older records)
+	Summoner1ID int `json:"summoner1Id,omitempty"`
+	Summoner2ID int `json:"summoner2Id,omitempty"`
+
 	// Final items (used for item stats and build inference)
 	Item0 int `json:"item0"`
 	Item1 int `json:"item1"`
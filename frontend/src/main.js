@@ -86,6 +86,11 @@ document.querySelector('#app').innerHTML = `
                     <div class="counterpicks-list" id="counterpicks-list"></div>
                 </div>
 
+                <div class="counterpicks-card hidden" id="enemycounters-card">
+                    <div class="counterpicks-header">Counters vs Enemy Team</div>
+                    <div class="enemycounters-list" id="enemycounters-list"></div>
+                </div>
+
                 <div class="build-card hidden" id="build-card">
                     <div class="build-role" id="build-role"></div>
                     <div class="build-matchup">
@@ -156,6 +161,9 @@ const bansList = document.getElementById('bans-list');
 const counterpicksCard = document.getElementById('counterpicks-card');
 const counterpicksSubheader = document.getElementById('counterpicks-subheader');
 const counterpicksList = document.getElementById('counterpicks-list');
+const enemycountersCard = document.getElementById('enemycounters-card');
+const enemycountersList = document.getElementById('enemycounters-list');
+let hasEnemyCounters = false;
 const buildCard = document.getElementById('build-card');
 const buildRole = document.getElementById('build-role');
 const buildWinrate = document.getElementById('build-winrate');
@@ -299,7 +307,7 @@ function renderMetaRoleContent(role) {
                 <div class="meta-champ-row clickable" data-champ-id="${c.championId}" data-role="${role}">
                     <span class="meta-rank">${idx + 1}</span>
                     <img class="meta-icon" src="${c.iconURL}" alt="${c.championName}" />
-                    <span class="meta-name">${c.championName}</span>
+                    <span class="meta-name">${c.championName}${c.provisional ? ' <span class="meta-provisional" title="Small sample">?</span>' : ''}</span>
                     <span class="meta-pr">${c.pickRate.toFixed(1)}%</span>
                     <span class="meta-wr winning">${c.winRate.toFixed(1)}%</span>
                 </div>
@@ -469,6 +477,34 @@ function loadChampionDetails(championId, role) {
 // Make showMetaTierList available globally for onclick
 window.showMetaTierList = showMetaTierList;
 
+// Render the full meta table (all roles) in one pass
+function renderMetaTable(data) {
+    metaHeader.textContent = `Top Champions - Patch ${data.patch}`;
+    metaDataLoaded = true;
+    currentMetaData = data;
+
+    metaContent.innerHTML = `
+        <div id="meta-tier-list">
+            ${renderMetaRoleTabs()}
+            <div id="meta-role-content">
+                ${renderMetaRoleContent(currentMetaRole)}
+            </div>
+        </div>
+        <div id="meta-details-view" class="hidden">
+            <div id="meta-champion-details"></div>
+        </div>
+    `;
+
+    setupMetaRoleTabHandlers();
+    setupMetaChampionClickHandlers();
+}
+
+// Backend pushes the whole meta table once stats load or the patch changes
+function onMetaReady(data) {
+    if (!data || !data.hasData) return;
+    renderMetaTable(data);
+}
+
 // Load and display meta champions
 function loadMetaData() {
     if (metaDataLoaded) {
@@ -493,24 +529,7 @@ function loadMetaData() {
                 return;
             }
 
-            metaHeader.textContent = `Top Champions - Patch ${data.patch}`;
-            metaDataLoaded = true;
-            currentMetaData = data;
-
-            metaContent.innerHTML = `
-                <div id="meta-tier-list">
-                    ${renderMetaRoleTabs()}
-                    <div id="meta-role-content">
-                        ${renderMetaRoleContent(currentMetaRole)}
-                    </div>
-                </div>
-                <div id="meta-details-view" class="hidden">
-                    <div id="meta-champion-details"></div>
-                </div>
-            `;
-
-            setupMetaRoleTabHandlers();
-            setupMetaChampionClickHandlers();
+            renderMetaTable(data);
         })
         .catch(err => {
             console.error('Failed to load meta data:', err);
@@ -645,7 +664,13 @@ function formatRole(role) {
 // Update connection status
 function updateStatus(status) {
     statusMessage.textContent = status.message;
-    statusDot.className = status.connected ? 'status-dot connected' : 'status-dot waiting';
+    if (status.connected) {
+        statusDot.className = 'status-dot connected';
+    } else if (status.state === 'connecting') {
+        statusDot.className = 'status-dot connecting';
+    } else {
+        statusDot.className = 'status-dot waiting';
+    }
 }
 
 // Update gameflow state
@@ -1009,6 +1034,7 @@ function updateChampSelect(data) {
         teamcompCard.classList.add('hidden');
         bansCard.classList.add('hidden');
         counterpicksCard.classList.add('hidden');
+        enemycountersCard.classList.add('hidden');
         return;
     }
 
@@ -1016,9 +1042,11 @@ function updateChampSelect(data) {
     if (data.banPhaseComplete) {
         bansCard.classList.add('hidden');
         counterpicksCard.classList.remove('hidden');
+        enemycountersCard.classList.toggle('hidden', !hasEnemyCounters);
     } else {
         bansCard.classList.remove('hidden');
         counterpicksCard.classList.add('hidden');
+        enemycountersCard.classList.add('hidden');
     }
 }
 
@@ -1218,6 +1246,38 @@ function updateCounterPicks(data) {
     counterpicksList.innerHTML = html;
 }
 
+// Update counter picks against every visible enemy (shown after ban phase)
+function updateEnemyCounters(data) {
+    const enemies = (data && data.hasData && data.enemies) || [];
+    hasEnemyCounters = enemies.some(enemy => enemy.counters && enemy.counters.length > 0);
+    if (!hasEnemyCounters) {
+        enemycountersList.innerHTML = '';
+        enemycountersCard.classList.add('hidden');
+        return;
+    }
+
+    let html = '';
+    for (const enemy of enemies) {
+        if (!enemy.counters || enemy.counters.length === 0) continue;
+        html += `<div class="counterpicks-subheader">vs ${enemy.championName}</div>`;
+        for (const pick of enemy.counters) {
+            const wr = typeof pick.winRate === 'number' ? pick.winRate.toFixed(1) : pick.winRate;
+            html += `
+                <div class="counterpick-row">
+                    <img class="counterpick-icon" src="${pick.iconURL}" alt="${pick.championName}" />
+                    <span class="counterpick-name">${pick.championName}</span>
+                    <span class="counterpick-wr winning">${wr}%</span>
+                    <span class="counterpick-games">${pick.games}</span>
+                </div>
+            `;
+        }
+    }
+    enemycountersList.innerHTML = html;
+    if (!counterpicksCard.classList.contains('hidden')) {
+        enemycountersCard.classList.remove('hidden');
+    }
+}
+
 // Event listeners
 EventsOn('lcu:status', updateStatus);
 EventsOn('champselect:update', updateChampSelect);
@@ -1227,11 +1287,13 @@ EventsOn('teamcomp:update', updateTeamComp);
 EventsOn('fullcomp:update', updateFullComp);
 EventsOn('items:update', updateItems);
 EventsOn('counterpicks:update', updateCounterPicks);
+EventsOn('enemycounters:update', updateEnemyCounters);
 EventsOn('gameflow:update', updateGameflow);
 EventsOn('ingame:build', updateInGameBuild);
 EventsOn('ingame:scouting', updateScouting);
 EventsOn('gold:update', updateGoldBox);
 EventsOn('goldbox:show', onGoldBoxShow);
+EventsOn('meta:ready', onMetaReady);
 
 // Get initial status
 GetConnectionStatus()
@@ -3,26 +3,62 @@
 import {main} from '../models';
 import {lcu} from '../models';
 
+export function ClearAllCaches():Promise<string>;
+
+export function DiagnoseLCU():Promise<main.LCUDiagnostic>;
+
+export function ExportPersonalStatsCard():Promise<string>;
+
 export function ForceStatsUpdate():Promise<string>;
 
+export function GetChampSelectHistory():Promise<Array<main.ChampSelectSnapshot>>;
+
+export function GetChampSelectSummary(arg1:number,arg2:string,arg3:Array<number>):Promise<main.ChampSelectSummary>;
+
 export function GetChampionBuild(arg1:number,arg2:string):Promise<main.ChampionBuildData>;
 
+export function GetChampionBuildForPatch(arg1:number,arg2:string,arg3:string):Promise<main.ChampionBuildData>;
+
 export function GetChampionDetails(arg1:number,arg2:string):Promise<main.ChampionDetails>;
 
+export function GetChampionPoolReport():Promise<main.ChampionPoolReport>;
+
 export function GetConnectionStatus():Promise<Record<string, any>>;
 
 export function GetGameflowPhase():Promise<Record<string, any>>;
 
 export function GetGoldDiff():Promise<Record<string, any>>;
 
+export function GetItemTooltip(arg1:number):Promise<lcu.ItemTooltip>;
+
 export function GetMetaChampions():Promise<main.MetaData>;
 
 export function GetPersonalStats():Promise<lcu.PersonalStats>;
 
+export function GetRecommendedSpells(arg1:number,arg2:string,arg3:number):Promise<main.SpellRecommendation>;
+
 export function HideForGame():Promise<void>;
 
+export function RecordChampSelectSnapshot():Promise<main.SnapshotResult>;
+
 export function RegisterToggleHotkey():Promise<void>;
 
+export function SetBuildItemOrder(arg1:string):Promise<void>;
+
+export function SetEmitAllMatchups(arg1:boolean):Promise<void>;
+
+export function SetEmitRawCounts(arg1:boolean):Promise<void>;
+
+export function SetMatchupBand(arg1:number,arg2:number):Promise<void>;
+
+export function SetMinLaneEnemies(arg1:number):Promise<void>;
+
+export function SetSituationalItemOptions(arg1:number):Promise<void>;
+
+export function SetStatsMaxAge(arg1:number):Promise<void>;
+
 export function ShowAfterGame():Promise<void>;
 
 export function ToggleWindow():Promise<void>;
+
+export function UpdateStats():Promise<main.StatsUpdateResult>;
@@ -2,18 +2,46 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ClearAllCaches() {
+  return window['go']['main']['App']['ClearAllCaches']();
+}
+
+export function DiagnoseLCU() {
+  return window['go']['main']['App']['DiagnoseLCU']();
+}
+
+export function ExportPersonalStatsCard() {
+  return window['go']['main']['App']['ExportPersonalStatsCard']();
+}
+
 export function ForceStatsUpdate() {
   return window['go']['main']['App']['ForceStatsUpdate']();
 }
 
+export function GetChampSelectHistory() {
+  return window['go']['main']['App']['GetChampSelectHistory']();
+}
+
+export function GetChampSelectSummary(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetChampSelectSummary'](arg1, arg2, arg3);
+}
+
 export function GetChampionBuild(arg1, arg2) {
   return window['go']['main']['App']['GetChampionBuild'](arg1, arg2);
 }
 
+export function GetChampionBuildForPatch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetChampionBuildForPatch'](arg1, arg2, arg3);
+}
+
 export function GetChampionDetails(arg1, arg2) {
   return window['go']['main']['App']['GetChampionDetails'](arg1, arg2);
 }
 
+export function GetChampionPoolReport() {
+  return window['go']['main']['App']['GetChampionPoolReport']();
+}
+
 export function GetConnectionStatus() {
   return window['go']['main']['App']['GetConnectionStatus']();
 }
@@ -26,6 +54,10 @@ export function GetGoldDiff() {
   return window['go']['main']['App']['GetGoldDiff']();
 }
 
+export function GetItemTooltip(arg1) {
+  return window['go']['main']['App']['GetItemTooltip'](arg1);
+}
+
 export function GetMetaChampions() {
   return window['go']['main']['App']['GetMetaChampions']();
 }
@@ -34,14 +66,50 @@ export function GetPersonalStats() {
   return window['go']['main']['App']['GetPersonalStats']();
 }
 
+export function GetRecommendedSpells(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetRecommendedSpells'](arg1, arg2, arg3);
+}
+
 export function HideForGame() {
   return window['go']['main']['App']['HideForGame']();
 }
 
+export function RecordChampSelectSnapshot() {
+  return window['go']['main']['App']['RecordChampSelectSnapshot']();
+}
+
 export function RegisterToggleHotkey() {
   return window['go']['main']['App']['RegisterToggleHotkey']();
 }
 
+export function SetBuildItemOrder(arg1) {
+  return window['go']['main']['App']['SetBuildItemOrder'](arg1);
+}
+
+export function SetEmitAllMatchups(arg1) {
+  return window['go']['main']['App']['SetEmitAllMatchups'](arg1);
+}
+
+export function SetEmitRawCounts(arg1) {
+  return window['go']['main']['App']['SetEmitRawCounts'](arg1);
+}
+
+export function SetMatchupBand(arg1, arg2) {
+  return window['go']['main']['App']['SetMatchupBand'](arg1, arg2);
+}
+
+export function SetMinLaneEnemies(arg1) {
+  return window['go']['main']['App']['SetMinLaneEnemies'](arg1);
+}
+
+export function SetSituationalItemOptions(arg1) {
+  return window['go']['main']['App']['SetSituationalItemOptions'](arg1);
+}
+
+export function SetStatsMaxAge(arg1) {
+  return window['go']['main']['App']['SetStatsMaxAge'](arg1);
+}
+
 export function ShowAfterGame() {
   return window['go']['main']['App']['ShowAfterGame']();
 }
@@ -49,3 +117,7 @@ export function ShowAfterGame() {
 export function ToggleWindow() {
   return window['go']['main']['App']['ToggleWindow']();
 }
+
+export function UpdateStats() {
+  return window['go']['main']['App']['UpdateStats']();
+}
@@ -40,6 +40,28 @@ export namespace lcu {
 	        this.avgCSPerMin = source["avgCSPerMin"];
 	    }
 	}
+	export class ItemTooltip {
+	    itemId: number;
+	    name: string;
+	    plaintext: string;
+	    description: string;
+	    cost: number;
+	    known: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ItemTooltip(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.itemId = source["itemId"];
+	        this.name = source["name"];
+	        this.plaintext = source["plaintext"];
+	        this.description = source["description"];
+	        this.cost = source["cost"];
+	        this.known = source["known"];
+	    }
+	}
 	export class PersonalStats {
 	    hasData: boolean;
 	    totalGames: number;
@@ -161,14 +183,143 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class ChampSelectBan {
+	    championId: number;
+	    championName: string;
+	    iconURL: string;
+	    damageType: string;
+	    winRate: number;
+	    games: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChampSelectBan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.championId = source["championId"];
+	        this.championName = source["championName"];
+	        this.iconURL = source["iconURL"];
+	        this.damageType = source["damageType"];
+	        this.winRate = source["winRate"];
+	        this.games = source["games"];
+	    }
+	}
+	export class ChampSelectMatchup {
+	    hasOpponent: boolean;
+	    enemyId: number;
+	    enemyName: string;
+	    enemyIcon: string;
+	    winRate: number;
+	    games: number;
+	    status: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChampSelectMatchup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hasOpponent = source["hasOpponent"];
+	        this.enemyId = source["enemyId"];
+	        this.enemyName = source["enemyName"];
+	        this.enemyIcon = source["enemyIcon"];
+	        this.winRate = source["winRate"];
+	        this.games = source["games"];
+	        this.status = source["status"];
+	    }
+	}
+	export class ChampSelectSnapshot {
+	    recordedAt: number;
+	    summary: ChampSelectSummary;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChampSelectSnapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.recordedAt = source["recordedAt"];
+	        this.summary = this.convertValues(source["summary"], ChampSelectSummary);
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice && a.map) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+	}
+	export class ChampSelectSummary {
+	    hasData: boolean;
+	    championId: number;
+	    championName: string;
+	    role: string;
+	    patch: string;
+	    build: ChampionBuildData;
+	    matchup: ChampSelectMatchup;
+	    counters: ChampionDetailMatchup[];
+	    bans: ChampSelectBan[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ChampSelectSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hasData = source["hasData"];
+	        this.championId = source["championId"];
+	        this.championName = source["championName"];
+	        this.role = source["role"];
+	        this.patch = source["patch"];
+	        this.build = this.convertValues(source["build"], ChampionBuildData);
+	        this.matchup = this.convertValues(source["matchup"], ChampSelectMatchup);
+	        this.counters = this.convertValues(source["counters"], ChampionDetailMatchup);
+	        this.bans = this.convertValues(source["bans"], ChampSelectBan);
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice && a.map) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+	}
 	export class ChampionBuildData {
 	    hasItems: boolean;
 	    championName: string;
 	    championId: number;
 	    role: string;
+	    resolvedRole: string;
 	    iconURL: string;
 	    splashURL: string;
+	    games: number;
+	    source: string;
 	    builds: BuildPath[];
+	    patch?: string;
+	    patchFallback?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new ChampionBuildData(source);
@@ -180,9 +331,14 @@ export namespace main {
 	        this.championName = source["championName"];
 	        this.championId = source["championId"];
 	        this.role = source["role"];
+	        this.resolvedRole = source["resolvedRole"];
 	        this.iconURL = source["iconURL"];
 	        this.splashURL = source["splashURL"];
+	        this.games = source["games"];
+	        this.source = source["source"];
 	        this.builds = this.convertValues(source["builds"], BuildPath);
+	        this.patch = source["patch"];
+	        this.patchFallback = source["patchFallback"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -291,6 +447,86 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class ChampionPoolEntry {
+	    championId: number;
+	    championName: string;
+	    iconURL: string;
+	    role: string;
+	    games: number;
+	    winRate: number;
+	    metaRank: number;
+	    metaWinRate: number;
+	    weakThisPatch: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChampionPoolEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.championId = source["championId"];
+	        this.championName = source["championName"];
+	        this.iconURL = source["iconURL"];
+	        this.role = source["role"];
+	        this.games = source["games"];
+	        this.winRate = source["winRate"];
+	        this.metaRank = source["metaRank"];
+	        this.metaWinRate = source["metaWinRate"];
+	        this.weakThisPatch = source["weakThisPatch"];
+	    }
+	}
+	export class ChampionPoolReport {
+	    hasData: boolean;
+	    patch: string;
+	    champions: ChampionPoolEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ChampionPoolReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hasData = source["hasData"];
+	        this.patch = source["patch"];
+	        this.champions = this.convertValues(source["champions"], ChampionPoolEntry);
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice && a.map) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+	}
+	export class LCUDiagnostic {
+	    connected: boolean;
+	    lockfileFound: boolean;
+	    loggedIn: boolean;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LCUDiagnostic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connected = source["connected"];
+	        this.lockfileFound = source["lockfileFound"];
+	        this.loggedIn = source["loggedIn"];
+	        this.reason = source["reason"];
+	    }
+	}
 	export class MetaChampion {
 	    championId: number;
 	    championName: string;
@@ -298,6 +534,10 @@ export namespace main {
 	    winRate: number;
 	    pickRate: number;
 	    games: number;
+	    wins: number;
+	    confidence: number;
+	    provisional: boolean;
+	    tier: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new MetaChampion(source);
@@ -311,6 +551,10 @@ export namespace main {
 	        this.winRate = source["winRate"];
 	        this.pickRate = source["pickRate"];
 	        this.games = source["games"];
+	        this.wins = source["wins"];
+	        this.confidence = source["confidence"];
+	        this.provisional = source["provisional"];
+	        this.tier = source["tier"];
 	    }
 	}
 	export class MetaData {
@@ -348,5 +592,87 @@ export namespace main {
 		}
 	}
 
+	export class SnapshotResult {
+	    success: boolean;
+	    snapshot: ChampSelectSnapshot;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SnapshotResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.snapshot = this.convertValues(source["snapshot"], ChampSelectSnapshot);
+	        this.error = source["error"];
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice && a.map) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+	}
+	export class SpellRecommendation {
+	    spell1Id: number;
+	    spell1Name: string;
+	    spell2Id: number;
+	    spell2Name: string;
+	    winRate: number;
+	    games: number;
+	    note?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SpellRecommendation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.spell1Id = source["spell1Id"];
+	        this.spell1Name = source["spell1Name"];
+	        this.spell2Id = source["spell2Id"];
+	        this.spell2Name = source["spell2Name"];
+	        this.winRate = source["winRate"];
+	        this.games = source["games"];
+	        this.note = source["note"];
+	        this.error = source["error"];
+	    }
+	}
+	export class StatsUpdateResult {
+	    success: boolean;
+	    changed: boolean;
+	    patch: string;
+	    previousPatch: string;
+	    inProgress?: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatsUpdateResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.changed = source["changed"];
+	        this.patch = source["patch"];
+	        this.previousPatch = source["previousPatch"];
+	        this.inProgress = source["inProgress"];
+	        this.error = source["error"];
+	    }
+	}
 }
 
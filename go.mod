@@ -9,6 +9,7 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/tursodatabase/libsql-client-go v0.0.0-20251219100830-236aa1ff8acc
 	github.com/wailsapp/wails/v2 v2.11.0
+	golang.org/x/sync v0.17.0
 	modernc.org/sqlite v1.42.2
 )
 
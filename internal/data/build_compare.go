@@ -0,0 +1,64 @@
+package data
+
+// BuildDelta compares a player's finished items with the recommended build
+type BuildDelta struct {
+	Matched     []int // recommended items the player built
+	MissedCore  []int // core items the player never finished
+	Suboptimal  []int // finished items outside the core and every situational option
+	Recommended []int // core items, in build order, for reference
+}
+
+// ComparePlayerBuild diffs a player's final items (e.g. from LCU match history) against
+// the recommended build for the champion in a role. isCompleted picks out finished items
+// (e.g. the item registry's IsCompleted); components, consumables, starters and trinkets
+// it rejects are ignored.
+func (p *StatsProvider) ComparePlayerBuild(championID int, role string, playerItems []int, isCompleted func(itemID int) bool) (*BuildDelta, error) {
+	build, err := p.FetchChampionData(championID, "", role)
+	if err != nil {
+		return nil, err
+	}
+	return compareBuild(build.Builds[0], playerItems, isCompleted), nil
+}
+
+// compareBuild diffs player items against one build path
+func compareBuild(build BuildPath, playerItems []int, isCompleted func(itemID int) bool) *BuildDelta {
+	delta := &BuildDelta{Recommended: build.CoreItems}
+
+	owned := make(map[int]bool)
+	for _, itemID := range playerItems {
+		if isCompleted(itemID) {
+			owned[itemID] = true
+		}
+	}
+
+	recommended := make(map[int]bool)
+	for _, itemID := range build.CoreItems {
+		recommended[itemID] = true
+		if owned[itemID] {
+			delta.Matched = append(delta.Matched, itemID)
+		} else {
+			delta.MissedCore = append(delta.MissedCore, itemID)
+		}
+	}
+	for _, options := range [][]ItemOption{build.FourthItemOptions, build.FifthItemOptions, build.SixthItemOptions} {
+		for _, opt := range options {
+			if recommended[opt.ItemID] {
+				continue
+			}
+			recommended[opt.ItemID] = true
+			if owned[opt.ItemID] {
+				delta.Matched = append(delta.Matched, opt.ItemID)
+			}
+		}
+	}
+
+	// Keep the player's inventory order for items off the recommended list
+	seen := make(map[int]bool)
+	for _, itemID := range playerItems {
+		if owned[itemID] && !recommended[itemID] && !seen[itemID] {
+			seen[itemID] = true
+			delta.Suboptimal = append(delta.Suboptimal, itemID)
+		}
+	}
+	return delta
+}
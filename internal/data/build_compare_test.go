@@ -0,0 +1,64 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+// completedItems stands in for the item registry's Data Dragon metadata
+var completedItems = map[int]bool{
+	6655: true, // Luden's Companion
+	3089: true, // Rabadon's Deathcap
+	3020: true, // Sorcerer's Shoes
+	3135: true, // Void Staff
+	3157: true, // Zhonya's Hourglass
+	3116: true, // Rylai's Crystal Scepter
+}
+
+func isCompletedStub(itemID int) bool { return completedItems[itemID] }
+
+func TestComparePlayerBuild_ReportsMissedCoreItem(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'MIDDLE', 50, 100)`)
+	// Core: Luden's then Rabadon's, Sorcerer's Shoes as boots; Void Staff or Zhonya's 4th
+	mustExec(t, db, `INSERT INTO champion_item_slots VALUES ('15.24', 103, 'MIDDLE', 6655, 1, 50, 90)`)
+	mustExec(t, db, `INSERT INTO champion_item_slots VALUES ('15.24', 103, 'MIDDLE', 3089, 2, 45, 80)`)
+	mustExec(t, db, `INSERT INTO champion_item_slots VALUES ('15.24', 103, 'MIDDLE', 3020, 2, 40, 85)`)
+	mustExec(t, db, `INSERT INTO champion_item_slots VALUES ('15.24', 103, 'MIDDLE', 3135, 4, 20, 40)`)
+	mustExec(t, db, `INSERT INTO champion_item_slots VALUES ('15.24', 103, 'MIDDLE', 3157, 4, 15, 30)`)
+
+	// No Luden's; Rylai's instead. The ward, Doran's Ring and empty slot are ignored.
+	delta, err := provider.ComparePlayerBuild(103, "middle", []int{3089, 3020, 3157, 3116, 1056, 0, 3340}, isCompletedStub)
+	if err != nil {
+		t.Fatalf("ComparePlayerBuild: %v", err)
+	}
+
+	if !reflect.DeepEqual(delta.MissedCore, []int{6655}) {
+		t.Errorf("MissedCore = %v, want [6655]", delta.MissedCore)
+	}
+	if !reflect.DeepEqual(delta.Matched, []int{3089, 3020, 3157}) {
+		t.Errorf("Matched = %v, want [3089 3020 3157]", delta.Matched)
+	}
+	if !reflect.DeepEqual(delta.Suboptimal, []int{3116}) {
+		t.Errorf("Suboptimal = %v, want [3116]", delta.Suboptimal)
+	}
+
+	if _, err := provider.ComparePlayerBuild(1, "middle", []int{3089}, isCompletedStub); err == nil {
+		t.Error("champion without data should error")
+	}
+}
+
+func TestCompareBuild_IgnoresHeldComponents(t *testing.T) {
+	build := BuildPath{CoreItems: []int{6655, 3089}}
+
+	// Game ended holding Fiendish Codex and Sheen; IDs above 3000, but still components
+	delta := compareBuild(build, []int{6655, 3108, 3057, 3116}, isCompletedStub)
+
+	if !reflect.DeepEqual(delta.Suboptimal, []int{3116}) {
+		t.Errorf("Suboptimal = %v, want [3116]", delta.Suboptimal)
+	}
+	if !reflect.DeepEqual(delta.MissedCore, []int{3089}) {
+		t.Errorf("MissedCore = %v, want [3089]", delta.MissedCore)
+	}
+}
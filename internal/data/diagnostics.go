@@ -0,0 +1,117 @@
+package data
+
+import "fmt"
+
+// OrphanItemStat identifies a champion_items row with no matching champion_stats row
+type OrphanItemStat struct {
+	ChampionID   int
+	TeamPosition string
+	ItemID       int
+	Matches      int
+}
+
+// FindOrphanItemStats returns item stats for a patch whose (champion, position)
+// has no champion_stats entry. These rows break pick-rate joins and can be deleted.
+func (p *StatsProvider) FindOrphanItemStats(patch string) ([]OrphanItemStat, error) {
+	rows, err := p.db().Query(`
+		SELECT ci.champion_id, ci.team_position, ci.item_id, ci.matches
+		FROM champion_items ci
+		LEFT JOIN champion_stats cs
+			ON cs.patch = ci.patch
+			AND cs.champion_id = ci.champion_id
+			AND cs.team_position = ci.team_position
+		WHERE ci.patch = ? AND cs.champion_id IS NULL
+		ORDER BY ci.champion_id, ci.team_position, ci.item_id
+	`, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orphan item stats: %w", err)
+	}
+	defer rows.Close()
+
+	var orphans []OrphanItemStat
+	for rows.Next() {
+		var o OrphanItemStat
+		if err := rows.Scan(&o.ChampionID, &o.TeamPosition, &o.ItemID, &o.Matches); err != nil {
+			return nil, fmt.Errorf("failed to scan orphan item stat: %w", err)
+		}
+		orphans = append(orphans, o)
+	}
+
+	return orphans, rows.Err()
+}
+
+// CoveragePair is a champion/position combination and its sample size
+type CoveragePair struct {
+	ChampionID   int
+	TeamPosition string
+	Matches      int
+}
+
+// DataCoverage summarizes which champion/position combos have enough games to trust
+type DataCoverage struct {
+	Patch        string
+	MinGames     int
+	UnderCovered []CoveragePair // Pairs below MinGames, fewest games first
+	CoveredCount int            // Pairs with at least MinGames
+}
+
+// CoverageReport lists champion/position pairs on a patch with fewer than minGames
+// games and counts the pairs that meet the floor.
+func (p *StatsProvider) CoverageReport(patch string, minGames int) (*DataCoverage, error) {
+	rows, err := p.db().Query(`
+		SELECT champion_id, team_position, SUM(matches) as matches
+		FROM champion_stats
+		WHERE patch = ?
+		GROUP BY champion_id, team_position
+		ORDER BY matches ASC, champion_id, team_position
+	`, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query coverage: %w", err)
+	}
+	defer rows.Close()
+
+	report := &DataCoverage{Patch: patch, MinGames: minGames}
+	for rows.Next() {
+		var c CoveragePair
+		if err := rows.Scan(&c.ChampionID, &c.TeamPosition, &c.Matches); err != nil {
+			return nil, fmt.Errorf("failed to scan coverage row: %w", err)
+		}
+		if c.Matches < minGames {
+			report.UnderCovered = append(report.UnderCovered, c)
+		} else {
+			report.CoveredCount++
+		}
+	}
+
+	return report, rows.Err()
+}
+
+// RoleWinRate is a champion's win rate in one position from champion_stats,
+// summed across every stored patch
+type RoleWinRate struct {
+	ChampionID   int
+	TeamPosition string
+	Wins         int
+	Games        int
+	WinRate      float64
+}
+
+// FetchRoleWinRate reads a champion's win rate in a role from champion_stats
+func (p *StatsProvider) FetchRoleWinRate(championID int, role string) (*RoleWinRate, error) {
+	position := roleToPosition(role)
+	result := &RoleWinRate{ChampionID: championID, TeamPosition: position}
+
+	err := p.db().QueryRow(`
+		SELECT COALESCE(SUM(wins), 0), COALESCE(SUM(matches), 0)
+		FROM champion_stats
+		WHERE champion_id = ? AND team_position = ?
+	`, championID, position).Scan(&result.Wins, &result.Games)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query champion win rate: %w", err)
+	}
+
+	if result.Games > 0 {
+		result.WinRate = float64(result.Wins) / float64(result.Games) * 100
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package data
+
+import "testing"
+
+func TestFindOrphanItemStats(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 1, 'TOP', 50, 100)`)
+	mustExec(t, db, `INSERT INTO champion_items VALUES ('15.24', 1, 'TOP', 3071, 30, 60)`)   // valid
+	mustExec(t, db, `INSERT INTO champion_items VALUES ('15.24', 1, 'JUNGLE', 3071, 5, 10)`) // orphan: no JUNGLE stats
+	mustExec(t, db, `INSERT INTO champion_items VALUES ('15.23', 2, 'MIDDLE', 3089, 5, 10)`) // other patch
+
+	orphans, err := provider.FindOrphanItemStats("15.24")
+	if err != nil {
+		t.Fatalf("FindOrphanItemStats: %v", err)
+	}
+
+	if len(orphans) != 1 {
+		t.Fatalf("got %d orphans, want 1: %+v", len(orphans), orphans)
+	}
+	want := OrphanItemStat{ChampionID: 1, TeamPosition: "JUNGLE", ItemID: 3071, Matches: 10}
+	if orphans[0] != want {
+		t.Errorf("got %+v, want %+v", orphans[0], want)
+	}
+}
+
+func TestCoverageReport(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 1, 'TOP', 60, 120)`)    // covered
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 2, 'MIDDLE', 50, 100)`) // covered (at floor)
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 3, 'JUNGLE', 20, 40)`)  // under
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 4, 'UTILITY', 2, 5)`)   // under
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.23', 5, 'TOP', 1, 1)`)       // other patch
+
+	report, err := provider.CoverageReport("15.24", 100)
+	if err != nil {
+		t.Fatalf("CoverageReport: %v", err)
+	}
+
+	if report.CoveredCount != 2 {
+		t.Errorf("covered: got %d, want 2", report.CoveredCount)
+	}
+	want := []CoveragePair{
+		{ChampionID: 4, TeamPosition: "UTILITY", Matches: 5},
+		{ChampionID: 3, TeamPosition: "JUNGLE", Matches: 40},
+	}
+	if len(report.UnderCovered) != len(want) {
+		t.Fatalf("under-covered: got %+v, want %+v", report.UnderCovered, want)
+	}
+	for i := range want {
+		if report.UnderCovered[i] != want[i] {
+			t.Errorf("under-covered[%d]: got %+v, want %+v", i, report.UnderCovered[i], want[i])
+		}
+	}
+}
+
+func TestFetchRoleWinRate(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'MIDDLE', 50, 100)`)
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.23', 103, 'MIDDLE', 30, 50)`)
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'TOP', 1, 10)`) // other role
+
+	got, err := provider.FetchRoleWinRate(103, "MID")
+	if err != nil {
+		t.Fatalf("FetchRoleWinRate: %v", err)
+	}
+	if got.Wins != 80 || got.Games != 150 {
+		t.Errorf("got %d/%d, want 80/150", got.Wins, got.Games)
+	}
+	if got.TeamPosition != "MIDDLE" {
+		t.Errorf("position = %q, want MIDDLE", got.TeamPosition)
+	}
+}
@@ -3,15 +3,30 @@ package data
 import (
 	"database/sql"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"ghostdraft/internal/roles"
 )
 
 // Minimum games threshold for using current patch only
 // If current patch has fewer games, fallback to aggregated data
 const minGamesForCurrentPatch = 1000
 
+// DefaultCounterPickMinGames is the sample floor for counter picks so
+// low-sample outliers don't top the list
+const DefaultCounterPickMinGames = 30
+
+// DefaultSituationalItemOptions is how many choices are offered for the 4th/5th/6th item slots
+const DefaultSituationalItemOptions = 3
+
 // ItemOption holds item ID with win rate
 type ItemOption struct {
 	ItemID   int
+	Wins     int
 	WinRate  float64
 	PickRate float64 // % of games this item was chosen in this slot (calculated from sampled data)
 	Games    int
@@ -24,23 +39,36 @@ type BuildPath struct {
 	Games             int
 	StartingItems     []int
 	CoreItems         []int
+	CoreItemStats     []ItemOption // win rate and games per core item, in CoreItems order
 	FourthItemOptions []ItemOption
 	FifthItemOptions  []ItemOption
 	SixthItemOptions  []ItemOption
 }
 
+// BuildSourceLocal marks builds computed from our own collected match data
+const BuildSourceLocal = "local"
+
 // BuildData holds champion build information
 type BuildData struct {
 	ChampionID   int
 	ChampionName string
 	Role         string
+	ResolvedRole string // role the data actually came from; differs from Role on fallback
+	Games        int    // sample size behind the build win rate
+	Source       string // where the build came from (BuildSourceLocal)
 	Builds       []BuildPath
+
+	// Set by FetchChampionDataForPatch only; aggregated builds leave them empty
+	Patch      string // patch the build was computed from
+	ExactPatch bool   // false when Patch is the closest stored patch to the one requested
 }
 
 // StatsProvider fetches build data from Turso with caching
 type StatsProvider struct {
 	client       *TursoClient
 	currentPatch string
+
+	situationalOptions atomic.Int32 // options per 4th/5th/6th slot (0 means default)
 }
 
 // ItemStat represents aggregated item statistics
@@ -60,13 +88,34 @@ type MatchupStat struct {
 	WinRate         float64
 }
 
+// wilsonZ is the z-score for a 99% confidence interval. The stricter bound keeps
+// 20-30 game spikes from outranking matchups with hundreds of games.
+const wilsonZ = 2.576
+
+// WilsonLowerBound returns the lower bound of the 99% Wilson score interval for the
+// win rate, as a percentage. Small samples are pulled toward 0, so a solid record
+// outranks a lucky streak.
+func WilsonLowerBound(wins, games int) float64 {
+	if games <= 0 {
+		return 0
+	}
+	n := float64(games)
+	p := float64(wins) / n
+	z2 := wilsonZ * wilsonZ
+	center := p + z2/(2*n)
+	margin := wilsonZ * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	return (center - margin) / (1 + z2/n) * 100
+}
+
 // ChampionWinRate holds champion win rate data for meta display
 type ChampionWinRate struct {
-	ChampionID int
-	Wins       int
-	Matches    int
-	WinRate    float64
-	PickRate   float64
+	ChampionID  int
+	Wins        int
+	Matches     int
+	WinRate     float64
+	PickRate    float64
+	Confidence  float64 // Wilson lower bound of WinRate; set by the confidence-sorted meta
+	Provisional bool    // fewer than MetaProvisionalGames; set by the confidence-sorted meta
 }
 
 // NewStatsProvider creates a new stats provider from a TursoClient
@@ -76,6 +125,23 @@ func NewStatsProvider(client *TursoClient) (*StatsProvider, error) {
 	}, nil
 }
 
+// SetSituationalItemOptions sets how many options are returned for the 4th/5th/6th item
+// slots. Values <= 0 restore the default.
+func (p *StatsProvider) SetSituationalItemOptions(count int) {
+	if count <= 0 {
+		count = DefaultSituationalItemOptions
+	}
+	p.situationalOptions.Store(int32(count))
+}
+
+// SituationalItemOptions returns how many options are returned per situational slot
+func (p *StatsProvider) SituationalItemOptions() int {
+	if n := p.situationalOptions.Load(); n > 0 {
+		return int(n)
+	}
+	return DefaultSituationalItemOptions
+}
+
 // Close is a no-op since the TursoClient owns the connection
 func (p *StatsProvider) Close() {
 	// Connection owned by TursoClient
@@ -83,7 +149,9 @@ func (p *StatsProvider) Close() {
 
 // ClearCache clears the query cache
 func (p *StatsProvider) ClearCache() {
-	p.client.ClearCache()
+	if p.client != nil {
+		p.client.ClearCache()
+	}
 }
 
 // db returns the underlying database connection
@@ -118,9 +186,28 @@ func (p *StatsProvider) FetchPatch() error {
 	p.currentPatch = patch
 	p.cache().Set("current_patch", patch)
 	fmt.Printf("[Stats] Using patch: %s\n", patch)
+
+	if version, newer := p.CheckSchemaVersion(); newer {
+		fmt.Printf("[Stats] Warning: stats database schema v%d is newer than supported v%d; update the app\n",
+			version, supportedSchemaVersion)
+	}
 	return nil
 }
 
+// supportedSchemaVersion is the stats schema this build was written against.
+// Queries name their columns, so a newer schema that only adds columns still works.
+const supportedSchemaVersion = 4
+
+// CheckSchemaVersion returns the database's schema version and whether it is newer
+// than this build supports. Databases without a schema_version table report 0.
+func (p *StatsProvider) CheckSchemaVersion() (int, bool) {
+	var version int
+	if err := p.db().QueryRow(`SELECT version FROM schema_version WHERE id = 1`).Scan(&version); err != nil {
+		return 0, false
+	}
+	return version, version > supportedSchemaVersion
+}
+
 // GetPatch returns the current patch
 func (p *StatsProvider) GetPatch() string {
 	return p.currentPatch
@@ -147,21 +234,7 @@ func (p *StatsProvider) GetMostPlayedRole(championID int) string {
 	}
 
 	// Convert database position back to role
-	var role string
-	switch position {
-	case "TOP":
-		role = "top"
-	case "JUNGLE":
-		role = "jungle"
-	case "MIDDLE":
-		role = "middle"
-	case "BOTTOM":
-		role = "bottom"
-	case "UTILITY":
-		role = "utility"
-	default:
-		role = ""
-	}
+	role := roles.Parse(position).String()
 
 	if role != "" {
 		p.cache().Set(cacheKey, role)
@@ -169,27 +242,19 @@ func (p *StatsProvider) GetMostPlayedRole(championID int) string {
 	return role
 }
 
-// roleToPosition converts role names to database team_position values
+// roleToPosition converts role names to database team_position values. It accepts the
+// app's labels (MID, ADC, SUPPORT...) in any case; unrecognised roles default to MIDDLE.
 func roleToPosition(role string) string {
-	switch role {
-	case "top":
-		return "TOP"
-	case "jungle":
-		return "JUNGLE"
-	case "middle", "mid":
-		return "MIDDLE"
-	case "bottom", "adc":
-		return "BOTTOM"
-	case "utility", "support":
-		return "UTILITY"
-	default:
-		return "MIDDLE"
+	if r := roles.Parse(role); r != roles.Unknown {
+		return r.Position()
 	}
+	fmt.Printf("[Stats] Warning: unrecognised role %q, defaulting to %s\n", role, roles.Middle.Position())
+	return roles.Middle.Position()
 }
 
 // FetchChampionData gets build data for a champion from Turso with caching
 func (p *StatsProvider) FetchChampionData(championID int, championName string, role string) (*BuildData, error) {
-	cacheKey := fmt.Sprintf("build:%d:%s", championID, role)
+	cacheKey := fmt.Sprintf("build:%d:%s:%d", championID, role, p.SituationalItemOptions())
 	if cached, ok := p.cache().Get(cacheKey); ok {
 		return cached.(*BuildData), nil
 	}
@@ -203,12 +268,80 @@ func (p *StatsProvider) FetchChampionData(championID int, championName string, r
 		WHERE champion_id = ? AND team_position = ?
 	`, championID, position).Scan(&totalGames)
 
+	// No games in the requested role: fall back to the champion's most-played role
+	if err == nil && totalGames == 0 {
+		if fallback := p.GetMostPlayedRole(championID); fallback != "" && roleToPosition(fallback) != position {
+			position = roleToPosition(fallback)
+			err = p.db().QueryRow(`
+				SELECT COALESCE(SUM(matches), 0) FROM champion_stats
+				WHERE champion_id = ? AND team_position = ?
+			`, championID, position).Scan(&totalGames)
+		}
+	}
+
 	if err != nil || totalGames == 0 {
 		return nil, fmt.Errorf("no data for champion %d in position %s", championID, position)
 	}
 
 	// Build the response using slot-based data
-	build, err := p.constructBuildPathFromSlots(championID, position, totalGames)
+	build, err := p.constructBuildPathFromSlots(championID, position, "", totalGames)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BuildData{
+		ChampionID:   championID,
+		ChampionName: championName,
+		Role:         role,
+		ResolvedRole: roles.Parse(position).String(),
+		Games:        totalGames,
+		Source:       BuildSourceLocal,
+		Builds:       []BuildPath{build},
+	}
+
+	p.cache().Set(cacheKey, result)
+	return result, nil
+}
+
+// FetchChampionDataForPatch gets build data computed from a single patch. If the database
+// has no games for the champion on that patch, the closest stored patch is used instead
+// and the result's ExactPatch is false.
+func (p *StatsProvider) FetchChampionDataForPatch(championID int, championName string, role string, patch string) (*BuildData, error) {
+	cacheKey := fmt.Sprintf("build_patch:%d:%s:%s:%d", championID, role, patch, p.SituationalItemOptions())
+	if cached, ok := p.cache().Get(cacheKey); ok {
+		return cached.(*BuildData), nil
+	}
+
+	position := roleToPosition(role)
+
+	rows, err := p.db().Query(`
+		SELECT patch, SUM(matches) FROM champion_stats
+		WHERE champion_id = ? AND team_position = ?
+		GROUP BY patch
+		HAVING SUM(matches) > 0
+	`, championID, position)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query patches: %w", err)
+	}
+	defer rows.Close()
+
+	games := make(map[string]int)
+	var patches []string
+	for rows.Next() {
+		var stored string
+		var matches int
+		if err := rows.Scan(&stored, &matches); err != nil {
+			continue
+		}
+		games[stored] = matches
+		patches = append(patches, stored)
+	}
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("no data for champion %d in position %s", championID, position)
+	}
+
+	served := closestPatch(patch, patches)
+	build, err := p.constructBuildPathFromSlots(championID, position, served, games[served])
 	if err != nil {
 		return nil, err
 	}
@@ -217,15 +350,59 @@ func (p *StatsProvider) FetchChampionData(championID int, championName string, r
 		ChampionID:   championID,
 		ChampionName: championName,
 		Role:         role,
+		ResolvedRole: roles.Parse(position).String(),
+		Games:        games[served],
+		Source:       BuildSourceLocal,
 		Builds:       []BuildPath{build},
+		Patch:        served,
+		ExactPatch:   served == patch,
 	}
 
 	p.cache().Set(cacheKey, result)
 	return result, nil
 }
 
-// constructBuildPathFromSlots creates a build path using item slot data
-func (p *StatsProvider) constructBuildPathFromSlots(championID int, position string, totalGames int) (BuildPath, error) {
+// closestPatch returns want if it is stored, otherwise the newest stored patch older
+// than want, otherwise the oldest stored patch (every stored patch is newer)
+func closestPatch(want string, stored []string) string {
+	sort.Slice(stored, func(i, j int) bool { return comparePatches(stored[i], stored[j]) < 0 })
+	closest := stored[0]
+	for _, patch := range stored {
+		cmp := comparePatches(patch, want)
+		if cmp == 0 {
+			return patch
+		}
+		if cmp < 0 {
+			closest = patch
+		}
+	}
+	return closest
+}
+
+// comparePatches orders "major.minor" patch strings numerically, so 15.10 sorts after 15.9
+func comparePatches(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// constructBuildPathFromSlots creates a build path using item slot data. An empty patch
+// aggregates across every stored patch.
+func (p *StatsProvider) constructBuildPathFromSlots(championID int, position string, patch string, totalGames int) (BuildPath, error) {
 	// Track excluded items (already used in build)
 	excluded := make(map[int]bool)
 
@@ -241,9 +418,10 @@ func (p *StatsProvider) constructBuildPathFromSlots(championID int, position str
 				CAST(SUM(matches) AS REAL) / SUM(SUM(matches)) OVER () * 100 as pick_rate
 			FROM champion_item_slots
 			WHERE champion_id = ? AND team_position = ? AND build_slot = ?
+			AND (? = '' OR patch = ?)
 			GROUP BY item_id
 			ORDER BY SUM(matches) DESC
-		`, championID, position, slot)
+		`, championID, position, slot, patch, patch)
 		if err != nil {
 			return nil, err
 		}
@@ -271,6 +449,7 @@ func (p *StatsProvider) constructBuildPathFromSlots(championID int, position str
 				}
 				items = append(items, ItemOption{
 					ItemID:   itemID,
+					Wins:     wins,
 					WinRate:  float64(wins) / float64(matches) * 100,
 					PickRate: pickRate,
 					Games:    matches,
@@ -284,20 +463,22 @@ func (p *StatsProvider) constructBuildPathFromSlots(championID int, position str
 	}
 
 	// Get best boots across all slots
-	var bestBoots int
+	var bestBoots, bootsWins, bootsGames int
 	bootsRow := p.db().QueryRow(`
-		SELECT item_id
+		SELECT item_id, SUM(wins), SUM(matches)
 		FROM champion_item_slots
 		WHERE champion_id = ? AND team_position = ?
 		AND item_id IN (3006, 3009, 3020, 3047, 3111, 3117, 3158)
+		AND (? = '' OR patch = ?)
 		GROUP BY item_id
 		ORDER BY SUM(matches) DESC
 		LIMIT 1
-	`, championID, position)
-	bootsRow.Scan(&bestBoots)
+	`, championID, position, patch, patch)
+	bootsRow.Scan(&bestBoots, &bootsWins, &bootsGames)
 
 	// Get 2 core items (slots 1, 2, 3 - excluding boots and duplicates)
 	var coreItemIDs []int
+	var coreStats []ItemOption
 	var winRate float64
 	for slot := 1; slot <= 3; slot++ {
 		if len(coreItemIDs) >= 2 {
@@ -309,6 +490,7 @@ func (p *StatsProvider) constructBuildPathFromSlots(championID int, position str
 		}
 		if len(items) > 0 {
 			coreItemIDs = append(coreItemIDs, items[0].ItemID)
+			coreStats = append(coreStats, items[0])
 			excluded[items[0].ItemID] = true
 			if len(coreItemIDs) == 1 {
 				winRate = items[0].WinRate
@@ -319,6 +501,11 @@ func (p *StatsProvider) constructBuildPathFromSlots(championID int, position str
 	// Add best boots to core items
 	if bestBoots > 0 {
 		coreItemIDs = append(coreItemIDs, bestBoots)
+		boots := ItemOption{ItemID: bestBoots, Wins: bootsWins, Games: bootsGames}
+		if bootsGames > 0 {
+			boots.WinRate = float64(bootsWins) / float64(bootsGames) * 100
+		}
+		coreStats = append(coreStats, boots)
 		excluded[bestBoots] = true
 	}
 
@@ -327,10 +514,11 @@ func (p *StatsProvider) constructBuildPathFromSlots(championID int, position str
 		excluded[bootsID] = true
 	}
 
-	// Get 4th, 5th, 6th item options (3 choices each, excluding core and boots)
-	fourthItems, _ := getSlotItems(4, 3, true)
-	fifthItems, _ := getSlotItems(5, 3, true)
-	sixthItems, _ := getSlotItems(6, 3, true)
+	// Get 4th, 5th, 6th item options (excluding core and boots)
+	optionCount := p.SituationalItemOptions()
+	fourthItems, _ := getSlotItems(4, optionCount, true)
+	fifthItems, _ := getSlotItems(5, optionCount, true)
+	sixthItems, _ := getSlotItems(6, optionCount, true)
 
 	return BuildPath{
 		Name:              "Recommended Build",
@@ -338,6 +526,7 @@ func (p *StatsProvider) constructBuildPathFromSlots(championID int, position str
 		Games:             totalGames,
 		StartingItems:     nil,
 		CoreItems:         coreItemIDs,
+		CoreItemStats:     coreStats,
 		FourthItemOptions: fourthItems,
 		FifthItemOptions:  fifthItems,
 		SixthItemOptions:  sixthItems,
@@ -355,6 +544,7 @@ func (p *StatsProvider) toItemOptions(items []ItemStat, start, count int) []Item
 	for i := start; i < end; i++ {
 		options = append(options, ItemOption{
 			ItemID:  items[i].ItemID,
+			Wins:    items[i].Wins,
 			WinRate: items[i].WinRate,
 			Games:   items[i].Matches,
 		})
@@ -447,6 +637,177 @@ func (p *StatsProvider) FetchMatchup(championID int, enemyChampionID int, role s
 	return &m, nil
 }
 
+// AllyPairStat is how two champions do when drafted on the same team
+type AllyPairStat struct {
+	ChampionA int // lower champion ID
+	ChampionB int // higher champion ID
+	Wins      int
+	Matches   int
+	WinRate   float64
+}
+
+// FetchAllyPairSynergy returns the combined win rate of two champions played on the
+// same team, aggregated across patches. Argument order does not matter.
+func (p *StatsProvider) FetchAllyPairSynergy(championA, championB int) (*AllyPairStat, error) {
+	low, high := championA, championB
+	if low > high {
+		low, high = high, low
+	}
+
+	cacheKey := fmt.Sprintf("allypair:%d:%d", low, high)
+	if cached, ok := p.cache().Get(cacheKey); ok {
+		return cached.(*AllyPairStat), nil
+	}
+
+	stat := AllyPairStat{ChampionA: low, ChampionB: high}
+	err := p.db().QueryRow(`
+		SELECT COALESCE(SUM(wins), 0), COALESCE(SUM(matches), 0)
+		FROM champion_ally_pairs
+		WHERE champion_id = ? AND ally_champion_id = ?
+	`, low, high).Scan(&stat.Wins, &stat.Matches)
+
+	if err != nil || stat.Matches == 0 {
+		return nil, fmt.Errorf("no ally pair data for %d and %d", championA, championB)
+	}
+
+	stat.WinRate = float64(stat.Wins) / float64(stat.Matches) * 100
+	p.cache().Set(cacheKey, &stat)
+	return &stat, nil
+}
+
+// SpellPairStat holds the win rate of one summoner spell pair; Spell1ID < Spell2ID
+type SpellPairStat struct {
+	Spell1ID int
+	Spell2ID int
+	Wins     int
+	Matches  int
+	WinRate  float64
+}
+
+// FetchSpellPairs returns a champion's summoner spell pairs in a role, best win rate
+// first, aggregated across patches. Pairs with fewer than minGames are ignored.
+func (p *StatsProvider) FetchSpellPairs(championID int, role string, minGames int) ([]SpellPairStat, error) {
+	cacheKey := fmt.Sprintf("spells:%d:%s:%d", championID, role, minGames)
+	if cached, ok := p.cache().Get(cacheKey); ok {
+		return cached.([]SpellPairStat), nil
+	}
+
+	position := roleToPosition(role)
+
+	rows, err := p.db().Query(`
+		SELECT spell1_id, spell2_id, SUM(wins) as wins, SUM(matches) as matches
+		FROM champion_spells
+		WHERE champion_id = ? AND team_position = ?
+		GROUP BY spell1_id, spell2_id
+		HAVING SUM(matches) >= ?
+		ORDER BY (CAST(SUM(wins) AS REAL) / CAST(SUM(matches) AS REAL)) DESC, SUM(matches) DESC
+	`, championID, position, minGames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query spell pairs: %w", err)
+	}
+	defer rows.Close()
+
+	var pairs []SpellPairStat
+	for rows.Next() {
+		var pair SpellPairStat
+		if err := rows.Scan(&pair.Spell1ID, &pair.Spell2ID, &pair.Wins, &pair.Matches); err != nil {
+			continue
+		}
+		if pair.Matches > 0 {
+			pair.WinRate = float64(pair.Wins) / float64(pair.Matches) * 100
+		}
+		pairs = append(pairs, pair)
+	}
+
+	p.cache().Set(cacheKey, pairs)
+	return pairs, nil
+}
+
+// DurationWinRate holds a matchup's win rate within one game-length bucket
+type DurationWinRate struct {
+	Bucket  string // "early" (<25m), "mid" (25-35m), "late" (35m+)
+	Wins    int
+	Matches int
+	WinRate float64
+}
+
+// FetchMatchupByDuration returns a matchup's win rate per game-length bucket
+// (early, mid, late), aggregated across patches. Buckets with no games are omitted.
+func (p *StatsProvider) FetchMatchupByDuration(championID int, role string, enemyChampionID int) ([]DurationWinRate, error) {
+	cacheKey := fmt.Sprintf("matchup_duration:%d:%d:%s", championID, enemyChampionID, role)
+	if cached, ok := p.cache().Get(cacheKey); ok {
+		return cached.([]DurationWinRate), nil
+	}
+
+	position := roleToPosition(role)
+
+	rows, err := p.db().Query(`
+		SELECT duration_bucket, SUM(wins), SUM(matches)
+		FROM champion_matchup_durations
+		WHERE champion_id = ? AND team_position = ? AND enemy_champion_id = ?
+		GROUP BY duration_bucket
+		HAVING SUM(matches) > 0
+		ORDER BY CASE duration_bucket WHEN 'early' THEN 0 WHEN 'mid' THEN 1 ELSE 2 END
+	`, championID, position, enemyChampionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query matchup durations: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []DurationWinRate
+	for rows.Next() {
+		var d DurationWinRate
+		if err := rows.Scan(&d.Bucket, &d.Wins, &d.Matches); err != nil {
+			continue
+		}
+		d.WinRate = float64(d.Wins) / float64(d.Matches) * 100
+		buckets = append(buckets, d)
+	}
+
+	p.cache().Set(cacheKey, buckets)
+	return buckets, nil
+}
+
+// PatchPeak holds a champion's best patch by games played
+type PatchPeak struct {
+	Patch   string
+	Wins    int
+	Games   int
+	WinRate float64
+}
+
+// ChampionPeak returns the stored patch in which a champion was most picked in a role.
+// Ties on games go to the higher win rate.
+func (p *StatsProvider) ChampionPeak(championID int, role string) (*PatchPeak, error) {
+	cacheKey := fmt.Sprintf("peak:%d:%s", championID, role)
+	if cached, ok := p.cache().Get(cacheKey); ok {
+		return cached.(*PatchPeak), nil
+	}
+
+	position := roleToPosition(role)
+
+	var peak PatchPeak
+	err := p.db().QueryRow(`
+		SELECT patch, SUM(wins), SUM(matches)
+		FROM champion_stats
+		WHERE champion_id = ? AND team_position = ?
+		GROUP BY patch
+		HAVING SUM(matches) > 0
+		ORDER BY SUM(matches) DESC, CAST(SUM(wins) AS REAL) / SUM(matches) DESC
+		LIMIT 1
+	`, championID, position).Scan(&peak.Patch, &peak.Wins, &peak.Games)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no patch data for champion %d in position %s", championID, position)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query champion peak: %w", err)
+	}
+
+	peak.WinRate = float64(peak.Wins) / float64(peak.Games) * 100
+	p.cache().Set(cacheKey, &peak)
+	return &peak, nil
+}
+
 // FetchAllMatchups returns all matchup data for a champion in a role
 func (p *StatsProvider) FetchAllMatchups(championID int, role string) ([]MatchupStat, error) {
 	position := roleToPosition(role)
@@ -529,9 +890,10 @@ func (p *StatsProvider) FetchCounterMatchups(championID int, role string, limit
 }
 
 // FetchCounterPicks returns champions that counter a specific enemy champion in a role
-// (i.e., champions with high win rate against the enemy)
-func (p *StatsProvider) FetchCounterPicks(enemyChampionID int, role string, limit int) ([]MatchupStat, error) {
-	cacheKey := fmt.Sprintf("counterpicks:%d:%s:%d", enemyChampionID, role, limit)
+// (i.e., champions with high win rate against the enemy), sorted by win rate descending.
+// Matchups with fewer than minGames are excluded before the limit is applied.
+func (p *StatsProvider) FetchCounterPicks(enemyChampionID int, role string, minGames, limit int) ([]MatchupStat, error) {
+	cacheKey := fmt.Sprintf("counterpicks:%d:%s:%d:%d", enemyChampionID, role, minGames, limit)
 	if cached, ok := p.cache().Get(cacheKey); ok {
 		return cached.([]MatchupStat), nil
 	}
@@ -541,6 +903,9 @@ func (p *StatsProvider) FetchCounterPicks(enemyChampionID int, role string, limi
 	if limit <= 0 {
 		limit = 5
 	}
+	if minGames <= 0 {
+		minGames = DefaultCounterPickMinGames
+	}
 
 	// Query champions that have high win rate against this enemy
 	// We flip the query - find champions where they beat the enemy
@@ -549,11 +914,11 @@ func (p *StatsProvider) FetchCounterPicks(enemyChampionID int, role string, limi
 		FROM champion_matchups
 		WHERE enemy_champion_id = ? AND team_position = ?
 		GROUP BY champion_id
-		HAVING SUM(matches) >= 10
+		HAVING SUM(matches) >= ?
 		   AND (CAST(SUM(wins) AS REAL) / CAST(SUM(matches) AS REAL)) > 0.51
-		ORDER BY (CAST(SUM(wins) AS REAL) / CAST(SUM(matches) AS REAL)) DESC
+		ORDER BY (CAST(SUM(wins) AS REAL) / CAST(SUM(matches) AS REAL)) DESC, SUM(matches) DESC
 		LIMIT ?
-	`, enemyChampionID, position, limit)
+	`, enemyChampionID, position, minGames, limit)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to query counter picks: %w", err)
@@ -580,6 +945,226 @@ func (p *StatsProvider) FetchCounterPicks(enemyChampionID int, role string, limi
 	return matchups, nil
 }
 
+// laneSummarySize is how many matchups each side of a lane summary holds
+const laneSummarySize = 3
+
+// LaneSummary is a champion's hardest and easiest lane matchups at a glance
+type LaneSummary struct {
+	Hardest []MatchupStat // below 50% win rate, lowest first
+	Easiest []MatchupStat // above 50% win rate, highest first
+}
+
+// FetchLaneSummary returns the champion's three hardest and three easiest matchups
+// in a role, ignoring matchups with fewer than minGames
+func (p *StatsProvider) FetchLaneSummary(championID int, role string, minGames int) (*LaneSummary, error) {
+	matchups, err := p.FetchAllMatchups(championID, role)
+	if err != nil {
+		return nil, err
+	}
+	return buildLaneSummary(matchups, minGames), nil
+}
+
+// buildLaneSummary splits matchups around 50% and keeps the extremes of each side.
+// Ties on win rate go to the matchup with more games.
+func buildLaneSummary(matchups []MatchupStat, minGames int) *LaneSummary {
+	summary := &LaneSummary{Hardest: []MatchupStat{}, Easiest: []MatchupStat{}}
+	for _, m := range matchups {
+		if m.Matches < minGames {
+			continue
+		}
+		switch {
+		case m.WinRate < 50:
+			summary.Hardest = append(summary.Hardest, m)
+		case m.WinRate > 50:
+			summary.Easiest = append(summary.Easiest, m)
+		}
+	}
+
+	sort.Slice(summary.Hardest, func(i, j int) bool {
+		a, b := summary.Hardest[i], summary.Hardest[j]
+		if a.WinRate != b.WinRate {
+			return a.WinRate < b.WinRate
+		}
+		return a.Matches > b.Matches
+	})
+	sort.Slice(summary.Easiest, func(i, j int) bool {
+		a, b := summary.Easiest[i], summary.Easiest[j]
+		if a.WinRate != b.WinRate {
+			return a.WinRate > b.WinRate
+		}
+		return a.Matches > b.Matches
+	})
+
+	if len(summary.Hardest) > laneSummarySize {
+		summary.Hardest = summary.Hardest[:laneSummarySize]
+	}
+	if len(summary.Easiest) > laneSummarySize {
+		summary.Easiest = summary.Easiest[:laneSummarySize]
+	}
+	return summary
+}
+
+// MatchupMatrix maps champion ID -> enemy champion ID -> matchup stats for one role
+type MatchupMatrix map[int]map[int]MatchupStat
+
+// Cell returns the stats for championID vs enemyChampionID, if present
+func (m MatchupMatrix) Cell(championID, enemyChampionID int) (MatchupStat, bool) {
+	stat, ok := m[championID][enemyChampionID]
+	return stat, ok
+}
+
+// FetchRoleMatchupMatrix returns every champion-vs-champion matchup for a role on a patch
+// in one query. Cells with fewer than minGames are left out.
+func (p *StatsProvider) FetchRoleMatchupMatrix(role string, patch string, minGames int) (MatchupMatrix, error) {
+	cacheKey := fmt.Sprintf("matchupmatrix:%s:%s:%d", role, patch, minGames)
+	if cached, ok := p.cache().Get(cacheKey); ok {
+		return cached.(MatchupMatrix), nil
+	}
+
+	position := roleToPosition(role)
+
+	rows, err := p.db().Query(`
+		SELECT champion_id, enemy_champion_id, SUM(wins) as wins, SUM(matches) as matches
+		FROM champion_matchups
+		WHERE patch = ? AND team_position = ?
+		GROUP BY champion_id, enemy_champion_id
+		HAVING SUM(matches) >= ?
+	`, patch, position, minGames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query matchup matrix: %w", err)
+	}
+	defer rows.Close()
+
+	matrix := make(MatchupMatrix)
+	for rows.Next() {
+		var champID int
+		var m MatchupStat
+		if err := rows.Scan(&champID, &m.EnemyChampionID, &m.Wins, &m.Matches); err != nil {
+			return nil, fmt.Errorf("failed to scan matchup matrix row: %w", err)
+		}
+		if m.Matches > 0 {
+			m.WinRate = float64(m.Wins) / float64(m.Matches) * 100
+		}
+		if matrix[champID] == nil {
+			matrix[champID] = make(map[int]MatchupStat)
+		}
+		matrix[champID][m.EnemyChampionID] = m
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read matchup matrix: %w", err)
+	}
+
+	p.cache().Set(cacheKey, matrix)
+	return matrix, nil
+}
+
+// FetchWorstItems returns the lowest win rate items built on a champion in a role
+// (trap picks), ascending by win rate. Items with fewer than minGames are ignored.
+func (p *StatsProvider) FetchWorstItems(championID int, role string, limit, minGames int) ([]ItemStat, error) {
+	cacheKey := fmt.Sprintf("worstitems:%d:%s:%d:%d", championID, role, limit, minGames)
+	if cached, ok := p.cache().Get(cacheKey); ok {
+		return cached.([]ItemStat), nil
+	}
+
+	position := roleToPosition(role)
+
+	if limit <= 0 {
+		limit = 3
+	}
+
+	rows, err := p.db().Query(`
+		SELECT item_id, SUM(wins) as wins, SUM(matches) as matches
+		FROM champion_items
+		WHERE champion_id = ? AND team_position = ?
+		GROUP BY item_id
+		HAVING SUM(matches) >= ?
+		ORDER BY (CAST(SUM(wins) AS REAL) / CAST(SUM(matches) AS REAL)) ASC, SUM(matches) DESC
+		LIMIT ?
+	`, championID, position, minGames, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query worst items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []ItemStat
+	for rows.Next() {
+		var item ItemStat
+		if err := rows.Scan(&item.ItemID, &item.Wins, &item.Matches); err != nil {
+			continue
+		}
+		if item.Matches > 0 {
+			item.WinRate = float64(item.Wins) / float64(item.Matches) * 100
+		}
+		items = append(items, item)
+	}
+
+	p.cache().Set(cacheKey, items)
+	return items, nil
+}
+
+// ARAMBuild holds a champion's ARAM stats. ARAM has no roles and no build-order
+// sampling, so the build is the champion's most-built final items.
+type ARAMBuild struct {
+	ChampionID int
+	Wins       int
+	Games      int
+	WinRate    float64
+	Items      []ItemStat // Most built first; PickRate is the share of ARAM games with the item
+}
+
+// FetchARAMBuild returns a champion's ARAM win rate and its top items, aggregated
+// across patches. ARAM stats live in their own tables and never mix with ranked data.
+func (p *StatsProvider) FetchARAMBuild(championID int, limit int) (*ARAMBuild, error) {
+	if limit <= 0 {
+		limit = 6
+	}
+
+	cacheKey := fmt.Sprintf("aram:%d:%d", championID, limit)
+	if cached, ok := p.cache().Get(cacheKey); ok {
+		return cached.(*ARAMBuild), nil
+	}
+
+	build := ARAMBuild{ChampionID: championID}
+	err := p.db().QueryRow(`
+		SELECT COALESCE(SUM(wins), 0), COALESCE(SUM(matches), 0)
+		FROM aram_champion_stats
+		WHERE champion_id = ?
+	`, championID).Scan(&build.Wins, &build.Games)
+	if err != nil || build.Games == 0 {
+		return nil, fmt.Errorf("no ARAM data for champion %d", championID)
+	}
+	build.WinRate = float64(build.Wins) / float64(build.Games) * 100
+
+	rows, err := p.db().Query(`
+		SELECT item_id, SUM(wins) as wins, SUM(matches) as matches
+		FROM aram_champion_items
+		WHERE champion_id = ?
+		GROUP BY item_id
+		ORDER BY SUM(matches) DESC, item_id ASC
+		LIMIT ?
+	`, championID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ARAM items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item ItemStat
+		if err := rows.Scan(&item.ItemID, &item.Wins, &item.Matches); err != nil {
+			continue
+		}
+		if item.Matches > 0 {
+			item.WinRate = float64(item.Wins) / float64(item.Matches) * 100
+		}
+		item.PickRate = float64(item.Matches) / float64(build.Games) * 100
+		build.Items = append(build.Items, item)
+	}
+
+	p.cache().Set(cacheKey, &build)
+	return &build, nil
+}
+
 // FetchTopChampionsByRole returns the top N champions by win rate for a given role
 // Uses tiered logic: prefer current patch, fallback to aggregated if not enough data
 func (p *StatsProvider) FetchTopChampionsByRole(role string, limit int) ([]ChampionWinRate, error) {
@@ -588,12 +1173,65 @@ func (p *StatsProvider) FetchTopChampionsByRole(role string, limit int) ([]Champ
 		return cached.([]ChampionWinRate), nil
 	}
 
-	position := roleToPosition(role)
+	if limit <= 0 {
+		limit = 5
+	}
+
+	champions, err := p.roleChampions(role, 100)
+	if err != nil {
+		return nil, err
+	}
+	if len(champions) > limit {
+		champions = champions[:limit]
+	}
+
+	p.cache().Set(cacheKey, champions)
+	return champions, nil
+}
+
+// metaMinGames is the fewest games a champion needs to appear in the confidence-sorted meta
+const metaMinGames = 50
+
+// MetaProvisionalGames is the games floor below which a meta champion is marked provisional
+const MetaProvisionalGames = 200
+
+// FetchConfidentTopChampionsByRole returns the top N champions for a role ranked by the
+// Wilson lower bound of their win rate, so a small lucky sample can't outrank a proven
+// pick. Champions under MetaProvisionalGames are flagged Provisional.
+func (p *StatsProvider) FetchConfidentTopChampionsByRole(role string, limit int) ([]ChampionWinRate, error) {
+	cacheKey := fmt.Sprintf("meta_confident:%s:%d", role, limit)
+	if cached, ok := p.cache().Get(cacheKey); ok {
+		return cached.([]ChampionWinRate), nil
+	}
 
 	if limit <= 0 {
 		limit = 5
 	}
 
+	champions, err := p.roleChampions(role, metaMinGames)
+	if err != nil {
+		return nil, err
+	}
+	for i := range champions {
+		champions[i].Confidence = WilsonLowerBound(champions[i].Wins, champions[i].Matches)
+		champions[i].Provisional = champions[i].Matches < MetaProvisionalGames
+	}
+	sort.SliceStable(champions, func(i, j int) bool {
+		return champions[i].Confidence > champions[j].Confidence
+	})
+	if len(champions) > limit {
+		champions = champions[:limit]
+	}
+
+	p.cache().Set(cacheKey, champions)
+	return champions, nil
+}
+
+// roleChampions returns every champion with at least minGames in a role, ordered by
+// raw win rate. Uses the current patch alone when it has enough games, else all patches.
+func (p *StatsProvider) roleChampions(role string, minGames int) ([]ChampionWinRate, error) {
+	position := roleToPosition(role)
+
 	// Check if current patch has enough games
 	var currentPatchGames int
 	if p.currentPatch != "" {
@@ -627,10 +1265,9 @@ func (p *StatsProvider) FetchTopChampionsByRole(role string, limit int) ([]Champ
 			FROM champion_stats
 			WHERE team_position = ? AND patch = ?
 			GROUP BY champion_id
-			HAVING SUM(matches) >= 100
+			HAVING SUM(matches) >= ?
 			ORDER BY (CAST(SUM(wins) AS REAL) / CAST(SUM(matches) AS REAL)) DESC
-			LIMIT ?
-		`, position, p.currentPatch, limit)
+		`, position, p.currentPatch, minGames)
 	} else {
 		// Not enough data in current patch - aggregate all patches
 		fmt.Printf("[Stats] Aggregating all patches for %s (current patch %s has only %d games)\n", role, p.currentPatch, currentPatchGames)
@@ -648,10 +1285,9 @@ func (p *StatsProvider) FetchTopChampionsByRole(role string, limit int) ([]Champ
 			FROM champion_stats
 			WHERE team_position = ?
 			GROUP BY champion_id
-			HAVING SUM(matches) >= 100
+			HAVING SUM(matches) >= ?
 			ORDER BY (CAST(SUM(wins) AS REAL) / CAST(SUM(matches) AS REAL)) DESC
-			LIMIT ?
-		`, position, limit)
+		`, position, minGames)
 	}
 
 	if err != nil {
@@ -673,17 +1309,15 @@ func (p *StatsProvider) FetchTopChampionsByRole(role string, limit int) ([]Champ
 		}
 		champions = append(champions, c)
 	}
-
-	p.cache().Set(cacheKey, champions)
 	return champions, nil
 }
 
 // FetchAllRolesTopChampions returns top N champions for all 5 roles
 func (p *StatsProvider) FetchAllRolesTopChampions(limit int) (map[string][]ChampionWinRate, error) {
-	roles := []string{"top", "jungle", "middle", "bottom", "utility"}
 	result := make(map[string][]ChampionWinRate)
 
-	for _, role := range roles {
+	for _, r := range roles.All {
+		role := r.String()
 		champs, err := p.FetchTopChampionsByRole(role, limit)
 		if err != nil {
 			result[role] = []ChampionWinRate{}
@@ -694,3 +1328,95 @@ func (p *StatsProvider) FetchAllRolesTopChampions(limit int) (map[string][]Champ
 
 	return result, nil
 }
+
+// FetchAllRolesConfidentChampions returns the confidence-sorted top N champions for all 5 roles
+func (p *StatsProvider) FetchAllRolesConfidentChampions(limit int) (map[string][]ChampionWinRate, error) {
+	result := make(map[string][]ChampionWinRate)
+
+	for _, r := range roles.All {
+		role := r.String()
+		champs, err := p.FetchConfidentTopChampionsByRole(role, limit)
+		if err != nil {
+			result[role] = []ChampionWinRate{}
+			continue
+		}
+		result[role] = champs
+	}
+
+	return result, nil
+}
+
+// FlexRole is one role a flex pick is viable in
+type FlexRole struct {
+	Role    string
+	Wins    int
+	Matches int
+	WinRate float64
+}
+
+// FlexPick is a champion viable in more than one role, roles ordered by games played
+type FlexPick struct {
+	ChampionID int
+	Roles      []FlexRole
+}
+
+// FetchFlexPicks returns champions with at least minGames and minWinRate (percent) in two
+// or more roles, across all patches. Champions viable in the most roles come first.
+func (p *StatsProvider) FetchFlexPicks(minGames int, minWinRate float64) ([]FlexPick, error) {
+	cacheKey := fmt.Sprintf("flexpicks:%d:%.2f", minGames, minWinRate)
+	if cached, ok := p.cache().Get(cacheKey); ok {
+		return cached.([]FlexPick), nil
+	}
+
+	rows, err := p.db().Query(`
+		SELECT champion_id, team_position, SUM(wins) as wins, SUM(matches) as matches
+		FROM champion_stats
+		GROUP BY champion_id, team_position
+		HAVING SUM(matches) >= ?
+		ORDER BY champion_id, matches DESC
+	`, minGames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query flex picks: %w", err)
+	}
+	defer rows.Close()
+
+	byChampion := make(map[int][]FlexRole)
+	var order []int
+	for rows.Next() {
+		var champID int
+		var position string
+		var r FlexRole
+		if err := rows.Scan(&champID, &position, &r.Wins, &r.Matches); err != nil {
+			return nil, fmt.Errorf("failed to scan flex pick row: %w", err)
+		}
+		role := roles.Parse(position)
+		if role == roles.Unknown || r.Matches == 0 {
+			continue
+		}
+		r.Role = role.String()
+		r.WinRate = float64(r.Wins) / float64(r.Matches) * 100
+		if r.WinRate < minWinRate {
+			continue
+		}
+		if _, seen := byChampion[champID]; !seen {
+			order = append(order, champID)
+		}
+		byChampion[champID] = append(byChampion[champID], r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read flex picks: %w", err)
+	}
+
+	picks := []FlexPick{}
+	for _, champID := range order {
+		if len(byChampion[champID]) >= 2 {
+			picks = append(picks, FlexPick{ChampionID: champID, Roles: byChampion[champID]})
+		}
+	}
+	sort.SliceStable(picks, func(i, j int) bool {
+		return len(picks[i].Roles) > len(picks[j].Roles)
+	})
+
+	p.cache().Set(cacheKey, picks)
+	return picks, nil
+}
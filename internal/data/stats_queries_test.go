@@ -0,0 +1,573 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+// testSchema mirrors the tables written by the data-analyzer pipeline
+const testSchema = `
+CREATE TABLE champion_stats (
+	patch TEXT NOT NULL,
+	champion_id INTEGER NOT NULL,
+	team_position TEXT NOT NULL,
+	wins INTEGER NOT NULL DEFAULT 0,
+	matches INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (patch, champion_id, team_position)
+);
+CREATE TABLE champion_items (
+	patch TEXT NOT NULL,
+	champion_id INTEGER NOT NULL,
+	team_position TEXT NOT NULL,
+	item_id INTEGER NOT NULL,
+	wins INTEGER NOT NULL DEFAULT 0,
+	matches INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (patch, champion_id, team_position, item_id)
+);
+CREATE TABLE champion_item_slots (
+	patch TEXT NOT NULL,
+	champion_id INTEGER NOT NULL,
+	team_position TEXT NOT NULL,
+	item_id INTEGER NOT NULL,
+	build_slot INTEGER NOT NULL,
+	wins INTEGER NOT NULL DEFAULT 0,
+	matches INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (patch, champion_id, team_position, item_id, build_slot)
+);
+CREATE TABLE champion_matchups (
+	patch TEXT NOT NULL,
+	champion_id INTEGER NOT NULL,
+	team_position TEXT NOT NULL,
+	enemy_champion_id INTEGER NOT NULL,
+	wins INTEGER NOT NULL DEFAULT 0,
+	matches INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (patch, champion_id, team_position, enemy_champion_id)
+);
+CREATE TABLE champion_matchup_durations (
+	patch TEXT NOT NULL,
+	champion_id INTEGER NOT NULL,
+	team_position TEXT NOT NULL,
+	enemy_champion_id INTEGER NOT NULL,
+	duration_bucket TEXT NOT NULL,
+	wins INTEGER NOT NULL DEFAULT 0,
+	matches INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (patch, champion_id, team_position, enemy_champion_id, duration_bucket)
+);
+CREATE TABLE champion_ally_pairs (
+	patch TEXT NOT NULL,
+	champion_id INTEGER NOT NULL,
+	ally_champion_id INTEGER NOT NULL,
+	wins INTEGER NOT NULL DEFAULT 0,
+	matches INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (patch, champion_id, ally_champion_id)
+);
+CREATE TABLE champion_spells (
+	patch TEXT NOT NULL,
+	champion_id INTEGER NOT NULL,
+	team_position TEXT NOT NULL,
+	spell1_id INTEGER NOT NULL,
+	spell2_id INTEGER NOT NULL,
+	wins INTEGER NOT NULL DEFAULT 0,
+	matches INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (patch, champion_id, team_position, spell1_id, spell2_id)
+);
+CREATE TABLE aram_champion_stats (
+	patch TEXT NOT NULL,
+	champion_id INTEGER NOT NULL,
+	wins INTEGER NOT NULL DEFAULT 0,
+	matches INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (patch, champion_id)
+);
+CREATE TABLE aram_champion_items (
+	patch TEXT NOT NULL,
+	champion_id INTEGER NOT NULL,
+	item_id INTEGER NOT NULL,
+	wins INTEGER NOT NULL DEFAULT 0,
+	matches INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (patch, champion_id, item_id)
+);
+`
+
+// newTestStatsProvider returns a provider backed by an in-memory SQLite database
+func newTestStatsProvider(t *testing.T) (*StatsProvider, *sql.DB) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1) // keep a single connection so the in-memory DB persists
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(testSchema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+
+	provider, err := NewStatsProvider(&TursoClient{db: db, cache: NewQueryCache()})
+	if err != nil {
+		t.Fatalf("NewStatsProvider: %v", err)
+	}
+	return provider, db
+}
+
+// mustExec runs a statement and fails the test on error
+func mustExec(t *testing.T, db *sql.DB, query string, args ...interface{}) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("exec %q: %v", query, err)
+	}
+}
+
+func TestFetchCounterPicks_SortedWithMinGames(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	// Counters vs champion 238 (Zed) in MIDDLE
+	mustExec(t, db, `INSERT INTO champion_matchups VALUES ('15.24', 1, 'MIDDLE', 238, 55, 100)`) // 55%
+	mustExec(t, db, `INSERT INTO champion_matchups VALUES ('15.24', 2, 'MIDDLE', 238, 60, 100)`) // 60%
+	mustExec(t, db, `INSERT INTO champion_matchups VALUES ('15.24', 3, 'MIDDLE', 238, 9, 10)`)   // 90% but low sample
+	mustExec(t, db, `INSERT INTO champion_matchups VALUES ('15.24', 4, 'MIDDLE', 238, 40, 100)`) // losing, not a counter
+
+	picks, err := provider.FetchCounterPicks(238, "middle", 50, 5)
+	if err != nil {
+		t.Fatalf("FetchCounterPicks: %v", err)
+	}
+
+	if len(picks) != 2 {
+		t.Fatalf("got %d picks, want 2: %+v", len(picks), picks)
+	}
+	if picks[0].EnemyChampionID != 2 || picks[1].EnemyChampionID != 1 {
+		t.Errorf("picks not sorted by win rate: %+v", picks)
+	}
+	for _, p := range picks {
+		if p.EnemyChampionID == 3 {
+			t.Error("low-sample counter should be excluded")
+		}
+	}
+}
+
+func TestFetchWorstItems_AscendingAboveFloor(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_items VALUES ('15.24', 103, 'MIDDLE', 3089, 60, 100)`) // 60%
+	mustExec(t, db, `INSERT INTO champion_items VALUES ('15.24', 103, 'MIDDLE', 3157, 45, 100)`) // 45%
+	mustExec(t, db, `INSERT INTO champion_items VALUES ('15.24', 103, 'MIDDLE', 3135, 40, 100)`) // 40%
+	mustExec(t, db, `INSERT INTO champion_items VALUES ('15.24', 103, 'MIDDLE', 3116, 1, 10)`)   // 10% but below floor
+
+	items, err := provider.FetchWorstItems(103, "middle", 2, 50)
+	if err != nil {
+		t.Fatalf("FetchWorstItems: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2: %+v", len(items), items)
+	}
+	if items[0].ItemID != 3135 || items[1].ItemID != 3157 {
+		t.Errorf("expected [3135 3157] ascending by win rate, got %+v", items)
+	}
+	if items[0].WinRate != 40 {
+		t.Errorf("win rate: got %.1f, want 40.0", items[0].WinRate)
+	}
+}
+
+func TestFetchChampionData_SituationalItemOptions(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'MIDDLE', 50, 100)`)
+	for i, itemID := range []int{3089, 3157, 3135, 3116, 3165, 4645} {
+		mustExec(t, db, `INSERT INTO champion_item_slots VALUES ('15.24', 103, 'MIDDLE', ?, 4, 10, ?)`, itemID, 60-i)
+	}
+
+	build, err := provider.FetchChampionData(103, "Ahri", "middle")
+	if err != nil {
+		t.Fatalf("FetchChampionData: %v", err)
+	}
+	if got := len(build.Builds[0].FourthItemOptions); got != DefaultSituationalItemOptions {
+		t.Errorf("default: got %d fourth item options, want %d", got, DefaultSituationalItemOptions)
+	}
+
+	provider.SetSituationalItemOptions(5)
+	build, err = provider.FetchChampionData(103, "Ahri", "middle")
+	if err != nil {
+		t.Fatalf("FetchChampionData: %v", err)
+	}
+	if got := len(build.Builds[0].FourthItemOptions); got != 5 {
+		t.Errorf("got %d fourth item options, want 5", got)
+	}
+}
+
+func TestFetchChampionData_GamesAndSource(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'MIDDLE', 30, 60)`)
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.23', 103, 'MIDDLE', 20, 40)`)
+	mustExec(t, db, `INSERT INTO champion_item_slots VALUES ('15.24', 103, 'MIDDLE', 3089, 1, 30, 60)`)
+
+	build, err := provider.FetchChampionData(103, "Ahri", "middle")
+	if err != nil {
+		t.Fatalf("FetchChampionData: %v", err)
+	}
+	if build.Games != 100 || build.Source != BuildSourceLocal {
+		t.Errorf("got games=%d source=%q, want 100 %q", build.Games, build.Source, BuildSourceLocal)
+	}
+}
+
+func TestFetchChampionDataForPatch_ClosestPatchWhenMissing(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.9', 103, 'MIDDLE', 10, 20)`)
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.10', 103, 'MIDDLE', 30, 60)`)
+	mustExec(t, db, `INSERT INTO champion_item_slots VALUES ('15.9', 103, 'MIDDLE', 6655, 1, 10, 20)`)
+	mustExec(t, db, `INSERT INTO champion_item_slots VALUES ('15.10', 103, 'MIDDLE', 3089, 1, 30, 60)`)
+
+	// Live patch ahead of the database: serve the newest stored patch and say so
+	build, err := provider.FetchChampionDataForPatch(103, "Ahri", "middle", "15.11")
+	if err != nil {
+		t.Fatalf("FetchChampionDataForPatch: %v", err)
+	}
+	if build.Patch != "15.10" || build.ExactPatch {
+		t.Errorf("got patch %q exact=%v, want closest 15.10 and not exact", build.Patch, build.ExactPatch)
+	}
+	if build.Games != 60 || build.Builds[0].CoreItems[0] != 3089 {
+		t.Errorf("got games=%d core=%v, want only 15.10 data", build.Games, build.Builds[0].CoreItems)
+	}
+
+	build, err = provider.FetchChampionDataForPatch(103, "Ahri", "middle", "15.9")
+	if err != nil {
+		t.Fatalf("FetchChampionDataForPatch: %v", err)
+	}
+	if build.Patch != "15.9" || !build.ExactPatch || build.Builds[0].CoreItems[0] != 6655 {
+		t.Errorf("got patch %q exact=%v core=%v, want exact 15.9 build", build.Patch, build.ExactPatch, build.Builds[0].CoreItems)
+	}
+
+	if _, err := provider.FetchChampionDataForPatch(1, "Annie", "middle", "15.10"); err == nil {
+		t.Error("champion without data should error")
+	}
+}
+
+func TestFetchChampionData_FallsBackToMostPlayedRole(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	// Ahri only has middle data; top is requested
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'MIDDLE', 30, 60)`)
+	mustExec(t, db, `INSERT INTO champion_item_slots VALUES ('15.24', 103, 'MIDDLE', 3089, 1, 30, 60)`)
+
+	build, err := provider.FetchChampionData(103, "Ahri", "top")
+	if err != nil {
+		t.Fatalf("FetchChampionData: %v", err)
+	}
+	if build.Role != "top" || build.ResolvedRole != "middle" {
+		t.Errorf("got role=%q resolved=%q, want top/middle", build.Role, build.ResolvedRole)
+	}
+	if build.Games != 60 {
+		t.Errorf("Games = %d, want 60 from the fallback role", build.Games)
+	}
+
+	build, err = provider.FetchChampionData(103, "Ahri", "middle")
+	if err != nil {
+		t.Fatalf("FetchChampionData: %v", err)
+	}
+	if build.ResolvedRole != "middle" {
+		t.Errorf("ResolvedRole = %q without fallback, want middle", build.ResolvedRole)
+	}
+}
+
+func TestFetchLaneSummary(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	for _, m := range []struct{ enemy, wins, matches int }{
+		{238, 40, 100}, // 40%
+		{7, 45, 100},   // 45%
+		{61, 48, 100},  // 48%
+		{134, 30, 100}, // 30%
+		{1, 1, 5},      // 20%, below floor
+		{4, 60, 100},   // 60%
+		{8, 55, 100},   // 55%
+		{9, 50, 100},   // even - neither side
+		{10, 5, 5},     // 100%, below floor
+	} {
+		mustExec(t, db, `INSERT INTO champion_matchups VALUES ('15.24', 103, 'MIDDLE', ?, ?, ?)`, m.enemy, m.wins, m.matches)
+	}
+
+	summary, err := provider.FetchLaneSummary(103, "middle", 50)
+	if err != nil {
+		t.Fatalf("FetchLaneSummary: %v", err)
+	}
+
+	var hardest, easiest []int
+	for _, m := range summary.Hardest {
+		hardest = append(hardest, m.EnemyChampionID)
+	}
+	for _, m := range summary.Easiest {
+		easiest = append(easiest, m.EnemyChampionID)
+	}
+	if fmt.Sprint(hardest) != "[134 238 7]" {
+		t.Errorf("hardest = %v, want [134 238 7]", hardest)
+	}
+	if fmt.Sprint(easiest) != "[4 8]" {
+		t.Errorf("easiest = %v, want [4 8]", easiest)
+	}
+	if summary.Hardest[0].Matches != 100 {
+		t.Errorf("games not carried through: %+v", summary.Hardest[0])
+	}
+}
+
+func TestWilsonLowerBound(t *testing.T) {
+	if got := WilsonLowerBound(0, 0); got != 0 {
+		t.Errorf("no games: got %v, want 0", got)
+	}
+	// 106/200 (53%) is better supported than 15/22 (~68%)
+	solid, spike := WilsonLowerBound(106, 200), WilsonLowerBound(15, 22)
+	if solid <= spike {
+		t.Errorf("200-game 53%% bound %.2f should beat 22-game spike %.2f", solid, spike)
+	}
+	if solid >= 53 || solid < 40 {
+		t.Errorf("bound %.2f should sit under the raw 53%%", solid)
+	}
+}
+
+func TestFetchRoleMatchupMatrix(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_matchups VALUES ('15.24', 103, 'MIDDLE', 238, 60, 100)`)
+	mustExec(t, db, `INSERT INTO champion_matchups VALUES ('15.24', 238, 'MIDDLE', 103, 40, 100)`)
+	mustExec(t, db, `INSERT INTO champion_matchups VALUES ('15.24', 103, 'MIDDLE', 7, 3, 5)`)      // below floor
+	mustExec(t, db, `INSERT INTO champion_matchups VALUES ('15.24', 1, 'TOP', 2, 50, 100)`)        // other role
+	mustExec(t, db, `INSERT INTO champion_matchups VALUES ('15.23', 103, 'MIDDLE', 238, 10, 100)`) // other patch
+
+	matrix, err := provider.FetchRoleMatchupMatrix("middle", "15.24", 50)
+	if err != nil {
+		t.Fatalf("FetchRoleMatchupMatrix: %v", err)
+	}
+
+	if len(matrix) != 2 {
+		t.Errorf("got %d rows, want 2: %+v", len(matrix), matrix)
+	}
+	cell, ok := matrix.Cell(103, 238)
+	if !ok || cell.WinRate != 60 || cell.Matches != 100 {
+		t.Errorf("103 vs 238: got %+v (present=%v), want 60%% over 100", cell, ok)
+	}
+	if cell, ok := matrix.Cell(238, 103); !ok || cell.WinRate != 40 {
+		t.Errorf("238 vs 103: got %+v (present=%v), want 40%%", cell, ok)
+	}
+	if _, ok := matrix.Cell(103, 7); ok {
+		t.Error("cell below the games floor should be omitted")
+	}
+	if _, ok := matrix.Cell(1, 2); ok {
+		t.Error("other role should not appear")
+	}
+}
+
+func TestChampionPeak_MostPickedPatch(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.22', 103, 'MIDDLE', 60, 100)`)
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.23', 103, 'MIDDLE', 140, 300)`) // peak
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'MIDDLE', 110, 200)`)
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'TOP', 500, 900)`) // other role
+
+	peak, err := provider.ChampionPeak(103, "middle")
+	if err != nil {
+		t.Fatalf("ChampionPeak: %v", err)
+	}
+	if peak.Patch != "15.23" || peak.Games != 300 {
+		t.Errorf("got %+v, want patch 15.23 with 300 games", peak)
+	}
+	if want := 140.0 / 300 * 100; peak.WinRate != want {
+		t.Errorf("win rate: got %.2f, want %.2f", peak.WinRate, want)
+	}
+
+	if _, err := provider.ChampionPeak(103, "jungle"); err == nil {
+		t.Error("expected an error for a role with no data")
+	}
+}
+
+func TestFetchMatchupByDuration(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_matchup_durations VALUES ('15.24', 103, 'MIDDLE', 238, 'late', 8, 20)`)
+	mustExec(t, db, `INSERT INTO champion_matchup_durations VALUES ('15.24', 103, 'MIDDLE', 238, 'early', 12, 20)`)
+	mustExec(t, db, `INSERT INTO champion_matchup_durations VALUES ('15.23', 103, 'MIDDLE', 238, 'early', 6, 10)`) // summed across patches
+	mustExec(t, db, `INSERT INTO champion_matchup_durations VALUES ('15.24', 103, 'MIDDLE', 7, 'mid', 5, 10)`)     // other enemy
+
+	buckets, err := provider.FetchMatchupByDuration(103, "middle", 238)
+	if err != nil {
+		t.Fatalf("FetchMatchupByDuration: %v", err)
+	}
+
+	if len(buckets) != 2 {
+		t.Fatalf("got %d buckets, want 2: %+v", len(buckets), buckets)
+	}
+	if buckets[0].Bucket != "early" || buckets[0].Matches != 30 || buckets[0].WinRate != 60 {
+		t.Errorf("early: got %+v, want 60%% over 30", buckets[0])
+	}
+	if buckets[1].Bucket != "late" || buckets[1].WinRate != 40 {
+		t.Errorf("late: got %+v, want 40%%", buckets[1])
+	}
+}
+
+func TestFetchFlexPicks(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	// Sett: strong top and support
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 875, 'TOP', 52, 100)`)
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.23', 875, 'TOP', 26, 50)`)
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 875, 'UTILITY', 53, 100)`)
+	// Ahri: mid only, off-role games below the floor
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'MIDDLE', 55, 100)`)
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'UTILITY', 8, 10)`)
+	// Garen: played top and mid, but losing mid
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 86, 'TOP', 52, 100)`)
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 86, 'MIDDLE', 45, 100)`)
+
+	picks, err := provider.FetchFlexPicks(50, 50)
+	if err != nil {
+		t.Fatalf("FetchFlexPicks: %v", err)
+	}
+
+	if len(picks) != 1 || picks[0].ChampionID != 875 {
+		t.Fatalf("picks = %+v, want only Sett (875)", picks)
+	}
+	got := picks[0].Roles
+	if len(got) != 2 || got[0].Role != "top" || got[0].Matches != 150 || got[1].Role != "utility" {
+		t.Errorf("roles = %+v, want top (150 games) then utility", got)
+	}
+}
+
+func TestStatsProvider_ToleratesNewerSchema(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	// A newer pipeline added columns and bumped the schema version
+	mustExec(t, db, `ALTER TABLE champion_stats ADD COLUMN bans INTEGER NOT NULL DEFAULT 0`)
+	mustExec(t, db, `ALTER TABLE champion_matchups ADD COLUMN gold_diff_15 REAL`)
+	mustExec(t, db, `CREATE TABLE schema_version (id INTEGER PRIMARY KEY, version INTEGER NOT NULL, notes TEXT)`)
+	mustExec(t, db, `INSERT INTO schema_version VALUES (1, ?, 'adds bans')`, supportedSchemaVersion+1)
+
+	mustExec(t, db, `INSERT INTO champion_stats (patch, champion_id, team_position, wins, matches, bans) VALUES ('15.24', 103, 'MIDDLE', 60, 100, 12)`)
+	mustExec(t, db, `INSERT INTO champion_matchups (patch, champion_id, team_position, enemy_champion_id, wins, matches, gold_diff_15) VALUES ('15.24', 103, 'MIDDLE', 238, 40, 100, -250.5)`)
+
+	if err := provider.FetchPatch(); err != nil {
+		t.Fatalf("FetchPatch: %v", err)
+	}
+	if version, newer := provider.CheckSchemaVersion(); version != supportedSchemaVersion+1 || !newer {
+		t.Errorf("CheckSchemaVersion = %d, %v; want %d, true", version, newer, supportedSchemaVersion+1)
+	}
+	if role := provider.GetMostPlayedRole(103); role != "middle" {
+		t.Errorf("GetMostPlayedRole = %q, want middle", role)
+	}
+	matchups, err := provider.FetchAllMatchups(103, "middle")
+	if err != nil || len(matchups) != 1 || matchups[0].WinRate != 40 {
+		t.Errorf("FetchAllMatchups = %+v, %v", matchups, err)
+	}
+	if _, err := provider.FetchFlexPicks(50, 0); err != nil {
+		t.Errorf("FetchFlexPicks: %v", err)
+	}
+}
+
+func TestCheckSchemaVersion_MissingTable(t *testing.T) {
+	provider, _ := newTestStatsProvider(t)
+
+	if version, newer := provider.CheckSchemaVersion(); version != 0 || newer {
+		t.Errorf("CheckSchemaVersion = %d, %v; want 0, false", version, newer)
+	}
+}
+
+func TestFetchAllyPairSynergy(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_ally_pairs VALUES ('15.24', 64, 103, 30, 50)`)
+	mustExec(t, db, `INSERT INTO champion_ally_pairs VALUES ('15.23', 64, 103, 20, 50)`) // summed across patches
+	mustExec(t, db, `INSERT INTO champion_ally_pairs VALUES ('15.24', 64, 238, 10, 50)`) // other pair
+
+	// Either argument order finds the pair
+	for _, args := range [][2]int{{103, 64}, {64, 103}} {
+		stat, err := provider.FetchAllyPairSynergy(args[0], args[1])
+		if err != nil {
+			t.Fatalf("FetchAllyPairSynergy(%d, %d): %v", args[0], args[1], err)
+		}
+		if stat.ChampionA != 64 || stat.Wins != 50 || stat.Matches != 100 || stat.WinRate != 50 {
+			t.Errorf("FetchAllyPairSynergy(%d, %d) = %+v, want 50/100", args[0], args[1], stat)
+		}
+	}
+
+	if _, err := provider.FetchAllyPairSynergy(103, 238); err == nil {
+		t.Error("pair without games should return an error")
+	}
+}
+
+func TestFetchARAMBuild(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO aram_champion_stats VALUES ('15.24', 103, 30, 50)`)
+	mustExec(t, db, `INSERT INTO aram_champion_stats VALUES ('15.23', 103, 20, 50)`)
+	mustExec(t, db, `INSERT INTO aram_champion_items VALUES ('15.24', 103, 3089, 20, 40)`)
+	mustExec(t, db, `INSERT INTO aram_champion_items VALUES ('15.23', 103, 3089, 10, 20)`)
+	mustExec(t, db, `INSERT INTO aram_champion_items VALUES ('15.24', 103, 3165, 10, 25)`)
+	// Ranked data for the same champion must not leak into the ARAM build
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'MIDDLE', 90, 100)`)
+
+	build, err := provider.FetchARAMBuild(103, 6)
+	if err != nil {
+		t.Fatalf("FetchARAMBuild: %v", err)
+	}
+	if build.Wins != 50 || build.Games != 100 || build.WinRate != 50 {
+		t.Errorf("FetchARAMBuild = %+v, want 50/100", build)
+	}
+	if len(build.Items) != 2 || build.Items[0].ItemID != 3089 || build.Items[0].Matches != 60 || build.Items[0].PickRate != 60 {
+		t.Errorf("Items = %+v, want 3089 first with 60 games (60%% pick rate)", build.Items)
+	}
+
+	if _, err := provider.FetchARAMBuild(238, 6); err == nil {
+		t.Error("champion without ARAM games should return an error")
+	}
+}
+
+func TestRoleToPosition(t *testing.T) {
+	cases := []struct {
+		role string
+		want string
+	}{
+		{"TOP", "TOP"},
+		{"JUNGLE", "JUNGLE"},
+		{"MID", "MIDDLE"},
+		{"ADC", "BOTTOM"},
+		{"SUPPORT", "UTILITY"},
+		{"middle", "MIDDLE"},
+		{"bottom", "BOTTOM"},
+		{"utility", "UTILITY"},
+		{"Mid", "MIDDLE"},
+		{" support ", "UTILITY"},
+		{"", "MIDDLE"},
+		{"fill", "MIDDLE"},
+	}
+	for _, c := range cases {
+		if got := roleToPosition(c.role); got != c.want {
+			t.Errorf("roleToPosition(%q) = %q, want %q", c.role, got, c.want)
+		}
+	}
+}
+
+func TestFetchConfidentTopChampionsByRole(t *testing.T) {
+	provider, db := newTestStatsProvider(t)
+
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 103, 'MIDDLE', 5200, 10000)`) // 52% over many games
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 238, 'MIDDLE', 60, 100)`)     // 60% over few games
+	mustExec(t, db, `INSERT INTO champion_stats VALUES ('15.24', 7, 'MIDDLE', 20, 30)`)        // below the list floor
+
+	champs, err := provider.FetchConfidentTopChampionsByRole("MID", 5)
+	if err != nil {
+		t.Fatalf("FetchConfidentTopChampionsByRole: %v", err)
+	}
+	if len(champs) != 2 {
+		t.Fatalf("got %d champions, want 2: %+v", len(champs), champs)
+	}
+	if champs[0].ChampionID != 103 || champs[1].ChampionID != 238 {
+		t.Errorf("order = %d, %d; want the 52%% high-sample champion first", champs[0].ChampionID, champs[1].ChampionID)
+	}
+	if champs[0].Provisional || !champs[1].Provisional {
+		t.Errorf("provisional flags = %v, %v; want false, true", champs[0].Provisional, champs[1].Provisional)
+	}
+	if champs[1].WinRate != 60 || champs[1].Confidence >= champs[0].Confidence {
+		t.Errorf("confidence should rank below despite higher win rate: %+v", champs)
+	}
+}
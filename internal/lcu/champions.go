@@ -26,7 +26,7 @@ type ChampionInfo struct {
 // ChampionRegistry holds the champion ID to name mapping
 type ChampionRegistry struct {
 	champions map[int]ChampionInfo // key -> info (key is the numeric ID)
-	version   string
+	version   string               // Data Dragon version the champion data was loaded from; all URLs use it
 	mu        sync.RWMutex
 	loaded    bool
 }
@@ -123,7 +123,7 @@ func (r *ChampionRegistry) GetIconURL(id int) string {
 	defer r.mu.RUnlock()
 
 	if info, ok := r.champions[id]; ok {
-		return fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/%s/img/champion/%s.png", r.version, info.IconID)
+		return r.iconURL(info.IconID)
 	}
 	return ""
 }
@@ -134,7 +134,8 @@ func (r *ChampionRegistry) GetSplashURL(id int) string {
 	defer r.mu.RUnlock()
 
 	if info, ok := r.champions[id]; ok {
-		return fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/img/champion/splash/%s_0.jpg", info.IconID)
+		// Splash art is unversioned on Data Dragon; the query pins the cached copy to the loaded version
+		return fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/img/champion/splash/%s_0.jpg?v=%s", info.IconID, r.version)
 	}
 	return ""
 }
@@ -162,9 +163,28 @@ func (r *ChampionRegistry) GetIconURLByName(name string) string {
 	// Search for champion by IconID
 	for _, info := range r.champions {
 		if info.IconID == iconID {
-			return fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/%s/img/champion/%s.png", r.version, info.IconID)
+			return r.iconURL(info.IconID)
 		}
 	}
 	// Fallback: use the extracted name directly as the icon ID
+	return r.iconURL(iconID)
+}
+
+// PinVersion sets the Data Dragon version every generated URL is built from
+func (r *ChampionRegistry) PinVersion(version string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.version = version
+}
+
+// GetVersion returns the pinned Data Dragon version
+func (r *ChampionRegistry) GetVersion() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.version
+}
+
+// iconURL builds a champion icon URL from the pinned version (caller holds the lock)
+func (r *ChampionRegistry) iconURL(iconID string) string {
 	return fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/%s/img/champion/%s.png", r.version, iconID)
 }
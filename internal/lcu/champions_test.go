@@ -0,0 +1,33 @@
+package lcu
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChampionRegistry_URLsUsePinnedVersion(t *testing.T) {
+	r := NewChampionRegistry()
+	r.champions[103] = ChampionInfo{Name: "Ahri", IconID: "Ahri"}
+	r.PinVersion("15.24.1")
+
+	if got := r.GetVersion(); got != "15.24.1" {
+		t.Fatalf("GetVersion: got %q, want 15.24.1", got)
+	}
+
+	urls := func() []string {
+		return []string{r.GetIconURL(103), r.GetSplashURL(103), r.GetIconURLByName("Ahri"), r.GetIconURLByName("game_character_displayname_Zed")}
+	}
+	before := urls()
+	for _, u := range before {
+		if !strings.Contains(u, "15.24.1") {
+			t.Errorf("URL %q does not embed the pinned version", u)
+		}
+	}
+
+	r.PinVersion("15.25.1")
+	for i, u := range urls() {
+		if u == before[i] || !strings.Contains(u, "15.25.1") {
+			t.Errorf("URL %q did not follow the new pin (was %q)", u, before[i])
+		}
+	}
+}
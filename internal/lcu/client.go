@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -29,27 +30,43 @@ type Credentials struct {
 	Protocol    string
 }
 
+// HTTPDoer sends HTTP requests. *http.Client satisfies it; tests can supply a stub.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
 // Client represents a connection to the League Client
 type Client struct {
 	credentials *Credentials
-	httpClient  *http.Client
+	httpClient  HTTPDoer
 	wsConn      *websocket.Conn
 	baseURL     string
 	authHeader  string
+
+	// Connection state and reconnect backoff, see connection.go
+	connMu      sync.Mutex
+	state       ConnectionState
+	onState     ConnectionHandler
+	connector   func() error // Connect attempt (nil means Connect)
+	backoff     time.Duration
+	nextAttempt time.Time
 }
 
 // NewClient creates a new LCU client
 func NewClient() *Client {
-	return &Client{
-		httpClient: &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: true, // LCU uses self-signed cert
-				},
+	return NewClientWithDoer(&http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true, // LCU uses self-signed cert
 			},
-			Timeout: 2 * time.Second, // Short timeout for quick disconnect detection
 		},
-	}
+		Timeout: 2 * time.Second, // Short timeout for quick disconnect detection
+	})
+}
+
+// NewClientWithDoer creates an LCU client that sends requests through doer
+func NewClientWithDoer(doer HTTPDoer) *Client {
+	return &Client{httpClient: doer, state: StateDisconnected}
 }
 
 // FindLockfile searches for the League Client lockfile
@@ -77,6 +94,12 @@ func FindLockfile() (string, error) {
 	return "", ErrLockfileNotFound
 }
 
+// HasLockfile reports whether a League Client lockfile exists on disk
+func (c *Client) HasLockfile() bool {
+	_, err := FindLockfile()
+	return err == nil
+}
+
 // ParseLockfile reads and parses the lockfile content
 func ParseLockfile(path string) (*Credentials, error) {
 	content, err := os.ReadFile(path)
@@ -111,9 +134,7 @@ func (c *Client) Connect() error {
 		return err
 	}
 
-	c.credentials = creds
-	c.baseURL = fmt.Sprintf("https://127.0.0.1:%s", creds.Port)
-	c.authHeader = "Basic " + base64.StdEncoding.EncodeToString([]byte("riot:"+creds.Password))
+	c.setCredentials(creds)
 
 	// Test connection
 	if err := c.testConnection(); err != nil {
@@ -123,6 +144,13 @@ func (c *Client) Connect() error {
 	return nil
 }
 
+// setCredentials points the client at the LCU instance described by creds
+func (c *Client) setCredentials(creds *Credentials) {
+	c.credentials = creds
+	c.baseURL = fmt.Sprintf("https://127.0.0.1:%s", creds.Port)
+	c.authHeader = "Basic " + base64.StdEncoding.EncodeToString([]byte("riot:"+creds.Password))
+}
+
 // testConnection verifies we can reach the LCU API
 func (c *Client) testConnection() error {
 	req, err := http.NewRequest("GET", c.baseURL+"/lol-summoner/v1/current-summoner", nil)
@@ -0,0 +1,64 @@
+package lcu
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// stubDoer answers requests from a path -> body map
+type stubDoer struct {
+	responses map[string]string
+	requests  []*http.Request
+}
+
+func (s *stubDoer) Do(req *http.Request) (*http.Response, error) {
+	s.requests = append(s.requests, req)
+	body, ok := s.responses[req.URL.Path]
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusNotFound
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func TestFetchMatchHistory_WithStubDoer(t *testing.T) {
+	doer := &stubDoer{responses: map[string]string{
+		"/lol-match-history/v1/products/lol/current-summoner/matches": matchHistoryFixture,
+	}}
+	client := NewClientWithDoer(doer)
+	client.setCredentials(&Credentials{Port: "12345", Password: "secret"})
+
+	history, err := client.FetchMatchHistory(20)
+	if err != nil {
+		t.Fatalf("FetchMatchHistory: %v", err)
+	}
+	if len(history.Games.Games) != 1 || len(history.Games.Games[0].Participants) != 2 {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+
+	if len(doer.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(doer.requests))
+	}
+	req := doer.requests[0]
+	if req.URL.Host != "127.0.0.1:12345" || req.URL.Query().Get("endIndex") != "20" {
+		t.Errorf("unexpected request URL: %s", req.URL)
+	}
+	if req.Header.Get("Authorization") != "Basic cmlvdDpzZWNyZXQ=" {
+		t.Errorf("missing auth header: %q", req.Header.Get("Authorization"))
+	}
+}
+
+func TestFetchMatchHistory_StubErrorStatus(t *testing.T) {
+	client := NewClientWithDoer(&stubDoer{})
+	client.setCredentials(&Credentials{Port: "12345", Password: "secret"})
+
+	if _, err := client.FetchMatchHistory(20); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
@@ -0,0 +1,100 @@
+package lcu
+
+import (
+	"time"
+)
+
+// ConnectionState is where the client is in the LCU connect/reconnect cycle
+type ConnectionState string
+
+const (
+	StateDisconnected ConnectionState = "disconnected"
+	StateConnecting   ConnectionState = "connecting"
+	StateConnected    ConnectionState = "connected"
+)
+
+// Backoff between failed connection attempts doubles from min up to max
+const (
+	minReconnectBackoff = 2 * time.Second
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// ConnectionHandler is called on every connection state change
+type ConnectionHandler func(from, to ConnectionState)
+
+// SetConnectionHandler sets the callback for connection state changes
+func (c *Client) SetConnectionHandler(handler ConnectionHandler) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	c.onState = handler
+}
+
+// State returns the current connection state
+func (c *Client) State() ConnectionState {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	return c.state
+}
+
+// MaintainConnection checks the connection and, if it is down and the backoff has
+// elapsed, tries to reconnect. Call it periodically; it reports whether the client
+// is connected afterwards.
+func (c *Client) MaintainConnection(now time.Time) bool {
+	if c.IsConnected() {
+		c.setState(StateConnected)
+		return true
+	}
+	c.setState(StateDisconnected)
+
+	c.connMu.Lock()
+	waiting := now.Before(c.nextAttempt)
+	c.connMu.Unlock()
+	if waiting {
+		return false
+	}
+
+	c.setState(StateConnecting)
+	connect := c.connector
+	if connect == nil {
+		connect = c.Connect
+	}
+	if err := connect(); err != nil {
+		c.connMu.Lock()
+		c.backoff = nextBackoff(c.backoff)
+		c.nextAttempt = now.Add(c.backoff)
+		c.connMu.Unlock()
+		c.setState(StateDisconnected)
+		return false
+	}
+
+	c.connMu.Lock()
+	c.backoff = 0
+	c.nextAttempt = time.Time{}
+	c.connMu.Unlock()
+	c.setState(StateConnected)
+	return true
+}
+
+// nextBackoff doubles the previous wait, starting at the minimum and capped at the maximum
+func nextBackoff(previous time.Duration) time.Duration {
+	if previous < minReconnectBackoff {
+		return minReconnectBackoff
+	}
+	if next := previous * 2; next < maxReconnectBackoff {
+		return next
+	}
+	return maxReconnectBackoff
+}
+
+// setState records the new state and notifies the handler if it changed
+func (c *Client) setState(state ConnectionState) {
+	c.connMu.Lock()
+	from := c.state
+	handler := c.onState
+	c.state = state
+	c.connMu.Unlock()
+
+	if from != state && handler != nil {
+		handler(from, state)
+	}
+}
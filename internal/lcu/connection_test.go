@@ -0,0 +1,86 @@
+package lcu
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintainConnection_ReconnectsWithBackoff(t *testing.T) {
+	doer := &stubDoer{responses: map[string]string{"/lol-summoner/v1/current-summoner": `{}`}}
+	client := NewClientWithDoer(doer)
+
+	var transitions []string
+	client.SetConnectionHandler(func(from, to ConnectionState) {
+		transitions = append(transitions, string(to))
+	})
+
+	// Connector fails while League is closed, then succeeds
+	leagueUp := true
+	client.connector = func() error {
+		if !leagueUp {
+			return errors.New("league client is not running")
+		}
+		client.setCredentials(&Credentials{Port: "12345", Password: "secret"})
+		return nil
+	}
+
+	now := time.Unix(0, 0)
+	if !client.MaintainConnection(now) {
+		t.Fatal("first attempt should connect")
+	}
+
+	// League closes: the health check fails and reconnects keep failing
+	leagueUp = false
+	delete(doer.responses, "/lol-summoner/v1/current-summoner")
+
+	var backoffs []time.Duration
+	for i := 0; i < 3; i++ {
+		if client.MaintainConnection(now) {
+			t.Fatal("should not connect while League is closed")
+		}
+		backoffs = append(backoffs, client.backoff)
+
+		// Polls before the backoff elapses don't retry
+		attempts := len(transitions)
+		client.MaintainConnection(now.Add(client.backoff - time.Millisecond))
+		if len(transitions) != attempts {
+			t.Fatalf("retried before backoff elapsed: %v", transitions)
+		}
+		now = now.Add(client.backoff)
+	}
+	if backoffs[0] != minReconnectBackoff || backoffs[1] <= backoffs[0] || backoffs[2] <= backoffs[1] {
+		t.Errorf("backoff should grow between failures, got %v", backoffs)
+	}
+
+	// League is back
+	leagueUp = true
+	doer.responses["/lol-summoner/v1/current-summoner"] = `{}`
+	if !client.MaintainConnection(now) {
+		t.Fatal("should reconnect once League is back")
+	}
+	if client.backoff != 0 || client.State() != StateConnected {
+		t.Errorf("backoff %v and state %s not reset after connecting", client.backoff, client.State())
+	}
+
+	want := strings.Join([]string{
+		"connecting", "connected",
+		"disconnected", "connecting", "disconnected",
+		"connecting", "disconnected",
+		"connecting", "disconnected",
+		"connecting", "connected",
+	}, ",")
+	if got := strings.Join(transitions, ","); got != want {
+		t.Errorf("transitions:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestNextBackoff_Capped(t *testing.T) {
+	if got := nextBackoff(0); got != minReconnectBackoff {
+		t.Errorf("nextBackoff(0) = %v, want %v", got, minReconnectBackoff)
+	}
+	if got := nextBackoff(maxReconnectBackoff); got != maxReconnectBackoff {
+		t.Errorf("nextBackoff(max) = %v, want %v", got, maxReconnectBackoff)
+	}
+}
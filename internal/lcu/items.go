@@ -3,23 +3,44 @@ package lcu
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 )
 
 // ItemData holds item information
 type ItemData struct {
-	Name string `json:"name"`
-	Gold struct {
-		Total int `json:"total"`
+	Name        string `json:"name"`
+	Description string `json:"description"` // HTML-ish markup with stats and passives
+	Plaintext   string `json:"plaintext"`   // one-line summary
+	Gold        struct {
+		Total       int  `json:"total"`
+		Purchasable bool `json:"purchasable"`
 	} `json:"gold"`
+	Into []string        `json:"into"` // items this one builds into
+	Maps map[string]bool `json:"maps"` // map ID -> available; "11" is Summoner's Rift
 }
 
-// ItemInfo holds item name and gold cost
+// ItemInfo holds item name, gold cost and tooltip text
 type ItemInfo struct {
-	Name string
-	Gold int
+	Name        string
+	Gold        int
+	Plaintext   string
+	Description string // Description with markup stripped
+	Completed   bool   // a finished Summoner's Rift item rather than a component or consumable
+}
+
+// ItemTooltip is the text shown when hovering an item
+type ItemTooltip struct {
+	ItemID      int    `json:"itemId"`
+	Name        string `json:"name"`
+	Plaintext   string `json:"plaintext"`
+	Description string `json:"description"`
+	Cost        int    `json:"cost"`
+	Known       bool   `json:"known"` // false when the item isn't in the loaded data
 }
 
 // ItemRegistry holds item ID to name mapping
@@ -70,26 +91,82 @@ func (r *ItemRegistry) Load() error {
 	}
 	defer itemResp.Body.Close()
 
+	items, err := parseItems(itemResp.Body)
+	if err != nil {
+		return err
+	}
+	r.items = items
+
+	r.loaded = true
+	fmt.Printf("Loaded %d items from Data Dragon (v%s)\n", len(r.items), r.version)
+	return nil
+}
+
+// parseItems decodes a Data Dragon item.json into ID -> ItemInfo
+func parseItems(r io.Reader) (map[int]ItemInfo, error) {
 	var itemData struct {
 		Data map[string]ItemData `json:"data"`
 	}
-	if err := json.NewDecoder(itemResp.Body).Decode(&itemData); err != nil {
-		return fmt.Errorf("failed to parse items: %w", err)
+	if err := json.NewDecoder(r).Decode(&itemData); err != nil {
+		return nil, fmt.Errorf("failed to parse items: %w", err)
 	}
 
-	// Build ID -> ItemInfo map
+	items := make(map[int]ItemInfo, len(itemData.Data))
 	for idStr, item := range itemData.Data {
 		var id int
 		fmt.Sscanf(idStr, "%d", &id)
-		r.items[id] = ItemInfo{
-			Name: item.Name,
-			Gold: item.Gold.Total,
+		items[id] = ItemInfo{
+			Name:        item.Name,
+			Gold:        item.Gold.Total,
+			Plaintext:   item.Plaintext,
+			Description: stripItemMarkup(item.Description),
+			Completed:   isCompletedItemData(item),
 		}
 	}
+	return items, nil
+}
 
-	r.loaded = true
-	fmt.Printf("Loaded %d items from Data Dragon (v%s)\n", len(r.items), r.version)
-	return nil
+// isCompletedItemData applies the rule the reducer uses to pick completed items: sold on
+// Summoner's Rift, builds into nothing, and costs at least 1000 gold (which leaves out
+// consumables and starters)
+func isCompletedItemData(item ItemData) bool {
+	if available, ok := item.Maps["11"]; ok && !available {
+		return false
+	}
+	return len(item.Into) == 0 && item.Gold.Purchasable && item.Gold.Total >= 1000
+}
+
+var (
+	itemLineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+	itemTag       = regexp.MustCompile(`<[^>]*>`)
+	blankLines    = regexp.MustCompile(`\n{3,}`)
+)
+
+// stripItemMarkup turns a Data Dragon item description into plain text, keeping line breaks
+func stripItemMarkup(description string) string {
+	text := itemLineBreak.ReplaceAllString(description, "\n")
+	text = itemTag.ReplaceAllString(text, "")
+	text = blankLines.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// GetTooltip returns the item's tooltip text, or a name-only fallback for unknown items
+func (r *ItemRegistry) GetTooltip(id int) ItemTooltip {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	info, ok := r.items[id]
+	if !ok {
+		return ItemTooltip{ItemID: id, Name: fmt.Sprintf("Item %d", id)}
+	}
+	return ItemTooltip{
+		ItemID:      id,
+		Name:        info.Name,
+		Plaintext:   info.Plaintext,
+		Description: info.Description,
+		Cost:        info.Gold,
+		Known:       true,
+	}
 }
 
 // GetName returns the item name for a given ID
@@ -114,6 +191,13 @@ func (r *ItemRegistry) GetGold(id int) int {
 	return 0
 }
 
+// IsCompleted reports whether an item is a finished item; unknown IDs are not
+func (r *ItemRegistry) IsCompleted(id int) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.items[id].Completed
+}
+
 // GetVersion returns the loaded version
 func (r *ItemRegistry) GetVersion() string {
 	r.mu.RLock()
@@ -0,0 +1,71 @@
+package lcu
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleItemJSON = `{
+	"type": "item",
+	"data": {
+		"3089": {
+			"name": "Rabadon's Deathcap",
+			"description": "<mainText><stats><attention>130</attention> Ability Power</stats><br><br><passive>Magical Opus</passive><br>Increases your total <scaleAP>Ability Power by 30%</scaleAP>.</mainText>",
+			"plaintext": "Hugely increases Ability Power",
+			"gold": {"base": 1100, "total": 3600}
+		}
+	}
+}`
+
+func TestItemRegistry_GetTooltip(t *testing.T) {
+	items, err := parseItems(strings.NewReader(sampleItemJSON))
+	if err != nil {
+		t.Fatalf("parseItems: %v", err)
+	}
+	r := NewItemRegistry()
+	r.items = items
+
+	tip := r.GetTooltip(3089)
+	if !tip.Known || tip.Name != "Rabadon's Deathcap" || tip.Cost != 3600 {
+		t.Errorf("got %+v", tip)
+	}
+	if tip.Plaintext != "Hugely increases Ability Power" {
+		t.Errorf("Plaintext = %q", tip.Plaintext)
+	}
+	want := "130 Ability Power\n\nMagical Opus\nIncreases your total Ability Power by 30%."
+	if tip.Description != want {
+		t.Errorf("Description = %q, want %q", tip.Description, want)
+	}
+
+	unknown := r.GetTooltip(9999)
+	if unknown.Known || unknown.Name != "Item 9999" || unknown.Description != "" || unknown.Cost != 0 {
+		t.Errorf("unknown item fallback: got %+v", unknown)
+	}
+}
+
+func TestParseItems_Completed(t *testing.T) {
+	const itemJSON = `{"data": {
+		"3089": {"name": "Rabadon's Deathcap", "gold": {"total": 3600, "purchasable": true}},
+		"3108": {"name": "Fiendish Codex", "gold": {"total": 900, "purchasable": true}, "into": ["3152"]},
+		"3057": {"name": "Sheen", "gold": {"total": 900, "purchasable": true}, "into": ["3078", "3100"]},
+		"2003": {"name": "Health Potion", "gold": {"total": 50, "purchasable": true}},
+		"3340": {"name": "Stealth Ward", "gold": {"total": 0, "purchasable": true}},
+		"3513": {"name": "Eye of the Herald", "gold": {"total": 0, "purchasable": false}},
+		"3112": {"name": "Guardian's Orb", "gold": {"total": 1000, "purchasable": true}, "maps": {"11": false, "12": true}}
+	}}`
+	items, err := parseItems(strings.NewReader(itemJSON))
+	if err != nil {
+		t.Fatalf("parseItems: %v", err)
+	}
+	r := NewItemRegistry()
+	r.items = items
+
+	if !r.IsCompleted(3089) {
+		t.Error("Rabadon's Deathcap should be completed")
+	}
+	for _, id := range []int{3108, 3057, 2003, 3340, 3513, 3112, 9999} {
+		if r.IsCompleted(id) {
+			t.Errorf("item %d (%s) should not be completed", id, r.GetName(id))
+		}
+	}
+}
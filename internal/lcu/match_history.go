@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+
+	"ghostdraft/internal/roles"
 )
 
 // MatchHistoryResponse represents the LCU match history response
@@ -15,20 +18,30 @@ type MatchHistoryResponse struct {
 
 // MatchGame represents a single game in match history
 type MatchGame struct {
-	GameId       int64             `json:"gameId"`
-	GameCreation int64             `json:"gameCreation"`
-	GameDuration int               `json:"gameDuration"`
-	QueueId      int               `json:"queueId"`
-	GameMode     string            `json:"gameMode"`
-	GameType     string            `json:"gameType"`
-	Participants []MatchParticipant `json:"participants"`
+	GameId                int64                 `json:"gameId"`
+	GameCreation          int64                 `json:"gameCreation"`
+	GameDuration          int                   `json:"gameDuration"`
+	QueueId               int                   `json:"queueId"`
+	GameMode              string                `json:"gameMode"`
+	GameType              string                `json:"gameType"`
+	Participants          []MatchParticipant    `json:"participants"`
+	ParticipantIdentities []ParticipantIdentity `json:"participantIdentities"`
+}
+
+// ParticipantIdentity links a participant ID to a player
+type ParticipantIdentity struct {
+	ParticipantId int `json:"participantId"`
+	Player        struct {
+		PUUID string `json:"puuid"`
+	} `json:"player"`
 }
 
 // MatchParticipant represents a participant in a match
 type MatchParticipant struct {
-	ChampionId int                `json:"championId"`
-	Stats      ParticipantStats   `json:"stats"`
-	Timeline   ParticipantTimeline `json:"timeline"`
+	ParticipantId int                 `json:"participantId"`
+	ChampionId    int                 `json:"championId"`
+	Stats         ParticipantStats    `json:"stats"`
+	Timeline      ParticipantTimeline `json:"timeline"`
 }
 
 // ParticipantTimeline contains role/lane info
@@ -95,25 +108,7 @@ var roleIconURLs = map[string]string{
 
 // normalizeRole converts LCU lane/role to a standard role name
 func normalizeRole(lane, role string) string {
-	switch lane {
-	case "TOP":
-		return "TOP"
-	case "JUNGLE":
-		return "JUNGLE"
-	case "MIDDLE", "MID":
-		return "MID"
-	case "BOTTOM":
-		if role == "DUO_CARRY" || role == "CARRY" {
-			return "ADC"
-		}
-		return "SUPPORT"
-	default:
-		// Fallback based on role
-		if role == "DUO_SUPPORT" || role == "SUPPORT" {
-			return "SUPPORT"
-		}
-		return "MID" // Default fallback
-	}
+	return roles.FromLCULaneRole(lane, role).Label()
 }
 
 // FetchMatchHistory fetches match history from the LCU
@@ -144,8 +139,32 @@ func (c *Client) FetchMatchHistory(count int) (*MatchHistoryResponse, error) {
 	return &history, nil
 }
 
-// CalculatePersonalStats calculates aggregated stats from match history
-func CalculatePersonalStats(history *MatchHistoryResponse, champRegistry *ChampionRegistry) *PersonalStats {
+// findPlayer returns the participant for puuid. If puuid is unknown or the game has no
+// identities, the first participant is used (the LCU lists the current player first).
+func findPlayer(game MatchGame, puuid string) (MatchParticipant, bool) {
+	if len(game.Participants) == 0 {
+		return MatchParticipant{}, false
+	}
+	if puuid == "" || len(game.ParticipantIdentities) == 0 {
+		return game.Participants[0], true
+	}
+
+	for _, identity := range game.ParticipantIdentities {
+		if identity.Player.PUUID != puuid {
+			continue
+		}
+		for _, p := range game.Participants {
+			if p.ParticipantId == identity.ParticipantId {
+				return p, true
+			}
+		}
+	}
+	return MatchParticipant{}, false
+}
+
+// CalculatePersonalStats calculates aggregated stats from match history.
+// puuid identifies the current player; pass "" to fall back to the first participant.
+func CalculatePersonalStats(history *MatchHistoryResponse, champRegistry *ChampionRegistry, puuid string) *PersonalStats {
 	stats := &PersonalStats{
 		HasData:       false,
 		ChampionStats: []ChampionPersonalStats{},
@@ -167,12 +186,10 @@ func CalculatePersonalStats(history *MatchHistoryResponse, champRegistry *Champi
 			continue
 		}
 
-		// The first participant is always the current player in LCU match history
-		if len(game.Participants) == 0 {
+		p, ok := findPlayer(game, puuid)
+		if !ok {
 			continue
 		}
-
-		p := game.Participants[0]
 		s := p.Stats
 
 		stats.TotalGames++
@@ -298,3 +315,38 @@ func CalculatePersonalStats(history *MatchHistoryResponse, champRegistry *Champi
 
 	return stats
 }
+
+// CurrentStreak returns the player's current ranked win/loss streak, counted back
+// from the most recent game: positive for wins, negative for losses, 0 if no games.
+func CurrentStreak(history *MatchHistoryResponse, puuid string) int {
+	if history == nil {
+		return 0
+	}
+
+	games := make([]MatchGame, 0, len(history.Games.Games))
+	for _, game := range history.Games.Games {
+		if game.QueueId == 420 || game.QueueId == 440 {
+			games = append(games, game)
+		}
+	}
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i].GameCreation > games[j].GameCreation
+	})
+
+	streak := 0
+	for _, game := range games {
+		p, ok := findPlayer(game, puuid)
+		if !ok {
+			continue
+		}
+		if streak != 0 && (streak > 0) != p.Stats.Win {
+			return streak
+		}
+		if p.Stats.Win {
+			streak++
+		} else {
+			streak--
+		}
+	}
+	return streak
+}
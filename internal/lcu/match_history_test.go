@@ -0,0 +1,79 @@
+package lcu
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Player is listed second; stats must come from the participant matching the puuid
+const matchHistoryFixture = `{
+	"games": {
+		"games": [{
+			"gameId": 1,
+			"gameDuration": 1800,
+			"queueId": 420,
+			"participantIdentities": [
+				{"participantId": 1, "player": {"puuid": "someone-else"}},
+				{"participantId": 2, "player": {"puuid": "me"}}
+			],
+			"participants": [
+				{"participantId": 1, "championId": 238, "stats": {"win": false, "kills": 1, "deaths": 9, "assists": 0}},
+				{"participantId": 2, "championId": 103, "stats": {"win": true, "kills": 10, "deaths": 2, "assists": 5}}
+			]
+		}]
+	}
+}`
+
+func TestCalculatePersonalStats_MatchesByPUUID(t *testing.T) {
+	var history MatchHistoryResponse
+	if err := json.Unmarshal([]byte(matchHistoryFixture), &history); err != nil {
+		t.Fatalf("unmarshal fixture: %v", err)
+	}
+
+	stats := CalculatePersonalStats(&history, nil, "me")
+	if !stats.HasData || stats.TotalGames != 1 {
+		t.Fatalf("expected 1 game, got %+v", stats)
+	}
+	if stats.Wins != 1 || stats.AvgKills != 10 {
+		t.Errorf("expected stats for the puuid's participant, got wins=%d kills=%.0f", stats.Wins, stats.AvgKills)
+	}
+	if len(stats.ChampionStats) != 1 || stats.ChampionStats[0].ChampionId != 103 {
+		t.Errorf("expected champion 103, got %+v", stats.ChampionStats)
+	}
+
+	// Without a puuid, the first participant is used
+	fallback := CalculatePersonalStats(&history, nil, "")
+	if fallback.ChampionStats[0].ChampionId != 238 {
+		t.Errorf("fallback champion = %d, want 238", fallback.ChampionStats[0].ChampionId)
+	}
+
+	// An unknown puuid skips the game instead of guessing
+	unknown := CalculatePersonalStats(&history, nil, "stranger")
+	if unknown.HasData {
+		t.Errorf("expected no data for unknown puuid, got %+v", unknown)
+	}
+}
+
+func TestCurrentStreak_CountsFromMostRecentRankedGame(t *testing.T) {
+	game := func(id int64, created int64, queue int, win bool) MatchGame {
+		g := MatchGame{GameId: id, GameCreation: created, QueueId: queue}
+		g.Participants = []MatchParticipant{{ParticipantId: 1, Stats: ParticipantStats{Win: win}}}
+		return g
+	}
+
+	var history MatchHistoryResponse
+	history.Games.Games = []MatchGame{
+		game(1, 100, 420, true),
+		game(2, 400, 420, false), // most recent
+		game(3, 300, 440, false),
+		game(4, 350, 450, true), // ARAM, ignored
+		game(5, 200, 420, false),
+	}
+
+	if got := CurrentStreak(&history, ""); got != -3 {
+		t.Errorf("CurrentStreak = %d, want -3", got)
+	}
+	if got := CurrentStreak(nil, ""); got != 0 {
+		t.Errorf("CurrentStreak(nil) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,86 @@
+package lcu
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MatchTimeline represents the LCU game timeline response
+type MatchTimeline struct {
+	Frames []TimelineFrame `json:"frames"`
+}
+
+// TimelineFrame is one (roughly per-minute) snapshot of a game
+type TimelineFrame struct {
+	Timestamp int64           `json:"timestamp"` // milliseconds since game start
+	Events    []TimelineEvent `json:"events"`
+}
+
+// TimelineEvent is a single in-game event within a frame
+type TimelineEvent struct {
+	Type          string `json:"type"`
+	Timestamp     int64  `json:"timestamp"` // milliseconds since game start
+	ParticipantId int    `json:"participantId"`
+	ItemId        int    `json:"itemId"`
+}
+
+// ItemPurchase records when a participant bought an item
+type ItemPurchase struct {
+	ItemID int           `json:"itemId"`
+	Time   time.Duration `json:"time"`
+}
+
+// FetchMatchTimeline fetches the event timeline for a game from the LCU
+func (c *Client) FetchMatchTimeline(gameId int64) (*MatchTimeline, error) {
+	endpoint := fmt.Sprintf("/lol-match-history/v1/game-timelines/%d", gameId)
+
+	resp, err := c.Get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch match timeline: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("match timeline request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var timeline MatchTimeline
+	if err := json.NewDecoder(resp.Body).Decode(&timeline); err != nil {
+		return nil, fmt.Errorf("failed to parse match timeline: %w", err)
+	}
+
+	return &timeline, nil
+}
+
+// ItemPurchases returns a participant's ITEM_PURCHASED events in timeline order
+func (t *MatchTimeline) ItemPurchases(participantId int) []ItemPurchase {
+	var purchases []ItemPurchase
+	if t == nil {
+		return purchases
+	}
+
+	for _, frame := range t.Frames {
+		for _, event := range frame.Events {
+			if event.Type != "ITEM_PURCHASED" || event.ParticipantId != participantId || event.ItemId == 0 {
+				continue
+			}
+			purchases = append(purchases, ItemPurchase{
+				ItemID: event.ItemId,
+				Time:   time.Duration(event.Timestamp) * time.Millisecond,
+			})
+		}
+	}
+	return purchases
+}
+
+// PlayerItemPurchases returns the item purchases for the player identified by puuid in game
+func PlayerItemPurchases(game MatchGame, timeline *MatchTimeline, puuid string) []ItemPurchase {
+	player, ok := findPlayer(game, puuid)
+	if !ok {
+		return nil
+	}
+	return timeline.ItemPurchases(player.ParticipantId)
+}
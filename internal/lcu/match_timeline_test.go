@@ -0,0 +1,47 @@
+package lcu
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+const matchTimelineFixture = `{
+	"frames": [
+		{"timestamp": 0, "events": [
+			{"type": "ITEM_PURCHASED", "timestamp": 1200, "participantId": 2, "itemId": 1056},
+			{"type": "ITEM_PURCHASED", "timestamp": 1500, "participantId": 1, "itemId": 1055}
+		]},
+		{"timestamp": 60000, "events": [
+			{"type": "CHAMPION_KILL", "timestamp": 300000, "participantId": 0},
+			{"type": "ITEM_SOLD", "timestamp": 540000, "participantId": 2, "itemId": 1056},
+			{"type": "ITEM_PURCHASED", "timestamp": 540500, "participantId": 2, "itemId": 6655}
+		]}
+	]
+}`
+
+func TestPlayerItemPurchases_FromTimeline(t *testing.T) {
+	var history MatchHistoryResponse
+	if err := json.Unmarshal([]byte(matchHistoryFixture), &history); err != nil {
+		t.Fatalf("unmarshal history fixture: %v", err)
+	}
+	var timeline MatchTimeline
+	if err := json.Unmarshal([]byte(matchTimelineFixture), &timeline); err != nil {
+		t.Fatalf("unmarshal timeline fixture: %v", err)
+	}
+
+	purchases := PlayerItemPurchases(history.Games.Games[0], &timeline, "me")
+
+	want := []ItemPurchase{
+		{ItemID: 1056, Time: 1200 * time.Millisecond},
+		{ItemID: 6655, Time: 540500 * time.Millisecond},
+	}
+	if len(purchases) != len(want) {
+		t.Fatalf("got %d purchases, want %d: %+v", len(purchases), len(want), purchases)
+	}
+	for i := range want {
+		if purchases[i] != want[i] {
+			t.Errorf("purchase %d: got %+v, want %+v", i, purchases[i], want[i])
+		}
+	}
+}
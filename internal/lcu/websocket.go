@@ -59,6 +59,27 @@ func (p *ChampSelectPlayer) GetPosition() string {
 	return ""
 }
 
+// UnavailableChampions returns champions that can no longer be picked or banned:
+// completed bans plus every pick except the local player's own
+func (s *ChampSelectSession) UnavailableChampions() map[int]bool {
+	unavailable := make(map[int]bool)
+	for _, group := range s.Actions {
+		for _, action := range group {
+			if action.Type == "ban" && action.Completed && action.ChampionID > 0 {
+				unavailable[action.ChampionID] = true
+			}
+		}
+	}
+	for _, team := range [][]ChampSelectPlayer{s.MyTeam, s.TheirTeam} {
+		for _, player := range team {
+			if player.ChampionID > 0 && player.CellID != s.LocalPlayerCellID {
+				unavailable[player.ChampionID] = true
+			}
+		}
+	}
+	return unavailable
+}
+
 type ChampSelectAction struct {
 	ID          int  `json:"id"`
 	ActorCellID int  `json:"actorCellId"`
@@ -0,0 +1,33 @@
+package lcu
+
+import "testing"
+
+func TestChampSelectSession_UnavailableChampions(t *testing.T) {
+	session := &ChampSelectSession{
+		LocalPlayerCellID: 0,
+		MyTeam: []ChampSelectPlayer{
+			{CellID: 0, ChampionID: 103}, // local player's own pick stays available
+			{CellID: 1, ChampionID: 64},
+			{CellID: 2, ChampionID: 0},
+		},
+		TheirTeam: []ChampSelectPlayer{{CellID: 5, ChampionID: 238}},
+		Actions: [][]ChampSelectAction{{
+			{Type: "ban", ChampionID: 157, Completed: true},
+			{Type: "ban", ChampionID: 91, Completed: false}, // only hovered
+			{Type: "pick", ChampionID: 103, ActorCellID: 0},
+		}},
+	}
+
+	got := session.UnavailableChampions()
+
+	for _, id := range []int{64, 238, 157} {
+		if !got[id] {
+			t.Errorf("champion %d should be unavailable", id)
+		}
+	}
+	for _, id := range []int{103, 91, 0} {
+		if got[id] {
+			t.Errorf("champion %d should still be available", id)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package roles
+
+import "strings"
+
+// Role is the canonical lane identifier used throughout the app.
+// Its value doubles as the frontend/meta role key ("top", "middle", ...).
+type Role string
+
+const (
+	Unknown Role = ""
+	Top     Role = "top"
+	Jungle  Role = "jungle"
+	Middle  Role = "middle"
+	Bottom  Role = "bottom"
+	Utility Role = "utility"
+)
+
+// All lists every role in lane order
+var All = []Role{Top, Jungle, Middle, Bottom, Utility}
+
+// Parse accepts any role spelling we encounter (app keys, DB team_position,
+// LCU assignedPosition, match-history labels) and returns the canonical role
+func Parse(s string) Role {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "TOP":
+		return Top
+	case "JUNGLE":
+		return Jungle
+	case "MIDDLE", "MID":
+		return Middle
+	case "BOTTOM", "ADC":
+		return Bottom
+	case "UTILITY", "SUPPORT":
+		return Utility
+	default:
+		return Unknown
+	}
+}
+
+// FromLCULaneRole maps the legacy match-history timeline lane/role pair to a role.
+// Bottom lane is split by role; anything unrecognised falls back to middle.
+func FromLCULaneRole(lane, role string) Role {
+	switch lane {
+	case "TOP":
+		return Top
+	case "JUNGLE":
+		return Jungle
+	case "MIDDLE", "MID":
+		return Middle
+	case "BOTTOM":
+		if role == "DUO_CARRY" || role == "CARRY" {
+			return Bottom
+		}
+		return Utility
+	default:
+		if role == "DUO_SUPPORT" || role == "SUPPORT" {
+			return Utility
+		}
+		return Middle
+	}
+}
+
+// Position returns the database team_position / LCU assignedPosition value
+func (r Role) Position() string {
+	if r == Unknown {
+		return ""
+	}
+	return strings.ToUpper(string(r))
+}
+
+// Label returns the short display label used in match history (TOP, MID, ADC...)
+func (r Role) Label() string {
+	switch r {
+	case Top:
+		return "TOP"
+	case Jungle:
+		return "JUNGLE"
+	case Middle:
+		return "MID"
+	case Bottom:
+		return "ADC"
+	case Utility:
+		return "SUPPORT"
+	default:
+		return ""
+	}
+}
+
+// LCULaneRole returns a timeline lane/role pair that FromLCULaneRole maps back to r
+func (r Role) LCULaneRole() (lane, role string) {
+	switch r {
+	case Top:
+		return "TOP", "SOLO"
+	case Jungle:
+		return "JUNGLE", "NONE"
+	case Middle:
+		return "MIDDLE", "SOLO"
+	case Bottom:
+		return "BOTTOM", "DUO_CARRY"
+	case Utility:
+		return "BOTTOM", "DUO_SUPPORT"
+	default:
+		return "", ""
+	}
+}
+
+// String returns the canonical key
+func (r Role) String() string {
+	return string(r)
+}
@@ -0,0 +1,77 @@
+package roles
+
+import "testing"
+
+func TestRole_RoundTrips(t *testing.T) {
+	tests := []struct {
+		role     Role
+		position string
+		label    string
+	}{
+		{Top, "TOP", "TOP"},
+		{Jungle, "JUNGLE", "JUNGLE"},
+		{Middle, "MIDDLE", "MID"},
+		{Bottom, "BOTTOM", "ADC"},
+		{Utility, "UTILITY", "SUPPORT"},
+	}
+
+	if len(tests) != len(All) {
+		t.Fatalf("table covers %d roles, All has %d", len(tests), len(All))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role.String(), func(t *testing.T) {
+			if got := tt.role.Position(); got != tt.position {
+				t.Errorf("Position() = %q, want %q", got, tt.position)
+			}
+			if got := Parse(tt.role.Position()); got != tt.role {
+				t.Errorf("Parse(Position()) = %q, want %q", got, tt.role)
+			}
+
+			if got := tt.role.Label(); got != tt.label {
+				t.Errorf("Label() = %q, want %q", got, tt.label)
+			}
+			if got := Parse(tt.role.Label()); got != tt.role {
+				t.Errorf("Parse(Label()) = %q, want %q", got, tt.role)
+			}
+
+			if got := Parse(tt.role.String()); got != tt.role {
+				t.Errorf("Parse(String()) = %q, want %q", got, tt.role)
+			}
+
+			lane, role := tt.role.LCULaneRole()
+			if got := FromLCULaneRole(lane, role); got != tt.role {
+				t.Errorf("FromLCULaneRole(%q, %q) = %q, want %q", lane, role, got, tt.role)
+			}
+		})
+	}
+}
+
+func TestParse_Unknown(t *testing.T) {
+	for _, s := range []string{"", "NONE", "invalid"} {
+		if got := Parse(s); got != Unknown {
+			t.Errorf("Parse(%q) = %q, want Unknown", s, got)
+		}
+	}
+	if Unknown.Position() != "" || Unknown.Label() != "" {
+		t.Error("Unknown should map to empty strings")
+	}
+}
+
+func TestFromLCULaneRole_Fallbacks(t *testing.T) {
+	tests := []struct {
+		lane, role string
+		want       Role
+	}{
+		{"BOTTOM", "CARRY", Bottom},
+		{"BOTTOM", "NONE", Utility},
+		{"NONE", "DUO_SUPPORT", Utility},
+		{"NONE", "SUPPORT", Utility},
+		{"NONE", "NONE", Middle},
+	}
+	for _, tt := range tests {
+		if got := FromLCULaneRole(tt.lane, tt.role); got != tt.want {
+			t.Errorf("FromLCULaneRole(%q, %q) = %q, want %q", tt.lane, tt.role, got, tt.want)
+		}
+	}
+}